@@ -0,0 +1,598 @@
+// Package boltdb keeps clients and trainings in a single bbolt file, so the
+// check-in kiosks keep working without a connection to the database server.
+//
+// Every entity is stored as JSON under its big-endian ID. Secondary indexes
+// are buckets of composite keys with empty values, so lookups by telephone,
+// training date and time and coach on a date are prefix or range scans:
+//
+//	clients_by_telephone           telephone                        -> client id
+//	trainings_by_date_time         unix nano | training id          -> nil
+//	trainings_by_coach_date_time   coach id | unix nano | training  -> nil
+//	clients_trainings              client id | training id          -> nil
+//	trainings_clients              training id | client id          -> nil
+package boltdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	trmcontext "github.com/avito-tech/go-transaction-manager/trm/v2/context"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/drivers"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	clientsBucket                  = []byte("clients")
+	clientsByTelephoneBucket       = []byte("clients_by_telephone")
+	trainingsBucket                = []byte("trainings")
+	trainingsByDateTimeBucket      = []byte("trainings_by_date_time")
+	trainingsByCoachDateTimeBucket = []byte("trainings_by_coach_date_time")
+	clientsTrainingsBucket         = []byte("clients_trainings")
+	trainingsClientsBucket         = []byte("trainings_clients")
+	buckets                        = [][]byte{
+		clientsBucket, clientsByTelephoneBucket, trainingsBucket, trainingsByDateTimeBucket,
+		trainingsByCoachDateTimeBucket, clientsTrainingsBucket, trainingsClientsBucket,
+	}
+)
+
+// Open opens the database file at path and creates the missing buckets.
+func Open(path string) (*bolt.DB, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range buckets {
+			_, err := tx.CreateBucketIfNotExists(name)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+type TrainingBolt struct {
+	Training           models.Training `json:"training"`
+	AvailablePlacesNum uint64          `json:"available_places_num"`
+}
+
+// Transaction wraps a writable bolt.Tx for the avito transaction manager.
+type Transaction struct {
+	tx       *bolt.Tx
+	isClosed *drivers.IsClosed
+}
+
+// NewFactory returns a trm.TrFactory for manager.New and manager.Must. The
+// repositories run inside the transaction found in the context and open
+// their own one otherwise.
+func NewFactory(db *bolt.DB) trm.TrFactory {
+	return func(ctx context.Context, _ trm.Settings) (context.Context, trm.Transaction, error) {
+		tx, err := db.Begin(true)
+		if err != nil {
+			return ctx, nil, err
+		}
+
+		return ctx, &Transaction{tx: tx, isClosed: drivers.NewIsClosed()}, nil
+	}
+}
+
+func (t *Transaction) Transaction() interface{} {
+	return t.tx
+}
+
+func (t *Transaction) Commit(_ context.Context) error {
+	defer t.isClosed.Close()
+
+	return t.tx.Commit()
+}
+
+func (t *Transaction) Rollback(_ context.Context) error {
+	defer t.isClosed.Close()
+
+	return t.tx.Rollback()
+}
+
+func (t *Transaction) IsActive() bool {
+	return t.isClosed.IsActive()
+}
+
+func (t *Transaction) Closed() <-chan struct{} {
+	return t.isClosed.Closed()
+}
+
+func update(ctx context.Context, db *bolt.DB, fn func(tx *bolt.Tx) error) error {
+	tr := trmcontext.DefaultManager.Default(ctx)
+	if tx, ok := transactionOf(tr, db); ok {
+		return fn(tx)
+	}
+
+	return db.Update(fn)
+}
+
+func view(ctx context.Context, db *bolt.DB, fn func(tx *bolt.Tx) error) error {
+	tr := trmcontext.DefaultManager.Default(ctx)
+	if tx, ok := transactionOf(tr, db); ok {
+		return fn(tx)
+	}
+
+	return db.View(fn)
+}
+
+func transactionOf(tr trm.Transaction, db *bolt.DB) (*bolt.Tx, bool) {
+	if tr == nil || !tr.IsActive() {
+		return nil, false
+	}
+
+	tx, ok := tr.Transaction().(*bolt.Tx)
+	if !ok || tx.DB() != db {
+		return nil, false
+	}
+
+	return tx, true
+}
+
+func itob(id uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	return b
+}
+
+func btoi(b []byte) uint64 {
+	return binary.BigEndian.Uint64(b)
+}
+
+func join(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func dateTimeKey(dateTime time.Time) []byte {
+	return itob(uint64(dateTime.UnixNano()))
+}
+
+func coachDateTimeKey(coachID uint64, dateTime time.Time) []byte {
+	return join(itob(coachID), dateTimeKey(dateTime))
+}
+
+// prefixIDs returns the IDs stored in the last 8 bytes of the keys that start
+// with prefix.
+func prefixIDs(b *bolt.Bucket, prefix []byte) []uint64 {
+	ids := []uint64{}
+	c := b.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		ids = append(ids, btoi(k[len(k)-8:]))
+	}
+
+	return ids
+}
+
+// rangeIDs returns the IDs stored in the last 8 bytes of the keys from
+// inclusive to to exclusive. Only the first len(to) bytes of a key are
+// compared with to.
+func rangeIDs(b *bolt.Bucket, from, to []byte) []uint64 {
+	ids := []uint64{}
+	c := b.Cursor()
+	for k, _ := c.Seek(from); k != nil && bytes.Compare(k[:len(to)], to) < 0; k, _ = c.Next() {
+		ids = append(ids, btoi(k[len(k)-8:]))
+	}
+
+	return ids
+}
+
+type ClientBoltRepository struct {
+	db *bolt.DB
+}
+
+func NewClientBoltRepository(db *bolt.DB) repositories.ClientRepository {
+	return &ClientBoltRepository{db: db}
+}
+
+func (c *ClientBoltRepository) Create(ctx context.Context, client *models.Client) error {
+	return update(ctx, c.db, func(tx *bolt.Tx) error {
+		byTelephone := tx.Bucket(clientsByTelephoneBucket)
+		if byTelephone.Get([]byte(client.Telephone)) != nil {
+			return extRepositoriesErrors.EntityAlreadyExists
+		}
+
+		clients := tx.Bucket(clientsBucket)
+		id, err := clients.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		stored := *client
+		stored.ID = id
+		value, err := json.Marshal(&stored)
+		if err != nil {
+			return err
+		}
+
+		err = clients.Put(itob(id), value)
+		if err != nil {
+			return err
+		}
+
+		err = byTelephone.Put([]byte(client.Telephone), itob(id))
+		if err != nil {
+			return err
+		}
+
+		client.ID = id
+		return nil
+	})
+}
+
+func getClient(tx *bolt.Tx, id uint64) (*models.Client, error) {
+	value := tx.Bucket(clientsBucket).Get(itob(id))
+	if value == nil {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	}
+
+	client := &models.Client{}
+	err := json.Unmarshal(value, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (c *ClientBoltRepository) GetByID(ctx context.Context, id uint64) (*models.Client, error) {
+	var client *models.Client
+
+	err := view(ctx, c.db, func(tx *bolt.Tx) error {
+		var err error
+		client, err = getClient(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (c *ClientBoltRepository) GetByTelephone(ctx context.Context, telephone string) (*models.Client, error) {
+	var client *models.Client
+
+	err := view(ctx, c.db, func(tx *bolt.Tx) error {
+		id := tx.Bucket(clientsByTelephoneBucket).Get([]byte(telephone))
+		if id == nil {
+			return repositoriesErrors.EntityDoesNotExists
+		}
+
+		var err error
+		client, err = getClient(tx, btoi(id))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (c *ClientBoltRepository) GetByTraining(ctx context.Context, id uint64) ([]models.Client, error) {
+	clientModels := []models.Client{}
+
+	err := view(ctx, c.db, func(tx *bolt.Tx) error {
+		for _, clientID := range prefixIDs(tx.Bucket(trainingsClientsBucket), itob(id)) {
+			client, err := getClient(tx, clientID)
+			if err != nil {
+				return err
+			}
+
+			clientModels = append(clientModels, *client)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clientModels, nil
+}
+
+func (c *ClientBoltRepository) CreateAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	return update(ctx, c.db, func(tx *bolt.Tx) error {
+		if tx.Bucket(clientsBucket).Get(itob(clientID)) == nil {
+			return repositoriesErrors.EntityDoesNotExists
+		}
+		if tx.Bucket(trainingsBucket).Get(itob(trainingID)) == nil {
+			return repositoriesErrors.EntityDoesNotExists
+		}
+
+		clientsTrainings := tx.Bucket(clientsTrainingsBucket)
+		key := join(itob(clientID), itob(trainingID))
+		if clientsTrainings.Get(key) != nil {
+			return extRepositoriesErrors.EntityAlreadyExists
+		}
+
+		err := clientsTrainings.Put(key, []byte{})
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(trainingsClientsBucket).Put(join(itob(trainingID), itob(clientID)), []byte{})
+	})
+}
+
+func (c *ClientBoltRepository) DeleteAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	return update(ctx, c.db, func(tx *bolt.Tx) error {
+		clientsTrainings := tx.Bucket(clientsTrainingsBucket)
+		key := join(itob(clientID), itob(trainingID))
+		if clientsTrainings.Get(key) == nil {
+			return repositoriesErrors.EntityDoesNotExists
+		}
+
+		err := clientsTrainings.Delete(key)
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(trainingsClientsBucket).Delete(join(itob(trainingID), itob(clientID)))
+	})
+}
+
+type TrainingBoltRepository struct {
+	db *bolt.DB
+}
+
+func NewTrainingBoltRepository(db *bolt.DB) repositories.TrainingRepository {
+	return &TrainingBoltRepository{db: db}
+}
+
+func (t *TrainingBoltRepository) Create(ctx context.Context, training *models.Training) error {
+	return update(ctx, t.db, func(tx *bolt.Tx) error {
+		trainings := tx.Bucket(trainingsBucket)
+		id, err := trainings.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		trainingDB := &TrainingBolt{Training: *training, AvailablePlacesNum: training.PlacesNum}
+		trainingDB.Training.ID = id
+		err = putTraining(tx, trainingDB)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Bucket(trainingsByDateTimeBucket).Put(join(dateTimeKey(training.DateTime), itob(id)), []byte{})
+		if err != nil {
+			return err
+		}
+
+		err = tx.Bucket(trainingsByCoachDateTimeBucket).Put(join(coachDateTimeKey(training.CoachID, training.DateTime), itob(id)), []byte{})
+		if err != nil {
+			return err
+		}
+
+		training.ID = id
+		return nil
+	})
+}
+
+func putTraining(tx *bolt.Tx, trainingDB *TrainingBolt) error {
+	value, err := json.Marshal(trainingDB)
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket(trainingsBucket).Put(itob(trainingDB.Training.ID), value)
+}
+
+func getTraining(tx *bolt.Tx, id uint64) (*TrainingBolt, error) {
+	value := tx.Bucket(trainingsBucket).Get(itob(id))
+	if value == nil {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	}
+
+	trainingDB := &TrainingBolt{}
+	err := json.Unmarshal(value, trainingDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return trainingDB, nil
+}
+
+func getTrainings(tx *bolt.Tx, ids []uint64) ([]models.Training, error) {
+	trainingModels := []models.Training{}
+	for _, id := range ids {
+		trainingDB, err := getTraining(tx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		trainingModels = append(trainingModels, trainingDB.Training)
+	}
+
+	return trainingModels, nil
+}
+
+// Delete removes the training together with its index entries and
+// assignments.
+func (t *TrainingBoltRepository) Delete(ctx context.Context, id uint64) error {
+	return update(ctx, t.db, func(tx *bolt.Tx) error {
+		trainingDB, err := getTraining(tx, id)
+		if err != nil {
+			return err
+		}
+		training := trainingDB.Training
+
+		for _, clientID := range prefixIDs(tx.Bucket(trainingsClientsBucket), itob(id)) {
+			err = tx.Bucket(clientsTrainingsBucket).Delete(join(itob(clientID), itob(id)))
+			if err != nil {
+				return err
+			}
+
+			err = tx.Bucket(trainingsClientsBucket).Delete(join(itob(id), itob(clientID)))
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.Bucket(trainingsByDateTimeBucket).Delete(join(dateTimeKey(training.DateTime), itob(id)))
+		if err != nil {
+			return err
+		}
+
+		err = tx.Bucket(trainingsByCoachDateTimeBucket).Delete(join(coachDateTimeKey(training.CoachID, training.DateTime), itob(id)))
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(trainingsBucket).Delete(itob(id))
+	})
+}
+
+func (t *TrainingBoltRepository) GetByID(ctx context.Context, id uint64) (*models.Training, error) {
+	var trainingDB *TrainingBolt
+
+	err := view(ctx, t.db, func(tx *bolt.Tx) error {
+		var err error
+		trainingDB, err = getTraining(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &trainingDB.Training, nil
+}
+
+func (t *TrainingBoltRepository) GetAllByClient(ctx context.Context, id uint64) ([]models.Training, error) {
+	var trainingModels []models.Training
+
+	err := view(ctx, t.db, func(tx *bolt.Tx) error {
+		var err error
+		trainingModels, err = getTrainings(tx, prefixIDs(tx.Bucket(clientsTrainingsBucket), itob(id)))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trainingModels, nil
+}
+
+// GetAllByCoachOnDate returns the trainings starting on the calendar day of
+// date in the location of date.
+func (t *TrainingBoltRepository) GetAllByCoachOnDate(ctx context.Context, id uint64, date time.Time) ([]models.Training, error) {
+	var trainingModels []models.Training
+
+	year, month, day := date.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+	end := time.Date(year, month, day+1, 0, 0, 0, 0, date.Location())
+
+	err := view(ctx, t.db, func(tx *bolt.Tx) error {
+		var err error
+		trainingModels, err = getTrainings(tx, rangeIDs(tx.Bucket(trainingsByCoachDateTimeBucket), coachDateTimeKey(id, start), coachDateTimeKey(id, end)))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trainingModels, nil
+}
+
+func (t *TrainingBoltRepository) GetAllByDateTime(ctx context.Context, dateTime time.Time) ([]models.Training, error) {
+	var trainingModels []models.Training
+
+	err := view(ctx, t.db, func(tx *bolt.Tx) error {
+		var err error
+		trainingModels, err = getTrainings(tx, prefixIDs(tx.Bucket(trainingsByDateTimeBucket), dateTimeKey(dateTime)))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trainingModels, nil
+}
+
+// GetAllBetweenDateTime includes both bounds, as between does in SQL.
+func (t *TrainingBoltRepository) GetAllBetweenDateTime(ctx context.Context, start time.Time, end time.Time) ([]models.Training, error) {
+	var trainingModels []models.Training
+
+	err := view(ctx, t.db, func(tx *bolt.Tx) error {
+		ids := []uint64{}
+		last := dateTimeKey(end)
+		c := tx.Bucket(trainingsByDateTimeBucket).Cursor()
+		for k, _ := c.Seek(dateTimeKey(start)); k != nil && bytes.Compare(k[:8], last) <= 0; k, _ = c.Next() {
+			ids = append(ids, btoi(k[8:]))
+		}
+
+		var err error
+		trainingModels, err = getTrainings(tx, ids)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return trainingModels, nil
+}
+
+func (t *TrainingBoltRepository) ReduceAvailablePlacesNum(ctx context.Context, id uint64) error {
+	return update(ctx, t.db, func(tx *bolt.Tx) error {
+		trainingDB, err := getTraining(tx, id)
+		if err != nil {
+			return err
+		}
+		if trainingDB.AvailablePlacesNum == 0 {
+			return extRepositoriesErrors.NoAvailablePlacesNum
+		}
+
+		trainingDB.AvailablePlacesNum--
+		return putTraining(tx, trainingDB)
+	})
+}
+
+func (t *TrainingBoltRepository) IncreaseAvailablePlacesNum(ctx context.Context, id uint64) error {
+	return update(ctx, t.db, func(tx *bolt.Tx) error {
+		trainingDB, err := getTraining(tx, id)
+		if err != nil {
+			return err
+		}
+
+		trainingDB.AvailablePlacesNum++
+		return putTraining(tx, trainingDB)
+	})
+}
+
+// AvailablePlacesNum returns the number of free places left on the training.
+func (t *TrainingBoltRepository) AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error) {
+	var trainingDB *TrainingBolt
+
+	err := view(ctx, t.db, func(tx *bolt.Tx) error {
+		var err error
+		trainingDB, err = getTraining(tx, id)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return trainingDB.AvailablePlacesNum, nil
+}
@@ -0,0 +1,200 @@
+package boltdb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	transactionManager "github.com/nkarakotova/lim-core/managers/implementation"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	bolt "go.etcd.io/bbolt"
+)
+
+type BoltSuite struct {
+	suite.Suite
+	dir       string
+	db        *bolt.DB
+	clients   repositories.ClientRepository
+	trainings repositories.TrainingRepository
+	ctx       context.Context
+}
+
+func (s *BoltSuite) BeforeEach(t provider.T) {
+	var err error
+	s.dir, err = os.MkdirTemp("", "lim-bolt")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	s.db, err = Open(filepath.Join(s.dir, "lim.db"))
+	if err != nil {
+		t.Fatalf("error opening database: %v", err)
+	}
+	s.clients = NewClientBoltRepository(s.db)
+	s.trainings = NewTrainingBoltRepository(s.db)
+	s.ctx = context.Background()
+}
+
+func (s *BoltSuite) AfterEach(t provider.T) {
+	s.db.Close()
+	os.RemoveAll(s.dir)
+}
+
+func (s *BoltSuite) TestClientBoltGetByTelephone(t provider.T) {
+	t.Title("ClientBoltGetByTelephone: Index lookup")
+	t.Tags("Client")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.clients.Create(s.ctx, postgreSQLObjectMother.CreateTestClient()))
+
+		client, err := s.clients.GetByTelephone(s.ctx, "1234567890")
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(postgreSQLObjectMother.CreateTestClient(), client)
+	})
+	t.WithNewStep("Duplicate telephone", func(sCtx provider.StepCtx) {
+		err := s.clients.Create(s.ctx, postgreSQLObjectMother.CreateTestClient())
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.EntityAlreadyExists)
+	})
+	t.WithNewStep("Unknown telephone", func(sCtx provider.StepCtx) {
+		_, err := s.clients.GetByTelephone(s.ctx, "0000000000")
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *BoltSuite) TestClientBoltAssignment(t provider.T) {
+	t.Title("ClientBoltAssignment: Create, get and delete")
+	t.Tags("Client")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		client := postgreSQLObjectMother.CreateTestClient()
+		training := postgreSQLObjectMother.CreateTestTraining()
+		sCtx.Assert().NoError(s.clients.Create(s.ctx, client))
+		sCtx.Assert().NoError(s.trainings.Create(s.ctx, training))
+
+		sCtx.Assert().NoError(s.clients.CreateAssignment(s.ctx, client.ID, training.ID))
+		sCtx.Assert().ErrorIs(s.clients.CreateAssignment(s.ctx, client.ID, training.ID), extRepositoriesErrors.EntityAlreadyExists)
+
+		clients, err := s.clients.GetByTraining(s.ctx, training.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Client{*client}, clients)
+
+		trainings, err := s.trainings.GetAllByClient(s.ctx, client.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Training{*training}, trainings)
+
+		sCtx.Assert().NoError(s.clients.DeleteAssignment(s.ctx, client.ID, training.ID))
+		sCtx.Assert().ErrorIs(s.clients.DeleteAssignment(s.ctx, client.ID, training.ID), repositoriesErrors.EntityDoesNotExists)
+	})
+	t.WithNewStep("Unknown training", func(sCtx provider.StepCtx) {
+		err := s.clients.CreateAssignment(s.ctx, 1, 2)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *BoltSuite) TestTrainingBoltIndexes(t provider.T) {
+	t.Title("TrainingBolt: Date and coach indexes")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		training := postgreSQLObjectMother.CreateTestTraining()
+		sCtx.Assert().NoError(s.trainings.Create(s.ctx, training))
+		later := postgreSQLObjectMother.CreateTestTraining()
+		later.DateTime = later.DateTime.Add(2 * time.Hour)
+		sCtx.Assert().NoError(s.trainings.Create(s.ctx, later))
+		other := postgreSQLObjectMother.CreateTestTraining()
+		other.CoachID = 2
+		other.DateTime = other.DateTime.AddDate(0, 0, 1)
+		sCtx.Assert().NoError(s.trainings.Create(s.ctx, other))
+
+		trainings, err := s.trainings.GetAllByDateTime(s.ctx, training.DateTime)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Training{*training}, trainings)
+
+		trainings, err = s.trainings.GetAllByCoachOnDate(s.ctx, 1, time.Date(2024, 7, 7, 0, 0, 0, 0, time.UTC))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Training{*training, *later}, trainings)
+
+		trainings, err = s.trainings.GetAllBetweenDateTime(s.ctx, training.DateTime, later.DateTime)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Training{*training, *later}, trainings)
+	})
+}
+
+func (s *BoltSuite) TestTrainingBoltDelete(t provider.T) {
+	t.Title("TrainingBoltDelete: Index entries and assignments are removed")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		client := postgreSQLObjectMother.CreateTestClient()
+		training := postgreSQLObjectMother.CreateTestTraining()
+		sCtx.Assert().NoError(s.clients.Create(s.ctx, client))
+		sCtx.Assert().NoError(s.trainings.Create(s.ctx, training))
+		sCtx.Assert().NoError(s.clients.CreateAssignment(s.ctx, client.ID, training.ID))
+
+		sCtx.Assert().NoError(s.trainings.Delete(s.ctx, training.ID))
+
+		trainings, err := s.trainings.GetAllByDateTime(s.ctx, training.DateTime)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(trainings)
+		trainings, err = s.trainings.GetAllByClient(s.ctx, client.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(trainings)
+		sCtx.Assert().ErrorIs(s.trainings.Delete(s.ctx, training.ID), repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *BoltSuite) TestTrainingBoltAvailablePlacesNum(t provider.T) {
+	t.Title("TrainingBoltAvailablePlacesNum: Reduce and increase")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		training := postgreSQLObjectMother.CreateTestTraining()
+		training.PlacesNum = 1
+		sCtx.Assert().NoError(s.trainings.Create(s.ctx, training))
+
+		sCtx.Assert().NoError(s.trainings.ReduceAvailablePlacesNum(s.ctx, training.ID))
+		sCtx.Assert().ErrorIs(s.trainings.ReduceAvailablePlacesNum(s.ctx, training.ID), extRepositoriesErrors.NoAvailablePlacesNum)
+		sCtx.Assert().NoError(s.trainings.IncreaseAvailablePlacesNum(s.ctx, training.ID))
+		sCtx.Assert().ErrorIs(s.trainings.IncreaseAvailablePlacesNum(s.ctx, 2), repositoriesErrors.EntityDoesNotExists)
+
+		available, err := s.trainings.(*TrainingBoltRepository).AvailablePlacesNum(s.ctx, training.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(uint64(1), available)
+	})
+}
+
+func (s *BoltSuite) TestBoltTransactionRollback(t provider.T) {
+	t.Title("BoltTransaction: Rollback discards the changes")
+	t.Tags("Transaction")
+	t.WithNewStep("Rollback", func(sCtx provider.StepCtx) {
+		transactor := transactionManager.NewTransactionManagerImplementation(manager.Must(NewFactory(s.db)))
+		fnErr := errors.New("fail")
+
+		err := transactor.WithinTransaction(s.ctx, func(txCtx context.Context) error {
+			if err := s.clients.Create(txCtx, postgreSQLObjectMother.CreateTestClient()); err != nil {
+				return err
+			}
+			if _, err := s.clients.GetByTelephone(txCtx, "1234567890"); err != nil {
+				return err
+			}
+			return fnErr
+		})
+		sCtx.Assert().ErrorIs(err, fnErr)
+
+		_, err = s.clients.GetByTelephone(s.ctx, "1234567890")
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func TestBoltSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(BoltSuite))
+}
@@ -0,0 +1,39 @@
+package boltdb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nkarakotova/lim-repo/inmemory"
+	"github.com/nkarakotova/lim-repo/repotest"
+
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+// The kiosk file keeps no coaches and halls, they come from the in-memory
+// storage here.
+func TestConformanceSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, &repotest.Suite{New: func() (repotest.Repositories, func(), error) {
+		dir, err := os.MkdirTemp("", "lim-bolt")
+		if err != nil {
+			return repotest.Repositories{}, nil, err
+		}
+		db, err := Open(filepath.Join(dir, "lim.db"))
+		if err != nil {
+			os.RemoveAll(dir)
+			return repotest.Repositories{}, nil, err
+		}
+		storage := inmemory.NewStorage()
+
+		return repotest.Repositories{
+			Client:   NewClientBoltRepository(db),
+			Coach:    inmemory.NewCoachInMemoryRepository(storage),
+			Hall:     inmemory.NewHallInMemoryRepository(storage),
+			Training: NewTrainingBoltRepository(db),
+		}, func() {
+			db.Close()
+			os.RemoveAll(dir)
+		}, nil
+	}})
+}
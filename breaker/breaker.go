@@ -0,0 +1,310 @@
+// Package breaker fails the repository calls fast while the database is
+// down, instead of letting each of them wait on a connect timeout.
+//
+// A Breaker is shared by the repositories of a database. It opens once the
+// share of the calls failed on the database reaches Settings.FailureRatio;
+// then every call returns an *ErrUnavailable at once. After
+// Settings.OpenTimeout the next call probes the database and closes the
+// breaker if it answers, the other calls are still rejected meanwhile. A
+// transaction counts as a single call: the repository calls within it are
+// let through and only its outcome is recorded:
+//
+//	b := breaker.New("postgres", breaker.Settings{Probe: db.PingContext}, logger)
+//	clients := breaker.NewClientRepository(postgreSQL.NewClientPostgreSQLRepository(dbx), b)
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jackc/pgconn"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-repo/internal/txctx"
+	"github.com/nkarakotova/lim-repo/metrics"
+	"github.com/nkarakotova/lim-repo/retry"
+)
+
+type State int
+
+const (
+	Closed State = iota
+	Open
+	// HalfOpen lets a single probe through.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// ErrUnavailable is the error of a call rejected by an open breaker. It
+// matches repositoriesErrors.DatabaseUnavailable with errors.Is.
+type ErrUnavailable struct {
+	Breaker string
+	// RetryAfter is the time left until the breaker probes the database.
+	RetryAfter time.Duration
+	// Cause is the failure that opened the breaker.
+	Cause error
+}
+
+func (e *ErrUnavailable) Error() string {
+	return fmt.Sprintf("breaker %s is open, retry after %s: %v", e.Breaker, e.RetryAfter, e.Cause)
+}
+
+func (e *ErrUnavailable) Is(target error) bool {
+	return target == extRepositoriesErrors.DatabaseUnavailable
+}
+
+type Settings struct {
+	// FailureRatio is the share of failed calls within Window that opens
+	// the breaker, once there were MinRequests of them.
+	FailureRatio float64
+	MinRequests  int
+	Window       time.Duration
+	// OpenTimeout is how long the breaker stays open before a probe.
+	OpenTimeout time.Duration
+	// Probe checks the database once OpenTimeout passed, bounded by
+	// ProbeTimeout. Without it the first call is the probe.
+	Probe        func(ctx context.Context) error
+	ProbeTimeout time.Duration
+	// IsFailure tells the errors of the database from the ones of the
+	// studio, DefaultIsFailure by default.
+	IsFailure func(err error) bool
+}
+
+// DefaultSettings is used for the zero fields of Settings.
+var DefaultSettings = Settings{
+	FailureRatio: 0.5,
+	MinRequests:  10,
+	Window:       10 * time.Second,
+	OpenTimeout:  5 * time.Second,
+	ProbeTimeout: time.Second,
+	IsFailure:    DefaultIsFailure,
+}
+
+func (s Settings) withDefaults() Settings {
+	if s.FailureRatio <= 0 {
+		s.FailureRatio = DefaultSettings.FailureRatio
+	}
+	if s.MinRequests <= 0 {
+		s.MinRequests = DefaultSettings.MinRequests
+	}
+	if s.Window <= 0 {
+		s.Window = DefaultSettings.Window
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = DefaultSettings.OpenTimeout
+	}
+	if s.ProbeTimeout <= 0 {
+		s.ProbeTimeout = DefaultSettings.ProbeTimeout
+	}
+	if s.IsFailure == nil {
+		s.IsFailure = DefaultSettings.IsFailure
+	}
+
+	return s
+}
+
+// DefaultIsFailure counts the transient errors of retry.Retryable and the
+// timeouts, not the ones of a context canceled by the caller.
+func DefaultIsFailure(err error) bool {
+	if retry.Retryable(err) || errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+type Breaker struct {
+	name     string
+	settings Settings
+	logger   *log.Logger
+
+	mutex       sync.Mutex
+	metrics     metrics.Metrics
+	state       State
+	openedAt    time.Time
+	cause       error
+	windowStart time.Time
+	requests    int
+	failures    int
+}
+
+// New returns a closed breaker. Its state changes are logged with logger,
+// discarded when it is nil.
+func New(name string, settings Settings, logger *log.Logger) *Breaker {
+	if logger == nil {
+		logger = log.New(io.Discard)
+	}
+
+	return &Breaker{
+		name:        name,
+		settings:    settings.withDefaults(),
+		logger:      logger,
+		windowStart: time.Now(),
+	}
+}
+
+// ReportTo records the current state and the changes of b in m.
+func (b *Breaker) ReportTo(m metrics.Metrics) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.metrics = m
+	m.ObserveBreakerState(b.name, b.state.String())
+}
+
+func (b *Breaker) State() State {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.state
+}
+
+// setState is called with the lock held.
+func (b *Breaker) setState(state State, cause error) {
+	from := b.state
+	b.state = state
+	b.requests, b.failures = 0, 0
+	b.windowStart = time.Now()
+	if state == Open {
+		b.openedAt = b.windowStart
+		b.cause = cause
+	}
+
+	switch state {
+	case Open:
+		b.logger.Warn("BREAKER! Open", "breaker", b.name, "from", from, "err", cause)
+	case Closed:
+		b.logger.Info("BREAKER! Closed", "breaker", b.name, "from", from)
+	default:
+		b.logger.Info("BREAKER! Half-open", "breaker", b.name, "from", from)
+	}
+	if b.metrics != nil {
+		b.metrics.ObserveBreakerState(b.name, state.String())
+	}
+}
+
+// allow reports whether a call may run and whether it is the probe.
+func (b *Breaker) allow() (probe bool, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case Closed:
+		if time.Since(b.windowStart) >= b.settings.Window {
+			b.requests, b.failures = 0, 0
+			b.windowStart = time.Now()
+		}
+		return false, nil
+	case Open:
+		if wait := b.settings.OpenTimeout - time.Since(b.openedAt); wait > 0 {
+			return false, &ErrUnavailable{Breaker: b.name, RetryAfter: wait, Cause: b.cause}
+		}
+		b.setState(HalfOpen, nil)
+		return true, nil
+	}
+
+	return false, &ErrUnavailable{Breaker: b.name, Cause: b.cause}
+}
+
+// done records the outcome of a call.
+func (b *Breaker) done(probe bool, err error) {
+	failure := err != nil && b.settings.IsFailure(err)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if probe {
+		// A probe canceled by its caller tells nothing, the breaker stays
+		// open until the next one.
+		if failure || errors.Is(err, context.Canceled) {
+			b.setState(Open, err)
+		} else {
+			b.setState(Closed, nil)
+		}
+		return
+	}
+	if b.state != Closed {
+		return
+	}
+
+	b.requests++
+	if failure {
+		b.failures++
+	}
+	if failure && b.requests >= b.settings.MinRequests && float64(b.failures) >= b.settings.FailureRatio*float64(b.requests) {
+		b.setState(Open, err)
+	}
+}
+
+// probed records the outcome of Settings.Probe, any error keeps b open.
+func (b *Breaker) probed(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if err != nil {
+		b.setState(Open, err)
+	} else {
+		b.setState(Closed, nil)
+	}
+}
+
+// do calls fn unless b is open. A call within a transaction is left to the
+// TransactionManager, so that a failure is counted once.
+func (b *Breaker) do(ctx context.Context, fn func() error) error {
+	if txctx.InTransaction(ctx) {
+		return fn()
+	}
+
+	probe, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	if probe && b.settings.Probe != nil {
+		probeCtx, cancel := context.WithTimeout(ctx, b.settings.ProbeTimeout)
+		err = b.settings.Probe(probeCtx)
+		cancel()
+		b.probed(err)
+		if err != nil {
+			return &ErrUnavailable{Breaker: b.name, RetryAfter: b.settings.OpenTimeout, Cause: err}
+		}
+		probe = false
+	}
+
+	err = fn()
+	b.done(probe, err)
+
+	return err
+}
+
+// get is do for an fn returning a result.
+func get[T any](ctx context.Context, b *Breaker, fn func() (T, error)) (T, error) {
+	var result T
+	err := b.do(ctx, func() error {
+		var err error
+		result, err = fn()
+		return err
+	})
+
+	return result, err
+}
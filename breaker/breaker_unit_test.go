@@ -0,0 +1,283 @@
+package breaker_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	"github.com/nkarakotova/lim-repo/breaker"
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-repo/inmemory"
+	"github.com/nkarakotova/lim-repo/metrics"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+var connectionReset = &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+
+// failingClients fails its calls with err, after waiting for release when
+// it is set.
+type failingClients struct {
+	repositories.ClientRepository
+	mutex   sync.Mutex
+	err     error
+	release chan struct{}
+	calls   int
+}
+
+func (f *failingClients) GetByID(ctx context.Context, id uint64) (*models.Client, error) {
+	f.mutex.Lock()
+	f.calls++
+	err, release := f.err, f.release
+	f.mutex.Unlock()
+
+	if release != nil {
+		<-release
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return f.ClientRepository.GetByID(ctx, id)
+}
+
+func (f *failingClients) fail(err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.err = err
+}
+
+// states keeps the breaker states it observes.
+type states struct {
+	mutex  sync.Mutex
+	states []string
+}
+
+func (s *states) ObserveCall(metrics.Call) {}
+
+func (s *states) RegisterPool(string, func() sql.DBStats) error {
+	return nil
+}
+
+func (s *states) RegisterCache(string, func() metrics.CacheStats) error {
+	return nil
+}
+
+func (s *states) ObserveBreakerState(_ string, state string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.states = append(s.states, state)
+}
+
+type BreakerSuite struct {
+	suite.Suite
+	output  bytes.Buffer
+	failing *failingClients
+	client  *models.Client
+	ctx     context.Context
+}
+
+func (s *BreakerSuite) BeforeEach(t provider.T) {
+	s.output.Reset()
+	s.ctx = context.Background()
+
+	storage := inmemory.NewStorage()
+	s.failing = &failingClients{ClientRepository: inmemory.NewClientInMemoryRepository(storage)}
+	s.client = &models.Client{Name: "Ivan", Telephone: "+79990001122"}
+	err := s.failing.ClientRepository.Create(s.ctx, s.client)
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+}
+
+func (s *BreakerSuite) newBreaker(settings breaker.Settings) (*breaker.Breaker, repositories.ClientRepository) {
+	b := breaker.New("postgres", settings, log.NewWithOptions(&s.output, log.Options{Level: log.InfoLevel}))
+
+	return b, breaker.NewClientRepository(s.failing, b)
+}
+
+// open fails calls until b opens.
+func (s *BreakerSuite) open(sCtx provider.StepCtx, b *breaker.Breaker, clients repositories.ClientRepository) {
+	s.failing.fail(connectionReset)
+	for i := 0; i < 2; i++ {
+		_, err := clients.GetByID(s.ctx, s.client.ID)
+		sCtx.Require().ErrorIs(err, syscall.ECONNRESET)
+	}
+	sCtx.Require().Equal(breaker.Open, b.State())
+}
+
+func (s *BreakerSuite) TestOpen(t provider.T) {
+	t.Title("Breaker: Opens at the failure ratio and fails fast")
+	t.Tags("Breaker")
+	t.WithNewStep("Open", func(sCtx provider.StepCtx) {
+		b, clients := s.newBreaker(breaker.Settings{FailureRatio: 0.5, MinRequests: 4, OpenTimeout: time.Hour})
+
+		for i := 0; i < 2; i++ {
+			_, err := clients.GetByID(s.ctx, s.client.ID)
+			sCtx.Require().NoError(err)
+		}
+		_, err := clients.GetByID(s.ctx, 42)
+		sCtx.Require().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+		sCtx.Assert().Equal(breaker.Closed, b.State())
+
+		// The third failure of six calls reaches the ratio.
+		s.failing.fail(connectionReset)
+		for i := 0; i < 2; i++ {
+			_, err = clients.GetByID(s.ctx, s.client.ID)
+			sCtx.Require().ErrorIs(err, syscall.ECONNRESET)
+			sCtx.Assert().Equal(breaker.Closed, b.State())
+		}
+		_, err = clients.GetByID(s.ctx, s.client.ID)
+		sCtx.Require().ErrorIs(err, syscall.ECONNRESET)
+		sCtx.Assert().Equal(breaker.Open, b.State())
+
+		calls := s.failing.calls
+		_, err = clients.GetByID(s.ctx, s.client.ID)
+
+		var unavailable *breaker.ErrUnavailable
+		sCtx.Require().True(errors.As(err, &unavailable))
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.DatabaseUnavailable)
+		sCtx.Assert().Equal("postgres", unavailable.Breaker)
+		sCtx.Assert().Greater(unavailable.RetryAfter, time.Minute)
+		sCtx.Assert().ErrorIs(unavailable.Cause, syscall.ECONNRESET)
+		sCtx.Assert().Equal(calls, s.failing.calls)
+		sCtx.Assert().Contains(s.output.String(), "BREAKER! Open")
+	})
+}
+
+func (s *BreakerSuite) TestProbe(t provider.T) {
+	t.Title("Breaker: Half-opens with a probe once the open timeout passed")
+	t.Tags("Breaker")
+	t.WithNewStep("Probe", func(sCtx provider.StepCtx) {
+		probeErr := errors.New("connection refused")
+		probes := 0
+		b, clients := s.newBreaker(breaker.Settings{
+			MinRequests: 2,
+			OpenTimeout: 10 * time.Millisecond,
+			Probe: func(ctx context.Context) error {
+				probes++
+				return probeErr
+			},
+		})
+		recorded := &states{}
+		b.ReportTo(recorded)
+		s.open(sCtx, b, clients)
+
+		time.Sleep(20 * time.Millisecond)
+		calls := s.failing.calls
+		_, err := clients.GetByID(s.ctx, s.client.ID)
+
+		var unavailable *breaker.ErrUnavailable
+		sCtx.Require().True(errors.As(err, &unavailable))
+		sCtx.Assert().Equal(probeErr, unavailable.Cause)
+		sCtx.Assert().Equal(1, probes)
+		sCtx.Assert().Equal(calls, s.failing.calls)
+		sCtx.Assert().Equal(breaker.Open, b.State())
+
+		probeErr = nil
+		s.failing.fail(nil)
+		time.Sleep(20 * time.Millisecond)
+		found, err := clients.GetByID(s.ctx, s.client.ID)
+
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(s.client.Name, found.Name)
+		sCtx.Assert().Equal(2, probes)
+		sCtx.Assert().Equal(breaker.Closed, b.State())
+		sCtx.Assert().Equal([]string{"closed", "open", "half-open", "open", "half-open", "closed"}, recorded.states)
+		sCtx.Assert().Contains(s.output.String(), "BREAKER! Closed")
+	})
+}
+
+func (s *BreakerSuite) TestTrialCall(t provider.T) {
+	t.Title("Breaker: Without a probe a single call goes through while half-open")
+	t.Tags("Breaker")
+	t.WithNewStep("Trial", func(sCtx provider.StepCtx) {
+		b, clients := s.newBreaker(breaker.Settings{MinRequests: 2, OpenTimeout: 10 * time.Millisecond})
+		s.open(sCtx, b, clients)
+
+		time.Sleep(20 * time.Millisecond)
+		s.failing.mutex.Lock()
+		s.failing.err = nil
+		s.failing.release = make(chan struct{})
+		release := s.failing.release
+		s.failing.mutex.Unlock()
+
+		trial := make(chan error)
+		go func() {
+			_, err := clients.GetByID(s.ctx, s.client.ID)
+			trial <- err
+		}()
+		for b.State() != breaker.HalfOpen {
+			time.Sleep(time.Millisecond)
+		}
+
+		_, err := clients.GetByID(s.ctx, s.client.ID)
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.DatabaseUnavailable)
+
+		close(release)
+		sCtx.Require().NoError(<-trial)
+		sCtx.Assert().Equal(breaker.Closed, b.State())
+	})
+	t.WithNewStep("Failed trial", func(sCtx provider.StepCtx) {
+		s.failing.mutex.Lock()
+		s.failing.release = nil
+		s.failing.mutex.Unlock()
+		b, clients := s.newBreaker(breaker.Settings{MinRequests: 2, OpenTimeout: 10 * time.Millisecond})
+		s.open(sCtx, b, clients)
+
+		time.Sleep(20 * time.Millisecond)
+		_, err := clients.GetByID(s.ctx, s.client.ID)
+
+		sCtx.Assert().ErrorIs(err, syscall.ECONNRESET)
+		sCtx.Assert().Equal(breaker.Open, b.State())
+	})
+}
+
+func (s *BreakerSuite) TestTransaction(t provider.T) {
+	t.Title("Breaker: A transaction counts as a single call")
+	t.Tags("Breaker")
+	t.WithNewStep("Transaction", func(sCtx provider.StepCtx) {
+		b, clients := s.newBreaker(breaker.Settings{MinRequests: 2, OpenTimeout: time.Hour})
+		transactions := breaker.NewTransactionManager(inmemory.NewTransactionManager(inmemory.NewStorage()), b)
+		s.failing.fail(connectionReset)
+
+		err := transactions.WithinTransaction(s.ctx, func(ctx context.Context) error {
+			_, err := clients.GetByID(ctx, s.client.ID)
+			return err
+		})
+
+		sCtx.Require().ErrorIs(err, syscall.ECONNRESET)
+		sCtx.Assert().Equal(breaker.Closed, b.State())
+
+		_, err = clients.GetByID(s.ctx, s.client.ID)
+		sCtx.Require().ErrorIs(err, syscall.ECONNRESET)
+		sCtx.Assert().Equal(breaker.Open, b.State())
+	})
+}
+
+func (s *BreakerSuite) TestNilLogger(t provider.T) {
+	t.Title("Breaker: State changes without a logger are not logged")
+	t.Tags("Breaker")
+	t.WithNewStep("Nil logger", func(sCtx provider.StepCtx) {
+		b := breaker.New("postgres", breaker.Settings{MinRequests: 2, OpenTimeout: time.Hour}, nil)
+
+		s.open(sCtx, b, breaker.NewClientRepository(s.failing, b))
+	})
+}
+
+func TestBreakerSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(BreakerSuite))
+}
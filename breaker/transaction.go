@@ -0,0 +1,24 @@
+package breaker
+
+import (
+	"context"
+
+	"github.com/nkarakotova/lim-core/managers"
+)
+
+type TransactionManager struct {
+	next    managers.TransactionManager
+	breaker *Breaker
+}
+
+// NewTransactionManager fails the transactions of next fast while b is
+// open, before they wait on a connection.
+func NewTransactionManager(next managers.TransactionManager, b *Breaker) managers.TransactionManager {
+	return &TransactionManager{next: next, breaker: b}
+}
+
+func (t *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.breaker.do(ctx, func() error {
+		return t.next.WithinTransaction(ctx, fn)
+	})
+}
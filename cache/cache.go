@@ -0,0 +1,284 @@
+// Package cache keeps the coach, hall and schedule lookups of the
+// repositories in process.
+//
+// Caches holds an LRU cache with a TTL per lookup: the coach and hall by id,
+// all coaches and halls, the details of a hall and the trainings of a date
+// and time. The constructors of this package decorate the repositories with
+// a Caches. Concurrent misses of a lookup wait for a single load, and a call
+// within a transaction bypasses the caches. Above the replica decorators the
+// caches follow the Router given to UseRouter: a read sticking to the primary
+// bypasses them and a load reads the primary, so a lagging replica is never
+// cached. A write through the decorators invalidates the lookups of its
+// table; Listen does the same for the writes of the other processes,
+// notified by the triggers of the migrations. A booking or a cancellation
+// only changes the available places, which the schedule does not carry, so
+// it keeps the caches:
+//
+//	caches := cache.New(cache.DefaultSettings)
+//	caches.UseRouter(router)
+//	caches.Listen(connect, logger)
+//	defer caches.Close()
+//	coaches := cache.NewCoachRepository(postgreSQL.NewCoachPostgreSQLRepository(dbx), caches)
+package cache
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nkarakotova/lim-core/models"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/nkarakotova/lim-repo/internal/txctx"
+	"github.com/nkarakotova/lim-repo/metrics"
+	extModels "github.com/nkarakotova/lim-repo/models"
+	"github.com/nkarakotova/lim-repo/replica"
+)
+
+type Settings struct {
+	// Size is the number of lookups each cache holds.
+	Size int
+	// TTL is how long a lookup is cached. It bounds the staleness of a
+	// lookup when a notification is lost.
+	TTL time.Duration
+	// ReconnectDelay is the first delay before Listen reconnects, doubled
+	// up to a minute while the database is down.
+	ReconnectDelay time.Duration
+}
+
+var DefaultSettings = Settings{
+	Size:           1024,
+	TTL:            5 * time.Minute,
+	ReconnectDelay: time.Second,
+}
+
+func (s Settings) withDefaults() Settings {
+	if s.Size <= 0 {
+		s.Size = DefaultSettings.Size
+	}
+	if s.TTL <= 0 {
+		s.TTL = DefaultSettings.TTL
+	}
+	if s.ReconnectDelay <= 0 {
+		s.ReconnectDelay = DefaultSettings.ReconnectDelay
+	}
+
+	return s
+}
+
+// lookup caches the results of a repository method by key. The callers get
+// copies made with clone, so they may change them.
+type lookup[K comparable, V any] struct {
+	caches *Caches
+	clone  func(V) V
+	group  singleflight.Group
+
+	mutex      sync.Mutex
+	entries    *lru[K, V]
+	generation uint64
+	stats      metrics.CacheStats
+}
+
+func newLookup[K comparable, V any](caches *Caches, clone func(V) V) *lookup[K, V] {
+	return &lookup[K, V]{
+		caches:  caches,
+		clone:   clone,
+		entries: newLRU[K, V](caches.settings.Size, caches.settings.TTL),
+	}
+}
+
+// get returns the cached value of key or the one load returns. The load is
+// shared by the concurrent misses of key and runs without the cancellation
+// of ctx, so a caller giving up does not fail the others, and on the
+// primary.
+func (l *lookup[K, V]) get(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error) {
+	if txctx.InTransaction(ctx) || l.caches.sticky(ctx) {
+		return load(ctx)
+	}
+
+	l.mutex.Lock()
+	value, ok, evicted := l.entries.get(key, time.Now())
+	l.stats.Evictions += uint64(evicted)
+	if ok {
+		l.stats.Hits++
+		l.mutex.Unlock()
+		return l.clone(value), nil
+	}
+	l.stats.Misses++
+	generation := l.generation
+	l.mutex.Unlock()
+
+	// A load started before an invalidation is neither joined nor stored.
+	leader := false
+	results := l.group.DoChan(fmt.Sprint(generation, "/", key), func() (any, error) {
+		leader = true
+		value, err := load(replica.WithPrimary(context.WithoutCancel(ctx)))
+		if err != nil {
+			return value, err
+		}
+
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		if l.generation == generation {
+			l.stats.Evictions += uint64(l.entries.add(key, value, time.Now()))
+		}
+
+		return value, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	case result := <-results:
+		if !leader {
+			l.mutex.Lock()
+			l.stats.Shared++
+			l.mutex.Unlock()
+		}
+		if result.Err != nil {
+			var zero V
+			return zero, result.Err
+		}
+		return l.clone(result.Val.(V)), nil
+	}
+}
+
+func (l *lookup[K, V]) invalidate() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.entries.purge()
+	l.generation++
+	l.stats.Invalidations++
+}
+
+func (l *lookup[K, V]) Stats() metrics.CacheStats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	stats := l.stats
+	stats.Entries = l.entries.len()
+
+	return stats
+}
+
+type invalidator interface {
+	invalidate()
+	Stats() metrics.CacheStats
+}
+
+func clonePointer[T any](value *T) *T {
+	copied := *value
+
+	return &copied
+}
+
+func cloneDetails(details *extModels.HallDetails) *extModels.HallDetails {
+	copied := *details
+	copied.Equipment = maps.Clone(details.Equipment)
+
+	return &copied
+}
+
+type Caches struct {
+	settings Settings
+	router   *replica.Router
+
+	coach    *lookup[uint64, *models.Coach]
+	coaches  *lookup[struct{}, []models.Coach]
+	hall     *lookup[uint64, *models.Hall]
+	halls    *lookup[struct{}, map[uint64]models.Hall]
+	details  *lookup[uint64, *extModels.HallDetails]
+	schedule *lookup[int64, []models.Training]
+
+	// tables are the lookups to invalidate on a write to a table.
+	tables map[string][]invalidator
+	names  map[string]invalidator
+
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+// New returns the caches, shared by the repositories of a database.
+func New(settings Settings) *Caches {
+	settings = settings.withDefaults()
+	c := &Caches{settings: settings}
+	c.coach = newLookup[uint64](c, clonePointer[models.Coach])
+	c.coaches = newLookup[struct{}](c, slices.Clone[[]models.Coach])
+	c.hall = newLookup[uint64](c, clonePointer[models.Hall])
+	c.halls = newLookup[struct{}](c, maps.Clone[map[uint64]models.Hall])
+	c.details = newLookup[uint64](c, cloneDetails)
+	c.schedule = newLookup[int64](c, slices.Clone[[]models.Training])
+	c.tables = map[string][]invalidator{
+		"coaches":        {c.coach, c.coaches},
+		"halls":          {c.hall, c.halls, c.details},
+		"hall_equipment": {c.details},
+		"trainings":      {c.schedule},
+	}
+	c.names = map[string]invalidator{
+		"coach":        c.coach,
+		"coaches":      c.coaches,
+		"hall":         c.hall,
+		"halls":        c.halls,
+		"hall_details": c.details,
+		"schedule":     c.schedule,
+	}
+
+	return c
+}
+
+// UseRouter makes the caches follow router, the Router of the replica
+// decorators below them. It is called before the repositories are used.
+func (c *Caches) UseRouter(router *replica.Router) {
+	c.router = router
+}
+
+func (c *Caches) sticky(ctx context.Context) bool {
+	return c.router != nil && c.router.Sticky(ctx)
+}
+
+// Invalidate drops the lookups that read table, all of them for a table
+// the caches do not know.
+func (c *Caches) Invalidate(table string) {
+	lookups, ok := c.tables[table]
+	if !ok {
+		c.invalidateAll()
+		return
+	}
+
+	for _, lookup := range lookups {
+		lookup.invalidate()
+	}
+}
+
+func (c *Caches) invalidateAll() {
+	for _, lookup := range c.names {
+		lookup.invalidate()
+	}
+}
+
+// Stats returns the stats of the caches by name.
+func (c *Caches) Stats() map[string]metrics.CacheStats {
+	stats := make(map[string]metrics.CacheStats, len(c.names))
+	for name, lookup := range c.names {
+		stats[name] = lookup.Stats()
+	}
+
+	return stats
+}
+
+// ReportTo reports the stats of the caches to m.
+func (c *Caches) ReportTo(m metrics.Metrics) error {
+	for name, lookup := range c.names {
+		err := m.RegisterCache(name, lookup.Stats)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
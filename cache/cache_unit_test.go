@@ -0,0 +1,396 @@
+package cache_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jackc/pgconn"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	"github.com/nkarakotova/lim-repo/cache"
+	"github.com/nkarakotova/lim-repo/inmemory"
+	"github.com/nkarakotova/lim-repo/replica"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+// countingCoaches counts the loads of the coaches, holding them until
+// release when it is set.
+type countingCoaches struct {
+	repositories.CoachRepository
+	mutex   sync.Mutex
+	loads   int
+	release chan struct{}
+}
+
+func (c *countingCoaches) load() {
+	c.mutex.Lock()
+	c.loads++
+	release := c.release
+	c.mutex.Unlock()
+
+	if release != nil {
+		<-release
+	}
+}
+
+func (c *countingCoaches) GetByID(ctx context.Context, id uint64) (*models.Coach, error) {
+	c.load()
+
+	return c.CoachRepository.GetByID(ctx, id)
+}
+
+func (c *countingCoaches) GetAll(ctx context.Context) ([]models.Coach, error) {
+	c.load()
+
+	return c.CoachRepository.GetAll(ctx)
+}
+
+func (c *countingCoaches) count() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.loads
+}
+
+// notifications is a connection that delivers the payloads sent to it.
+type notifications struct {
+	payloads chan string
+	listened chan string
+}
+
+func (n *notifications) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case n.listened <- sql:
+		return pgconn.CommandTag("LISTEN"), nil
+	}
+}
+
+func (n *notifications) WaitForNotification(ctx context.Context) (*pgconn.Notification, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case payload, ok := <-n.payloads:
+		if !ok {
+			return nil, errors.New("connection lost")
+		}
+		return &pgconn.Notification{Channel: cache.Channel, Payload: payload}, nil
+	}
+}
+
+func (n *notifications) Close(ctx context.Context) error {
+	return nil
+}
+
+type CacheSuite struct {
+	suite.Suite
+	storage  *inmemory.Storage
+	counting *countingCoaches
+	caches   *cache.Caches
+	coaches  repositories.CoachRepository
+	coach    *models.Coach
+	ctx      context.Context
+}
+
+func (s *CacheSuite) BeforeEach(t provider.T) {
+	s.ctx = context.Background()
+	s.storage = inmemory.NewStorage()
+	s.counting = &countingCoaches{CoachRepository: inmemory.NewCoachInMemoryRepository(s.storage)}
+	s.caches = cache.New(cache.Settings{Size: 2, TTL: time.Hour, ReconnectDelay: time.Millisecond})
+	s.coaches = cache.NewCoachRepository(s.counting, s.caches)
+
+	s.coach = &models.Coach{Name: "Anna"}
+	err := s.counting.CoachRepository.Create(s.ctx, s.coach)
+	if err != nil {
+		t.Fatalf("error creating coach: %v", err)
+	}
+}
+
+func (s *CacheSuite) AfterEach(t provider.T) {
+	s.caches.Close()
+}
+
+func (s *CacheSuite) TestHit(t provider.T) {
+	t.Title("Cache: A lookup is loaded once and returned as a copy")
+	t.Tags("Cache")
+	t.WithNewStep("Hit", func(sCtx provider.StepCtx) {
+		all, err := s.coaches.GetAll(s.ctx)
+		sCtx.Require().NoError(err)
+		all[0].Name = "Changed"
+
+		all, err = s.coaches.GetAll(s.ctx)
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal("Anna", all[0].Name)
+		sCtx.Assert().Equal(1, s.counting.count())
+
+		stats := s.caches.Stats()["coaches"]
+		sCtx.Assert().Equal(uint64(1), stats.Hits)
+		sCtx.Assert().Equal(uint64(1), stats.Misses)
+		sCtx.Assert().Equal(1, stats.Entries)
+	})
+	t.WithNewStep("Eviction", func(sCtx provider.StepCtx) {
+		ids := []uint64{s.coach.ID}
+		for _, name := range []string{"Boris", "Vera"} {
+			coach := &models.Coach{Name: name}
+			sCtx.Require().NoError(s.counting.CoachRepository.Create(s.ctx, coach))
+			ids = append(ids, coach.ID)
+		}
+		for _, id := range ids {
+			_, err := s.coaches.GetByID(s.ctx, id)
+			sCtx.Require().NoError(err)
+		}
+
+		sCtx.Assert().Equal(uint64(1), s.caches.Stats()["coach"].Evictions)
+		sCtx.Assert().Equal(2, s.caches.Stats()["coach"].Entries)
+	})
+}
+
+func (s *CacheSuite) TestExpire(t provider.T) {
+	t.Title("Cache: A lookup expires after the TTL")
+	t.Tags("Cache")
+	t.WithNewStep("Expire", func(sCtx provider.StepCtx) {
+		coaches := cache.NewCoachRepository(s.counting, cache.New(cache.Settings{TTL: 10 * time.Millisecond}))
+
+		_, err := coaches.GetByID(s.ctx, s.coach.ID)
+		sCtx.Require().NoError(err)
+		time.Sleep(20 * time.Millisecond)
+		_, err = coaches.GetByID(s.ctx, s.coach.ID)
+		sCtx.Require().NoError(err)
+
+		sCtx.Assert().Equal(2, s.counting.count())
+	})
+}
+
+func (s *CacheSuite) TestInvalidate(t provider.T) {
+	t.Title("Cache: A write invalidates the lookups of its table")
+	t.Tags("Cache")
+	t.WithNewStep("Write", func(sCtx provider.StepCtx) {
+		_, err := s.coaches.GetAll(s.ctx)
+		sCtx.Require().NoError(err)
+
+		sCtx.Require().NoError(s.coaches.Create(s.ctx, &models.Coach{Name: "Boris"}))
+		all, err := s.coaches.GetAll(s.ctx)
+
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Len(all, 2)
+		sCtx.Assert().Equal(2, s.counting.count())
+		sCtx.Assert().Equal(uint64(1), s.caches.Stats()["coaches"].Invalidations)
+		sCtx.Assert().Equal(uint64(0), s.caches.Stats()["schedule"].Invalidations)
+	})
+	t.WithNewStep("During a load", func(sCtx provider.StepCtx) {
+		s.counting.release = make(chan struct{})
+		loaded := make(chan struct{})
+		go func() {
+			s.coaches.GetByID(s.ctx, s.coach.ID)
+			close(loaded)
+		}()
+		for s.counting.count() < 3 {
+			time.Sleep(time.Millisecond)
+		}
+
+		s.caches.Invalidate("coaches")
+		close(s.counting.release)
+		<-loaded
+		_, err := s.coaches.GetByID(s.ctx, s.coach.ID)
+
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(4, s.counting.count())
+	})
+}
+
+func (s *CacheSuite) TestBooking(t provider.T) {
+	t.Title("Cache: A booking keeps the schedule")
+	t.Tags("Cache")
+	t.WithNewStep("Booking", func(sCtx provider.StepCtx) {
+		hall := &models.Hall{Number: 1}
+		sCtx.Require().NoError(inmemory.NewHallInMemoryRepository(s.storage).Create(s.ctx, hall))
+		trainings := cache.NewTrainingRepository(inmemory.NewTrainingInMemoryRepository(s.storage), s.caches)
+		training := &models.Training{CoachID: s.coach.ID, HallID: hall.ID, Name: "Yoga", DateTime: time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC), PlacesNum: 2}
+		sCtx.Require().NoError(trainings.Create(s.ctx, training))
+		_, err := trainings.GetAllByDateTime(s.ctx, training.DateTime)
+		sCtx.Require().NoError(err)
+
+		sCtx.Require().NoError(trainings.ReduceAvailablePlacesNum(s.ctx, training.ID))
+		sCtx.Require().NoError(trainings.IncreaseAvailablePlacesNum(s.ctx, training.ID))
+		all, err := trainings.GetAllByDateTime(s.ctx, training.DateTime)
+
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Len(all, 1)
+		sCtx.Assert().Equal(uint64(1), s.caches.Stats()["schedule"].Hits)
+		sCtx.Assert().Equal(uint64(1), s.caches.Stats()["schedule"].Invalidations)
+	})
+}
+
+func (s *CacheSuite) TestCoalesce(t provider.T) {
+	t.Title("Cache: Concurrent misses share a single load")
+	t.Tags("Cache")
+	t.WithNewStep("Coalesce", func(sCtx provider.StepCtx) {
+		s.counting.release = make(chan struct{})
+
+		var group sync.WaitGroup
+		errs := make(chan error, 5)
+		for i := 0; i < 5; i++ {
+			group.Add(1)
+			go func() {
+				defer group.Done()
+				_, err := s.coaches.GetByID(s.ctx, s.coach.ID)
+				errs <- err
+			}()
+		}
+		for s.caches.Stats()["coach"].Misses < 5 {
+			time.Sleep(time.Millisecond)
+		}
+		close(s.counting.release)
+		group.Wait()
+		close(errs)
+
+		for err := range errs {
+			sCtx.Assert().NoError(err)
+		}
+		sCtx.Assert().Equal(1, s.counting.count())
+		sCtx.Assert().Equal(uint64(4), s.caches.Stats()["coach"].Shared)
+	})
+	t.WithNewStep("Caller gives up", func(sCtx provider.StepCtx) {
+		s.counting.release = make(chan struct{})
+		ctx, cancel := context.WithCancel(s.ctx)
+
+		canceled := make(chan error)
+		go func() {
+			_, err := s.coaches.GetAll(ctx)
+			canceled <- err
+		}()
+		type result struct {
+			all []models.Coach
+			err error
+		}
+		waited := make(chan result)
+		go func() {
+			all, err := s.coaches.GetAll(s.ctx)
+			waited <- result{all, err}
+		}()
+		for s.caches.Stats()["coaches"].Misses < 2 {
+			time.Sleep(time.Millisecond)
+		}
+
+		cancel()
+		sCtx.Assert().ErrorIs(<-canceled, context.Canceled)
+		close(s.counting.release)
+		found := <-waited
+		sCtx.Require().NoError(found.err)
+		sCtx.Assert().Len(found.all, 1)
+	})
+}
+
+func (s *CacheSuite) TestTransaction(t provider.T) {
+	t.Title("Cache: A lookup within a transaction bypasses the cache")
+	t.Tags("Cache")
+	t.WithNewStep("Transaction", func(sCtx provider.StepCtx) {
+		err := inmemory.NewTransactionManager(s.storage).WithinTransaction(s.ctx, func(ctx context.Context) error {
+			for i := 0; i < 2; i++ {
+				_, err := s.coaches.GetByID(ctx, s.coach.ID)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(2, s.counting.count())
+		sCtx.Assert().Equal(0, s.caches.Stats()["coach"].Entries)
+	})
+}
+
+// assignments accepts every assignment.
+type assignments struct {
+	repositories.ClientRepository
+}
+
+func (a *assignments) CreateAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	return nil
+}
+
+func (s *CacheSuite) TestReplica(t provider.T) {
+	t.Title("Cache: The caches are filled from the primary and bypassed by sticky reads")
+	t.Tags("Cache")
+
+	lagging := &countingCoaches{CoachRepository: inmemory.NewCoachInMemoryRepository(inmemory.NewStorage())}
+	router := replica.NewRouter([]*sql.DB{nil}, replica.Settings{
+		CheckInterval: time.Hour,
+		StickyWindow:  time.Hour,
+		Lag: func(ctx context.Context, db *sql.DB) (time.Duration, error) {
+			return 0, nil
+		},
+	}, log.New(io.Discard))
+	s.caches.UseRouter(router)
+	coaches := cache.NewCoachRepository(replica.NewCoachRepository(s.counting, []repositories.CoachRepository{lagging}, router), s.caches)
+
+	t.WithNewStep("Primary", func(sCtx provider.StepCtx) {
+		all, err := coaches.GetAll(s.ctx)
+
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Len(all, 1)
+		sCtx.Assert().Equal(1, s.counting.count())
+		sCtx.Assert().Equal(0, lagging.count())
+	})
+	t.WithNewStep("Sticky", func(sCtx provider.StepCtx) {
+		ctx := replica.WithClient(s.ctx, 1)
+		err := replica.NewClientRepository(&assignments{}, router).CreateAssignment(ctx, 1, 1)
+		sCtx.Require().NoError(err)
+
+		for i := 0; i < 2; i++ {
+			_, err = coaches.GetAll(ctx)
+			sCtx.Require().NoError(err)
+		}
+
+		sCtx.Assert().Equal(3, s.counting.count())
+		sCtx.Assert().Equal(uint64(1), s.caches.Stats()["coaches"].Misses)
+	})
+}
+
+func (s *CacheSuite) TestListen(t provider.T) {
+	t.Title("Cache: Notifications invalidate the caches and a lost connection is reopened")
+	t.Tags("Cache")
+	t.WithNewStep("Listen", func(sCtx provider.StepCtx) {
+		var output bytes.Buffer
+		conn := &notifications{payloads: make(chan string), listened: make(chan string, 2)}
+		refused := true
+		s.caches.Listen(func(ctx context.Context) (cache.Conn, error) {
+			if refused {
+				refused = false
+				return nil, errors.New("connection refused")
+			}
+			return conn, nil
+		}, log.New(&output))
+
+		sCtx.Assert().Equal("listen "+cache.Channel, <-conn.listened)
+		_, err := s.coaches.GetByID(s.ctx, s.coach.ID)
+		sCtx.Require().NoError(err)
+
+		conn.payloads <- "coaches"
+		conn.payloads <- "trainings"
+		sCtx.Assert().Equal(0, s.caches.Stats()["coach"].Entries)
+		sCtx.Assert().Equal(uint64(2), s.caches.Stats()["coach"].Invalidations)
+		sCtx.Assert().Equal(uint64(1), s.caches.Stats()["hall"].Invalidations)
+
+		close(conn.payloads)
+		sCtx.Assert().Equal("listen "+cache.Channel, <-conn.listened)
+		s.caches.Close()
+		sCtx.Assert().Contains(output.String(), "CACHE! Lost notifications")
+	})
+}
+
+func TestCacheSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(CacheSuite))
+}
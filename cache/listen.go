@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/nkarakotova/lim-repo/internal/listen"
+)
+
+// Channel is the channel the triggers notify with the name of the table a
+// statement wrote to.
+const Channel = "lim_cache"
+
+// Conn is the connection Listen waits for the notifications on, a
+// *pgx.Conn.
+type Conn = listen.Conn
+
+// Listen invalidates the caches on the notifications of Channel until
+// Close, on a connection opened with connect. It reconnects after the
+// connection is lost and then invalidates all the caches, as the
+// notifications in between are lost.
+func (c *Caches) Listen(connect func(ctx context.Context) (Conn, error), logger *log.Logger) {
+	var ctx context.Context
+	ctx, c.stop = context.WithCancel(context.Background())
+	c.done = make(chan struct{})
+
+	listener := &listen.Listener{
+		Channel:        Channel,
+		Connect:        connect,
+		ReconnectDelay: c.settings.ReconnectDelay,
+		Listened: func(bool) {
+			c.invalidateAll()
+		},
+		Notify: c.Invalidate,
+		Logger: logger,
+		Lost:   "CACHE! Lost notifications, reconnecting",
+	}
+
+	go func() {
+		defer close(c.done)
+		listener.Run(ctx)
+	}()
+}
+
+// Close stops Listen.
+func (c *Caches) Close() {
+	if c.stop == nil {
+		return
+	}
+
+	c.stop()
+	<-c.done
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"container/list"
+	"time"
+)
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// lru holds at most size entries for ttl each, dropping the least recently
+// used one first. It is not safe for concurrent use.
+type lru[K comparable, V any] struct {
+	size    int
+	ttl     time.Duration
+	order   *list.List
+	entries map[K]*list.Element
+}
+
+func newLRU[K comparable, V any](size int, ttl time.Duration) *lru[K, V] {
+	return &lru[K, V]{size: size, ttl: ttl, order: list.New(), entries: map[K]*list.Element{}}
+}
+
+// get returns the value of key unless it expired; an expired one is
+// dropped and counted in evicted.
+func (l *lru[K, V]) get(key K, now time.Time) (value V, ok bool, evicted int) {
+	element, ok := l.entries[key]
+	if !ok {
+		return value, false, 0
+	}
+
+	found := element.Value.(*entry[K, V])
+	if !now.Before(found.expires) {
+		l.remove(element)
+		return value, false, 1
+	}
+
+	l.order.MoveToFront(element)
+	return found.value, true, 0
+}
+
+// add sets the value of key and returns the number of entries it evicted.
+func (l *lru[K, V]) add(key K, value V, now time.Time) (evicted int) {
+	if element, ok := l.entries[key]; ok {
+		l.remove(element)
+	}
+
+	l.entries[key] = l.order.PushFront(&entry[K, V]{key: key, value: value, expires: now.Add(l.ttl)})
+	for l.order.Len() > l.size {
+		l.remove(l.order.Back())
+		evicted++
+	}
+
+	return evicted
+}
+
+func (l *lru[K, V]) remove(element *list.Element) {
+	l.order.Remove(element)
+	delete(l.entries, element.Value.(*entry[K, V]).key)
+}
+
+func (l *lru[K, V]) purge() {
+	l.order.Init()
+	l.entries = map[K]*list.Element{}
+}
+
+func (l *lru[K, V]) len() int {
+	return l.order.Len()
+}
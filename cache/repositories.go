@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+)
+
+type CoachRepository struct {
+	next   repositories.CoachRepository
+	caches *Caches
+}
+
+// NewCoachRepository caches GetByID and GetAll of next in caches.
+func NewCoachRepository(next repositories.CoachRepository, caches *Caches) repositories.CoachRepository {
+	return &CoachRepository{next: next, caches: caches}
+}
+
+func (c *CoachRepository) Create(ctx context.Context, coach *models.Coach) error {
+	err := c.next.Create(ctx, coach)
+	if err == nil {
+		c.caches.Invalidate("coaches")
+	}
+
+	return err
+}
+
+func (c *CoachRepository) GetByID(ctx context.Context, id uint64) (*models.Coach, error) {
+	return c.caches.coach.get(ctx, id, func(ctx context.Context) (*models.Coach, error) {
+		return c.next.GetByID(ctx, id)
+	})
+}
+
+func (c *CoachRepository) GetByName(ctx context.Context, name string) (*models.Coach, error) {
+	return c.next.GetByName(ctx, name)
+}
+
+func (c *CoachRepository) GetAll(ctx context.Context) ([]models.Coach, error) {
+	return c.caches.coaches.get(ctx, struct{}{}, func(ctx context.Context) ([]models.Coach, error) {
+		return c.next.GetAll(ctx)
+	})
+}
+
+type HallRepository struct {
+	next   repositories.HallRepository
+	caches *Caches
+}
+
+// NewHallRepository caches GetByID and GetAll of next in caches.
+func NewHallRepository(next repositories.HallRepository, caches *Caches) repositories.HallRepository {
+	return newHallRepository(next, caches)
+}
+
+func newHallRepository(next repositories.HallRepository, caches *Caches) *HallRepository {
+	return &HallRepository{next: next, caches: caches}
+}
+
+func (h *HallRepository) Create(ctx context.Context, hall *models.Hall) error {
+	err := h.next.Create(ctx, hall)
+	if err == nil {
+		h.caches.Invalidate("halls")
+	}
+
+	return err
+}
+
+func (h *HallRepository) GetByID(ctx context.Context, id uint64) (*models.Hall, error) {
+	return h.caches.hall.get(ctx, id, func(ctx context.Context) (*models.Hall, error) {
+		return h.next.GetByID(ctx, id)
+	})
+}
+
+func (h *HallRepository) GetByNumber(ctx context.Context, number uint64) (*models.Hall, error) {
+	return h.next.GetByNumber(ctx, number)
+}
+
+func (h *HallRepository) GetAll(ctx context.Context) (map[uint64]models.Hall, error) {
+	return h.caches.halls.get(ctx, struct{}{}, func(ctx context.Context) (map[uint64]models.Hall, error) {
+		return h.next.GetAll(ctx)
+	})
+}
+
+// ExtHallRepository also caches GetDetails of the extended hall
+// repository.
+type ExtHallRepository struct {
+	*HallRepository
+	next extRepositories.HallRepository
+}
+
+// NewExtHallRepository is NewHallRepository for the extended hall
+// repository.
+func NewExtHallRepository(next extRepositories.HallRepository, caches *Caches) extRepositories.HallRepository {
+	return &ExtHallRepository{HallRepository: newHallRepository(next, caches), next: next}
+}
+
+func (h *ExtHallRepository) GetDetails(ctx context.Context, id uint64) (*extModels.HallDetails, error) {
+	return h.caches.details.get(ctx, id, func(ctx context.Context) (*extModels.HallDetails, error) {
+		return h.next.GetDetails(ctx, id)
+	})
+}
+
+func (h *ExtHallRepository) UpdateDetails(ctx context.Context, details *extModels.HallDetails) error {
+	err := h.next.UpdateDetails(ctx, details)
+	if err == nil {
+		h.caches.Invalidate("halls")
+	}
+
+	return err
+}
+
+func (h *ExtHallRepository) SetEquipment(ctx context.Context, id uint64, equipment extModels.Equipment, count uint64) error {
+	err := h.next.SetEquipment(ctx, id, equipment, count)
+	if err == nil {
+		h.caches.Invalidate("hall_equipment")
+	}
+
+	return err
+}
+
+func (h *ExtHallRepository) GetAllByEquipment(ctx context.Context, required map[extModels.Equipment]uint64) (map[uint64]models.Hall, error) {
+	return h.next.GetAllByEquipment(ctx, required)
+}
+
+type TrainingRepository struct {
+	next   repositories.TrainingRepository
+	caches *Caches
+}
+
+// NewTrainingRepository caches GetAllByDateTime of next in caches.
+func NewTrainingRepository(next repositories.TrainingRepository, caches *Caches) repositories.TrainingRepository {
+	return newTrainingRepository(next, caches)
+}
+
+func newTrainingRepository(next repositories.TrainingRepository, caches *Caches) *TrainingRepository {
+	return &TrainingRepository{next: next, caches: caches}
+}
+
+// wrote invalidates the schedule after a successful write.
+func (t *TrainingRepository) wrote(err error) error {
+	if err == nil {
+		t.caches.Invalidate("trainings")
+	}
+
+	return err
+}
+
+func (t *TrainingRepository) Create(ctx context.Context, training *models.Training) error {
+	return t.wrote(t.next.Create(ctx, training))
+}
+
+func (t *TrainingRepository) Delete(ctx context.Context, id uint64) error {
+	return t.wrote(t.next.Delete(ctx, id))
+}
+
+func (t *TrainingRepository) GetByID(ctx context.Context, id uint64) (*models.Training, error) {
+	return t.next.GetByID(ctx, id)
+}
+
+func (t *TrainingRepository) GetAllByClient(ctx context.Context, id uint64) ([]models.Training, error) {
+	return t.next.GetAllByClient(ctx, id)
+}
+
+func (t *TrainingRepository) GetAllByCoachOnDate(ctx context.Context, id uint64, date time.Time) ([]models.Training, error) {
+	return t.next.GetAllByCoachOnDate(ctx, id, date)
+}
+
+func (t *TrainingRepository) GetAllByDateTime(ctx context.Context, dateTime time.Time) ([]models.Training, error) {
+	return t.caches.schedule.get(ctx, dateTime.UnixNano(), func(ctx context.Context) ([]models.Training, error) {
+		return t.next.GetAllByDateTime(ctx, dateTime)
+	})
+}
+
+func (t *TrainingRepository) GetAllBetweenDateTime(ctx context.Context, start time.Time, end time.Time) ([]models.Training, error) {
+	return t.next.GetAllBetweenDateTime(ctx, start, end)
+}
+
+// ReduceAvailablePlacesNum keeps the schedule: models.Training does not
+// carry the available places.
+func (t *TrainingRepository) ReduceAvailablePlacesNum(ctx context.Context, id uint64) error {
+	return t.next.ReduceAvailablePlacesNum(ctx, id)
+}
+
+func (t *TrainingRepository) IncreaseAvailablePlacesNum(ctx context.Context, id uint64) error {
+	return t.next.IncreaseAvailablePlacesNum(ctx, id)
+}
+
+// ExtTrainingRepository also invalidates the schedule on the writes of the
+// extended training repository.
+type ExtTrainingRepository struct {
+	*TrainingRepository
+	next extRepositories.TrainingRepository
+}
+
+// NewExtTrainingRepository is NewTrainingRepository for the extended
+// training repository.
+func NewExtTrainingRepository(next extRepositories.TrainingRepository, caches *Caches) extRepositories.TrainingRepository {
+	return &ExtTrainingRepository{TrainingRepository: newTrainingRepository(next, caches), next: next}
+}
+
+func (t *ExtTrainingRepository) Reschedule(ctx context.Context, id uint64, dateTime time.Time) error {
+	return t.wrote(t.next.Reschedule(ctx, id, dateTime))
+}
+
+func (t *ExtTrainingRepository) AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error) {
+	return t.next.AvailablePlacesNum(ctx, id)
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nkarakotova/lim-core/managers"
+
+	"github.com/nkarakotova/lim-repo/flags"
+	"github.com/nkarakotova/lim-repo/generator"
+	"github.com/nkarakotova/lim-repo/postgreSQL"
+	"github.com/nkarakotova/lim-repo/sqlite"
+
+	"github.com/charmbracelet/log"
+)
+
+const (
+	backendPostgres = "postgres"
+	backendSQLite   = "sqlite"
+)
+
+// runGenerate generates a dataset and loads it in one transaction. The size
+// flags override the ones of -size.
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+
+	size := fs.String("size", "small", "ready configuration: small, medium or large")
+	seed := fs.Int64("seed", 0, "seed of the generator")
+	clients := fs.Int("clients", 0, "number of clients")
+	coaches := fs.Int("coaches", 0, "number of coaches")
+	halls := fs.Int("halls", 0, "number of halls")
+	from := fs.String("from", "", "first day of the schedule, 2006-01-02")
+	days := fs.Int("days", 0, "number of days in the schedule")
+	density := fs.Float64("density", 0, "share of busy hours a hall has a training at")
+	occupancy := fs.Float64("occupancy", 0, "share of places booked in busy hours")
+	churn := fs.Float64("churn", 0, "share of clients leaving in a month")
+	firstHour := fs.Int("firsthour", 0, "first training hour")
+	lastHour := fs.Int("lasthour", 0, "training hour the trainings end before")
+
+	backend := fs.String("backend", backendPostgres, "storage to populate: postgres or sqlite")
+	copyFrom := fs.Bool("copy", false, "load into empty postgres tables with COPY")
+	logLevel := fs.String("loglevel", "info", "log level")
+
+	var postgres flags.PostgresFlags
+	fs.StringVar(&postgres.Host, "host", "localhost", "postgres host")
+	fs.StringVar(&postgres.Port, "port", "5432", "postgres port")
+	fs.StringVar(&postgres.User, "user", "postgres", "postgres user")
+	fs.StringVar(&postgres.Password, "password", "", "postgres password")
+	fs.StringVar(&postgres.DBName, "dbname", "postgres", "postgres database")
+	fs.StringVar(&postgres.Driver, "driver", flags.DriverStdlib, "postgres driver: stdlib or pgxpool")
+
+	var sqliteFlags flags.SQLiteFlags
+	fs.StringVar(&sqliteFlags.Path, "path", "lim.db", "sqlite database file")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *backend != backendPostgres && *backend != backendSQLite {
+		return fmt.Errorf("unknown backend %q", *backend)
+	}
+	if *copyFrom && *backend != backendPostgres {
+		return fmt.Errorf("-copy needs the %s backend", backendPostgres)
+	}
+
+	cfg, ok := generator.Sizes[*size]
+	if !ok {
+		return fmt.Errorf("unknown size %q", *size)
+	}
+
+	var parseErr error
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "seed":
+			cfg.Seed = *seed
+		case "clients":
+			cfg.Clients = *clients
+		case "coaches":
+			cfg.Coaches = *coaches
+		case "halls":
+			cfg.Halls = *halls
+		case "from":
+			cfg.From, parseErr = time.Parse(time.DateOnly, *from)
+		case "days":
+			cfg.Days = *days
+		case "density":
+			cfg.Density = *density
+		case "occupancy":
+			cfg.Occupancy = *occupancy
+		case "churn":
+			cfg.Churn = *churn
+		case "firsthour":
+			cfg.FirstTrainingTime = *firstHour
+		case "lasthour":
+			cfg.LastTrainingTime = *lastHour
+		}
+	})
+	if parseErr != nil {
+		return parseErr
+	}
+
+	logger := log.New(os.Stderr)
+	level, err := log.ParseLevel(*logLevel)
+	if err != nil {
+		return err
+	}
+	logger.SetLevel(level)
+
+	dataset, err := generator.Generate(cfg)
+	if err != nil {
+		return err
+	}
+	logger.Info("GENERATOR! Generated dataset", "seed", cfg.Seed, "clients", len(dataset.Clients), "coaches", len(dataset.Coaches),
+		"halls", len(dataset.Halls), "trainings", len(dataset.Trainings), "bookings", len(dataset.Bookings))
+
+	ctx := context.Background()
+	start := time.Now()
+
+	if *backend == backendPostgres {
+		err = loadPostgres(ctx, postgres, *copyFrom, dataset, logger)
+	} else {
+		err = loadSQLite(ctx, sqliteFlags, dataset, logger)
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Info("GENERATOR! Successfully loaded dataset", "backend", *backend, "elapsed", time.Since(start))
+
+	return nil
+}
+
+func loadPostgres(ctx context.Context, postgres flags.PostgresFlags, copyFrom bool, dataset *generator.Dataset, logger *log.Logger) error {
+	if copyFrom {
+		postgres.Driver = flags.DriverPgxPool
+	}
+
+	fields, err := postgreSQL.CreatePostgresRepositoryFields(postgres, logger)
+	if err != nil {
+		return err
+	}
+	defer fields.DB.Close()
+	if fields.Pool != nil {
+		defer fields.Pool.Close()
+	}
+	if fields.Caches != nil {
+		defer fields.Caches.Close()
+	}
+
+	err = postgreSQL.Migrate(ctx, fields.DB)
+	if err != nil {
+		return err
+	}
+
+	if copyFrom {
+		return postgreSQL.CreateTransactionManager(fields).WithinTransaction(ctx, func(ctx context.Context) error {
+			return postgreSQL.ImportDataset(ctx, fields.Pool, dataset)
+		})
+	}
+
+	return load(ctx, postgreSQL.CreateTransactionManager(fields), dataset, generator.Repositories{
+		Client:   postgreSQL.CreateClientPostgreSQLRepository(fields),
+		Coach:    postgreSQL.CreateCoachPostgreSQLRepository(fields),
+		Hall:     postgreSQL.CreateHallPostgreSQLRepository(fields),
+		Training: postgreSQL.CreateTrainingPostgreSQLRepository(fields),
+	})
+}
+
+func loadSQLite(ctx context.Context, sqliteFlags flags.SQLiteFlags, dataset *generator.Dataset, logger *log.Logger) error {
+	fields, err := sqlite.CreateSQLiteRepositoryFields(sqliteFlags, logger)
+	if err != nil {
+		return err
+	}
+	defer fields.DB.Close()
+
+	return load(ctx, sqlite.CreateTransactionManager(fields), dataset, generator.Repositories{
+		Client:   sqlite.CreateClientSQLiteRepository(fields),
+		Coach:    sqlite.CreateCoachSQLiteRepository(fields),
+		Hall:     sqlite.CreateHallSQLiteRepository(fields),
+		Training: sqlite.CreateTrainingSQLiteRepository(fields),
+	})
+}
+
+func load(ctx context.Context, manager managers.TransactionManager, dataset *generator.Dataset, repos generator.Repositories) error {
+	return manager.WithinTransaction(ctx, func(ctx context.Context) error {
+		return generator.Load(ctx, dataset, repos)
+	})
+}
@@ -0,0 +1,42 @@
+// Command lim-repo runs maintenance tasks against the storage of the
+// repositories:
+//
+//	lim-repo generate -size medium -seed 42 -backend postgres -copy
+//
+// Run a subcommand with -h for its flags.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: lim-repo <command> [flags]
+
+commands:
+  generate  populate the storage with a generated studio
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		err = runGenerate(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -1,11 +1,17 @@
 package config
 
 import (
+	"io"
+	"os"
+
+	"github.com/charmbracelet/log"
+
 	"github.com/nkarakotova/lim-repo/flags"
 )
 
 type Config struct {
 	Postgres      flags.PostgresFlags `mapstructure:"postgres"`
+	SQLite        flags.SQLiteFlags   `mapstructure:"sqlite"`
 	Address       string              `mapstructure:"address"`
 	Port          string              `mapstructure:"port"`
 	LogLevel      string              `mapstructure:"loglevel"`
@@ -17,3 +23,37 @@ type Config struct {
 	FirstTrainingTime int `mapstructure:"first_training_time"`
 	LastTrainingTime int `mapstructure:"last_training_time"`
 }
+
+// InitLogger returns the logger at LogLevel, info when it is empty, writing
+// to LogFile or to stderr when it is empty. The closer closes LogFile.
+func (c *Config) InitLogger() (*log.Logger, io.Closer, error) {
+	level := log.InfoLevel
+	if c.LogLevel != "" {
+		var err error
+		level, err = log.ParseLevel(c.LogLevel)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var out io.WriteCloser = nopCloser{os.Stderr}
+	if c.LogFile != "" {
+		file, err := os.OpenFile(c.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = file
+	}
+
+	logger := log.NewWithOptions(out, log.Options{Level: level, ReportTimestamp: true})
+
+	return logger, out, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
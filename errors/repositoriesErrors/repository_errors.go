@@ -0,0 +1,15 @@
+package repositoriesErrors
+
+import "errors"
+
+var (
+	EntityAlreadyExists       = errors.New("Repository error! Такая сущность уже есть в базе данных!")
+	NoAvailablePlacesNum      = errors.New("Repository error! Не осталось свободных мест на тренировке!")
+	CoachNotAvailable         = errors.New("Repository error! Тренер не работает в это время!")
+	PlacesNumMoreThenCapacity = errors.New("Repository error! На тренировке больше мест, чем вмещает зал!")
+	HallClosed                = errors.New("Repository error! Зал закрыт в это время!")
+	DatabaseNotEmpty          = errors.New("Repository error! База данных не пуста!")
+	DatabaseUnavailable       = errors.New("Repository error! База данных недоступна!")
+	QueryCanceled             = errors.New("Repository error! Запрос отменён!")
+	QueryTimeout              = errors.New("Repository error! Превышено время выполнения запроса!")
+)
@@ -0,0 +1,244 @@
+// Package events delivers the changes of the trainings and bookings made by
+// any process, notified by the triggers of the migrations on Channel.
+//
+// Subscribe listens on a connection of its own and sends the typed events
+// over a buffered channel. By default a full channel holds the
+// notifications back until the consumer takes an event, so none is lost
+// while the connection lasts; a consumer lagging for long fills the
+// notification queue of the server instead. With Drop the subscription
+// keeps reading the notifications however slow the consumer is: the events
+// that find the channel full are dropped, and Overflowed tells the consumer
+// so ahead of the next event delivered. A lost connection is reopened and
+// listened on again; Resubscribed then tells that the events in between are
+// lost:
+//
+//	subscription := events.Subscribe(connect, events.DefaultSettings, logger)
+//	defer subscription.Close()
+//	for event := range subscription.Events() {
+//		switch event := event.(type) {
+//		case events.BookingCreated:
+//			notify(event.ClientID, event.TrainingID)
+//		}
+//	}
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/nkarakotova/lim-repo/internal/listen"
+)
+
+// Channel is the channel the triggers notify the events on.
+const Channel = "lim_events"
+
+// Event is one of TrainingCreated, TrainingDeleted, BookingCreated,
+// BookingCancelled, Resubscribed and Overflowed.
+type Event interface {
+	event()
+}
+
+type TrainingCreated struct {
+	TrainingID uint64
+}
+
+// TrainingDeleted is a cancelled training. Its bookings are cancelled
+// with it, each with a BookingCancelled.
+type TrainingDeleted struct {
+	TrainingID uint64
+}
+
+type BookingCreated struct {
+	ClientID   uint64
+	TrainingID uint64
+}
+
+type BookingCancelled struct {
+	ClientID   uint64
+	TrainingID uint64
+}
+
+// Resubscribed follows a reconnection: the events since the connection was
+// lost are not delivered.
+type Resubscribed struct{}
+
+// Overflowed follows a full channel with Drop: the events since the
+// previous one were dropped while the consumer lagged behind.
+type Overflowed struct{}
+
+func (TrainingCreated) event()  {}
+func (TrainingDeleted) event()  {}
+func (BookingCreated) event()   {}
+func (BookingCancelled) event() {}
+func (Resubscribed) event()     {}
+func (Overflowed) event()       {}
+
+// payload is the notification the triggers send.
+type payload struct {
+	Type       string `json:"type"`
+	TrainingID uint64 `json:"training_id"`
+	ClientID   uint64 `json:"client_id"`
+}
+
+// Parse returns the event of the payload of a notification.
+func Parse(text string) (Event, error) {
+	var p payload
+	err := json.Unmarshal([]byte(text), &p)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p.Type {
+	case "training_created":
+		return TrainingCreated{TrainingID: p.TrainingID}, nil
+	case "training_deleted":
+		return TrainingDeleted{TrainingID: p.TrainingID}, nil
+	case "booking_created":
+		return BookingCreated{ClientID: p.ClientID, TrainingID: p.TrainingID}, nil
+	case "booking_cancelled":
+		return BookingCancelled{ClientID: p.ClientID, TrainingID: p.TrainingID}, nil
+	}
+
+	return nil, fmt.Errorf("unknown event type %q", p.Type)
+}
+
+// Conn is the connection a subscription waits for the notifications on, a
+// *pgx.Conn.
+type Conn = listen.Conn
+
+type Settings struct {
+	// Buffer is the number of events delivered ahead of the consumer, from
+	// which the notifications are held back.
+	Buffer int
+	// Drop drops the events instead of holding the notifications back
+	// while Buffer events wait for the consumer.
+	Drop bool
+	// ReconnectDelay is the first delay before a reconnection, doubled up
+	// to a minute while the database is down.
+	ReconnectDelay time.Duration
+}
+
+var DefaultSettings = Settings{
+	Buffer:         64,
+	ReconnectDelay: time.Second,
+}
+
+func (s Settings) withDefaults() Settings {
+	if s.Buffer <= 0 {
+		s.Buffer = DefaultSettings.Buffer
+	}
+	if s.ReconnectDelay <= 0 {
+		s.ReconnectDelay = DefaultSettings.ReconnectDelay
+	}
+
+	return s
+}
+
+type Subscription struct {
+	settings Settings
+	logger   *log.Logger
+	connect  func(ctx context.Context) (Conn, error)
+	events   chan Event
+	stop     context.CancelFunc
+	done     chan struct{}
+
+	// overflowed tells that the events dropped since the last one delivered
+	// are followed by an Overflowed.
+	overflowed bool
+}
+
+// Subscribe delivers the events on connections opened with connect until
+// Close.
+func Subscribe(connect func(ctx context.Context) (Conn, error), settings Settings, logger *log.Logger) *Subscription {
+	settings = settings.withDefaults()
+	capacity := settings.Buffer
+	if settings.Drop {
+		// The extra slot holds the Overflowed of a full buffer.
+		capacity++
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	s := &Subscription{
+		settings: settings,
+		logger:   logger,
+		connect:  connect,
+		events:   make(chan Event, capacity),
+		stop:     stop,
+		done:     make(chan struct{}),
+	}
+
+	go s.run(ctx)
+
+	return s
+}
+
+// Events returns the channel of the events, closed by Close.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close stops the subscription and closes Events, without waiting for the
+// consumer to take the events held back.
+func (s *Subscription) Close() {
+	s.stop()
+	<-s.done
+}
+
+func (s *Subscription) run(ctx context.Context) {
+	defer close(s.done)
+	defer close(s.events)
+
+	listener := &listen.Listener{
+		Channel:        Channel,
+		Connect:        s.connect,
+		ReconnectDelay: s.settings.ReconnectDelay,
+		Listened: func(resubscribed bool) {
+			if resubscribed {
+				s.deliver(ctx, Resubscribed{})
+			}
+		},
+		Notify: func(payload string) {
+			s.notify(ctx, payload)
+		},
+		Logger: s.logger,
+		Lost:   "EVENTS! Lost subscription, reconnecting",
+	}
+	listener.Run(ctx)
+}
+
+func (s *Subscription) notify(ctx context.Context, payload string) {
+	event, err := Parse(payload)
+	if err != nil {
+		s.logger.Warn("EVENTS! Skip notification", "payload", payload, "err", err)
+		return
+	}
+	s.deliver(ctx, event)
+}
+
+// deliver buffers event for the consumer, waiting until ctx is done while
+// the buffer is full, or drops it with Drop.
+func (s *Subscription) deliver(ctx context.Context, event Event) {
+	if !s.settings.Drop {
+		select {
+		case s.events <- event:
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	// Only the subscription sends on the channel, so neither send blocks.
+	if len(s.events) < s.settings.Buffer {
+		s.events <- event
+		s.overflowed = false
+		return
+	}
+
+	if !s.overflowed {
+		s.events <- Overflowed{}
+		s.overflowed = true
+		s.logger.Warn("EVENTS! Consumer lags behind, dropping events", "buffer", s.settings.Buffer)
+	}
+}
@@ -0,0 +1,246 @@
+package events_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jackc/pgconn"
+
+	"github.com/nkarakotova/lim-repo/events"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+// notifications is a connection that delivers the payloads sent to it and
+// is lost when payloads is closed.
+type notifications struct {
+	payloads chan string
+	listened chan string
+
+	mutex sync.Mutex
+	waits int
+}
+
+func newNotifications() *notifications {
+	return &notifications{payloads: make(chan string), listened: make(chan string, 1)}
+}
+
+func (n *notifications) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case n.listened <- sql:
+		return pgconn.CommandTag("LISTEN"), nil
+	}
+}
+
+func (n *notifications) WaitForNotification(ctx context.Context) (*pgconn.Notification, error) {
+	n.mutex.Lock()
+	n.waits++
+	n.mutex.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case payload, ok := <-n.payloads:
+		if !ok {
+			return nil, errors.New("connection lost")
+		}
+		return &pgconn.Notification{Channel: events.Channel, Payload: payload}, nil
+	}
+}
+
+func (n *notifications) Close(ctx context.Context) error {
+	return nil
+}
+
+func (n *notifications) waited() int {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	return n.waits
+}
+
+type EventsSuite struct {
+	suite.Suite
+	output bytes.Buffer
+	logger *log.Logger
+}
+
+func (s *EventsSuite) BeforeEach(t provider.T) {
+	s.output.Reset()
+	s.logger = log.NewWithOptions(&s.output, log.Options{Level: log.InfoLevel})
+}
+
+func (s *EventsSuite) subscribe(settings events.Settings, conns ...*notifications) *events.Subscription {
+	var mutex sync.Mutex
+	return events.Subscribe(func(ctx context.Context) (events.Conn, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if len(conns) == 0 {
+			return nil, errors.New("connection refused")
+		}
+		conn := conns[0]
+		conns = conns[1:]
+		return conn, nil
+	}, settings, s.logger)
+}
+
+func (s *EventsSuite) TestParse(t provider.T) {
+	t.Title("Events: Payloads of the triggers parse to typed events")
+	t.Tags("Events")
+	t.WithNewStep("Parse", func(sCtx provider.StepCtx) {
+		payloads := map[string]events.Event{
+			`{"type" : "training_created", "training_id" : 12}`:                   events.TrainingCreated{TrainingID: 12},
+			`{"type" : "training_deleted", "training_id" : 12}`:                   events.TrainingDeleted{TrainingID: 12},
+			`{"type" : "booking_created", "client_id" : 7, "training_id" : 12}`:   events.BookingCreated{ClientID: 7, TrainingID: 12},
+			`{"type" : "booking_cancelled", "client_id" : 7, "training_id" : 12}`: events.BookingCancelled{ClientID: 7, TrainingID: 12},
+		}
+		for payload, expected := range payloads {
+			event, err := events.Parse(payload)
+			sCtx.Require().NoError(err)
+			sCtx.Assert().Equal(expected, event)
+		}
+	})
+	t.WithNewStep("Invalid", func(sCtx provider.StepCtx) {
+		_, err := events.Parse(`{"type" : "coach_created"}`)
+		sCtx.Assert().Error(err)
+		_, err = events.Parse(`booking_created`)
+		sCtx.Assert().Error(err)
+	})
+}
+
+func (s *EventsSuite) TestDeliver(t provider.T) {
+	t.Title("Events: Notifications are delivered in order, invalid ones skipped")
+	t.Tags("Events")
+	t.WithNewStep("Deliver", func(sCtx provider.StepCtx) {
+		conn := newNotifications()
+		subscription := s.subscribe(events.Settings{Buffer: 4}, conn)
+		sCtx.Assert().Equal("listen "+events.Channel, <-conn.listened)
+
+		conn.payloads <- `{"type" : "booking_created", "client_id" : 7, "training_id" : 12}`
+		conn.payloads <- `not json`
+		conn.payloads <- `{"type" : "training_deleted", "training_id" : 12}`
+
+		sCtx.Assert().Equal(events.BookingCreated{ClientID: 7, TrainingID: 12}, <-subscription.Events())
+		sCtx.Assert().Equal(events.TrainingDeleted{TrainingID: 12}, <-subscription.Events())
+		sCtx.Assert().Contains(s.output.String(), "EVENTS! Skip notification")
+
+		subscription.Close()
+		_, open := <-subscription.Events()
+		sCtx.Assert().False(open)
+	})
+}
+
+func (s *EventsSuite) TestBackpressure(t provider.T) {
+	t.Title("Events: A full channel holds the notifications back")
+	t.Tags("Events")
+	t.WithNewStep("Backpressure", func(sCtx provider.StepCtx) {
+		conn := newNotifications()
+		subscription := s.subscribe(events.Settings{Buffer: 1}, conn)
+		<-conn.listened
+
+		conn.payloads <- `{"type" : "training_created", "training_id" : 1}`
+		conn.payloads <- `{"type" : "training_created", "training_id" : 2}`
+		for conn.waited() < 2 {
+			time.Sleep(time.Millisecond)
+		}
+
+		// The first event is buffered, the second waits to be taken.
+		select {
+		case conn.payloads <- `{"type" : "training_created", "training_id" : 3}`:
+			sCtx.Errorf("a notification was read ahead of the consumer")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		sCtx.Assert().Equal(events.TrainingCreated{TrainingID: 1}, <-subscription.Events())
+		sCtx.Assert().Equal(events.TrainingCreated{TrainingID: 2}, <-subscription.Events())
+		sCtx.Assert().NotContains(s.output.String(), "EVENTS! Consumer lags behind")
+
+		// Close does not wait for a consumer.
+		conn.payloads <- `{"type" : "training_created", "training_id" : 3}`
+		conn.payloads <- `{"type" : "training_created", "training_id" : 4}`
+		subscription.Close()
+	})
+}
+
+func (s *EventsSuite) TestOverflow(t provider.T) {
+	t.Title("Events: A full channel with Drop drops the events and tells it with Overflowed")
+	t.Tags("Events")
+	t.WithNewStep("Overflow", func(sCtx provider.StepCtx) {
+		conn := newNotifications()
+		subscription := s.subscribe(events.Settings{Buffer: 1, Drop: true}, conn)
+		<-conn.listened
+
+		// The notifications are read however slow the consumer is.
+		for id := 1; id <= 4; id++ {
+			select {
+			case conn.payloads <- fmt.Sprintf(`{"type" : "training_created", "training_id" : %d}`, id):
+			case <-time.After(time.Second):
+				sCtx.Errorf("notification %d was not read", id)
+			}
+		}
+		for conn.waited() < 5 {
+			time.Sleep(time.Millisecond)
+		}
+
+		sCtx.Assert().Equal(events.TrainingCreated{TrainingID: 1}, <-subscription.Events())
+		sCtx.Assert().Equal(events.Overflowed{}, <-subscription.Events())
+		sCtx.Assert().Contains(s.output.String(), "EVENTS! Consumer lags behind")
+
+		conn.payloads <- `{"type" : "training_created", "training_id" : 5}`
+		sCtx.Assert().Equal(events.TrainingCreated{TrainingID: 5}, <-subscription.Events())
+
+		// Close does not wait for a consumer.
+		conn.payloads <- `{"type" : "training_created", "training_id" : 6}`
+		conn.payloads <- `{"type" : "training_created", "training_id" : 7}`
+		subscription.Close()
+	})
+}
+
+func (s *EventsSuite) TestReconnect(t provider.T) {
+	t.Title("Events: A lost subscription is reopened and marked with Resubscribed")
+	t.Tags("Events")
+	t.WithNewStep("Reconnect", func(sCtx provider.StepCtx) {
+		first, second := newNotifications(), newNotifications()
+		subscription := s.subscribe(events.Settings{Buffer: 4, ReconnectDelay: time.Millisecond}, first, second)
+		defer subscription.Close()
+		<-first.listened
+
+		close(first.payloads)
+		sCtx.Assert().Equal("listen "+events.Channel, <-second.listened)
+		sCtx.Assert().Equal(events.Resubscribed{}, <-subscription.Events())
+
+		second.payloads <- `{"type" : "booking_cancelled", "client_id" : 7, "training_id" : 12}`
+		sCtx.Assert().Equal(events.BookingCancelled{ClientID: 7, TrainingID: 12}, <-subscription.Events())
+		sCtx.Assert().Contains(s.output.String(), "EVENTS! Lost subscription")
+	})
+	t.WithNewStep("Refused", func(sCtx provider.StepCtx) {
+		conn := newNotifications()
+		refused := true
+		subscription := events.Subscribe(func(ctx context.Context) (events.Conn, error) {
+			if refused {
+				refused = false
+				return nil, errors.New("connection refused")
+			}
+			return conn, nil
+		}, events.Settings{ReconnectDelay: time.Millisecond}, s.logger)
+		defer subscription.Close()
+
+		// Nothing was missed before the first subscription.
+		<-conn.listened
+		conn.payloads <- `{"type" : "training_created", "training_id" : 5}`
+		sCtx.Assert().Equal(events.TrainingCreated{TrainingID: 5}, <-subscription.Events())
+	})
+}
+
+func TestEventsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(EventsSuite))
+}
@@ -0,0 +1,31 @@
+package flags
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/nkarakotova/lim-repo/querylog"
+	"github.com/nkarakotova/lim-repo/sqlhook"
+	"github.com/nkarakotova/lim-repo/tracing"
+)
+
+// openDB opens the database with the hooks the flags ask for: tracing with
+// trace and query logging when logger logs debug or slowQuery is set.
+func openDB(driverName, dsn string, trace bool, slowQuery time.Duration, explain bool, logger *log.Logger) (*sql.DB, error) {
+	var hooks []sqlhook.Hook
+	if trace {
+		hooks = append(hooks, tracing.NewHook(driverName))
+	}
+	queryLog := querylog.Config{SlowThreshold: slowQuery, Explain: explain}
+	if queryLog.Enabled(logger) {
+		hooks = append(hooks, querylog.NewHook(logger, queryLog))
+	}
+
+	if len(hooks) == 0 {
+		return sql.Open(driverName, dsn)
+	}
+
+	return sqlhook.OpenDB(driverName, dsn, hooks...)
+}
@@ -1,10 +1,26 @@
 package flags
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
+
 	"github.com/charmbracelet/log"
-	_ "github.com/jackc/pgx/stdlib"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	_ "github.com/jackc/pgx/v4/stdlib"
+
+	"github.com/nkarakotova/lim-repo/querylog"
+)
+
+const (
+	// DriverStdlib runs the repositories on database/sql with the pgx stdlib
+	// driver. It is used when Driver is empty.
+	DriverStdlib = "stdlib"
+	// DriverPgxPool runs the repositories on a native pgxpool.Pool with
+	// the binary protocol, batching and COPY.
+	DriverPgxPool = "pgxpool"
 )
 
 type PostgresFlags struct {
@@ -13,17 +29,70 @@ type PostgresFlags struct {
 	Password string `mapstructure:"password"`
 	Port     string `mapstructure:"port"`
 	DBName   string `mapstructure:"dbname"`
+	Driver   string `mapstructure:"driver"`
+	// Trace emits an OpenTelemetry span for every repository call and every
+	// statement on DB, see the tracing package.
+	Trace bool `mapstructure:"trace"`
+	// SlowQuery is the duration from which a statement is logged at warn
+	// level, see the querylog package. Explain adds the plan of the
+	// statements on DB.
+	SlowQuery time.Duration `mapstructure:"slow_query"`
+	Explain   bool          `mapstructure:"explain"`
+	// MaxRetries is the number of times a call failed on a transient error
+	// is retried, see the retry package. Zero disables retries.
+	MaxRetries int `mapstructure:"max_retries"`
+	// BreakerFailureRatio is the share of calls failed on the database
+	// that opens the circuit breaker for BreakerOpenTimeout, see the breaker
+	// package. Zero disables the breaker.
+	BreakerFailureRatio float64       `mapstructure:"breaker_failure_ratio"`
+	BreakerOpenTimeout  time.Duration `mapstructure:"breaker_open_timeout"`
+	Timeouts            TimeoutFlags  `mapstructure:"timeouts"`
+	// Replicas are the key=value DSNs of the read replicas the schedule browsing
+	// reads go to while their lag is at most ReplicaMaxLag, see the replica
+	// package. They run on database/sql whatever Driver is.
+	Replicas      []string      `mapstructure:"replicas"`
+	ReplicaMaxLag time.Duration `mapstructure:"replica_max_lag"`
+	// CacheSize is the number of coach, hall and schedule lookups cached
+	// per lookup for CacheTTL, see the cache package. Zero disables the
+	// caches.
+	CacheSize int           `mapstructure:"cache_size"`
+	CacheTTL  time.Duration `mapstructure:"cache_ttl"`
+	// TimeZone is the IANA time zone of the studio, the TimeZone of every
+	// session, the time zone of the server when empty. The date times are
+	// stored in UTC; the training hours and the coach availability are
+	// taken in TimeZone, and so are the date times the migrations convert
+	// to UTC.
+	TimeZone string `mapstructure:"time_zone"`
+}
+
+// connDSN is the DSN of the connections outside the repository pools,
+// without the session timeouts.
+func (p *PostgresFlags) connDSN() string {
+	return fmt.Sprintf("user=%s dbname=%s password=%s host=%s port=%s sslmode=disable",
+		p.User, p.DBName, p.Password,
+		p.Host, p.Port) + p.timeZoneParam()
+}
+
+// dsn is the DSN of the repository pools.
+func (p *PostgresFlags) dsn() string {
+	return p.connDSN() + p.Timeouts.sessionParams()
+}
+
+// timeZoneParam is the run-time parameter of the session setting its
+// TimeZone, in the key=value form of a DSN.
+func (p *PostgresFlags) timeZoneParam() string {
+	if p.TimeZone == "" {
+		return ""
+	}
+
+	return " timezone=" + p.TimeZone
 }
 
 func (p *PostgresFlags) InitDB(logger *log.Logger) (*sql.DB, error) {
 	logger.Debug("POSTGRES! Start init postgreSQL", "user", p.User, "DBName", p.DBName,
 		"host", p.Host, "port", p.Port)
 
-	dsnPGConn := fmt.Sprintf("user=%s dbname=%s password=%s host=%s port=%s sslmode=disable",
-		p.User, p.DBName, p.Password,
-		p.Host, p.Port)
-
-	db, err := sql.Open("pgx", dsnPGConn)
+	db, err := openDB("pgx", p.dsn(), p.Trace, p.SlowQuery, p.Explain, logger)
 	if err != nil {
 		logger.Fatal("POSTGRES! Error in method open")
 		return nil, err
@@ -40,4 +109,59 @@ func (p *PostgresFlags) InitDB(logger *log.Logger) (*sql.DB, error) {
 
 	logger.Info("POSTGRES! Successfully init postgreSQL")
 	return db, nil
-}
\ No newline at end of file
+}
+
+// InitReplicas opens the Replicas with the hooks, the session timeouts and
+// the time zone of the primary. A replica is not pinged: the router leaves
+// it out while it is down.
+func (p *PostgresFlags) InitReplicas(logger *log.Logger) ([]*sql.DB, error) {
+	var replicas []*sql.DB
+	for i, dsn := range p.Replicas {
+		db, err := openDB("pgx", dsn+p.Timeouts.sessionParams()+p.timeZoneParam(), p.Trace, p.SlowQuery, p.Explain, logger)
+		if err != nil {
+			logger.Error("POSTGRES! Error in method open", "replica", i)
+			for _, replica := range replicas {
+				replica.Close()
+			}
+			return nil, err
+		}
+		db.SetMaxOpenConns(10)
+		replicas = append(replicas, db)
+	}
+
+	logger.Info("POSTGRES! Successfully init replicas", "count", len(replicas))
+	return replicas, nil
+}
+
+// Connect opens a single connection outside DB and the pool, to LISTEN on.
+// It has no session timeouts.
+func (p *PostgresFlags) Connect(ctx context.Context) (*pgx.Conn, error) {
+	return pgx.Connect(ctx, p.connDSN())
+}
+
+func (p *PostgresFlags) InitPool(logger *log.Logger) (*pgxpool.Pool, error) {
+	logger.Debug("POSTGRES! Start init pgxpool", "user", p.User, "DBName", p.DBName,
+		"host", p.Host, "port", p.Port)
+
+	config, err := pgxpool.ParseConfig(p.dsn())
+	if err != nil {
+		logger.Error("POSTGRES! Error in method parse config")
+		return nil, err
+	}
+	config.MaxConns = 10
+
+	queryLog := querylog.Config{SlowThreshold: p.SlowQuery}
+	if queryLog.Enabled(logger) {
+		config.ConnConfig.Logger = querylog.NewPgxLogger(logger, queryLog)
+		config.ConnConfig.LogLevel = pgx.LogLevelInfo
+	}
+
+	pool, err := pgxpool.ConnectConfig(context.Background(), config)
+	if err != nil {
+		logger.Error("POSTGRES! Error in method connect")
+		return nil, err
+	}
+
+	logger.Info("POSTGRES! Successfully init pgxpool")
+	return pool, nil
+}
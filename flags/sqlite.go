@@ -0,0 +1,46 @@
+package flags
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+	_ "modernc.org/sqlite"
+)
+
+type SQLiteFlags struct {
+	Path string `mapstructure:"path"`
+	// Trace emits an OpenTelemetry span for every repository call and every
+	// statement, see the tracing package.
+	Trace bool `mapstructure:"trace"`
+	// SlowQuery is the duration from which a statement is logged at warn
+	// level, see the querylog package. Explain adds the query plan.
+	SlowQuery time.Duration `mapstructure:"slow_query"`
+	Explain   bool          `mapstructure:"explain"`
+}
+
+func (s *SQLiteFlags) InitDB(logger *log.Logger) (*sql.DB, error) {
+	logger.Debug("SQLITE! Start init sqlite", "path", s.Path)
+
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", s.Path)
+
+	db, err := openDB("sqlite", dsn, s.Trace, s.SlowQuery, s.Explain, logger)
+	if err != nil {
+		logger.Error("SQLITE! Error in method open")
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		logger.Error("SQLITE! Error in method ping")
+		return nil, err
+	}
+
+	// SQLite has a single writer, and every connection to ":memory:" opens
+	// its own database.
+	db.SetMaxOpenConns(1)
+
+	logger.Info("SQLITE! Successfully init sqlite")
+	return db, nil
+}
@@ -0,0 +1,62 @@
+package flags
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nkarakotova/lim-repo/timeout"
+)
+
+// TimeoutFlags are the default timeouts of the repository calls whose
+// context has no deadline, by class of operation, see the timeout package,
+// and the timeouts of the sessions of the repository pools. The LISTEN
+// connections and the migrations run without the latter. A zero one takes
+// its default, a negative one disables it.
+type TimeoutFlags struct {
+	PointRead time.Duration `mapstructure:"point_read"`
+	List      time.Duration `mapstructure:"list"`
+	Write     time.Duration `mapstructure:"write"`
+	Report    time.Duration `mapstructure:"report"`
+	// Statement is statement_timeout of the session, the report timeout by
+	// default. IdleInTransaction is its idle_in_transaction_session_timeout,
+	// a minute by default.
+	Statement         time.Duration `mapstructure:"statement"`
+	IdleInTransaction time.Duration `mapstructure:"idle_in_transaction"`
+}
+
+const defaultIdleInTransactionTimeout = time.Minute
+
+func orDefault(value, def time.Duration) time.Duration {
+	if value == 0 {
+		return def
+	}
+
+	return value
+}
+
+// Timeouts returns the timeouts of the operations with the defaults of
+// timeout.DefaultTimeouts.
+func (t TimeoutFlags) Timeouts() timeout.Timeouts {
+	return timeout.Timeouts{
+		PointRead: orDefault(t.PointRead, timeout.DefaultTimeouts.PointRead),
+		List:      orDefault(t.List, timeout.DefaultTimeouts.List),
+		Write:     orDefault(t.Write, timeout.DefaultTimeouts.Write),
+		Report:    orDefault(t.Report, timeout.DefaultTimeouts.Report),
+	}
+}
+
+// sessionParams are the run-time parameters of the session setting its
+// timeouts, in the key=value form of a DSN.
+func (t TimeoutFlags) sessionParams() string {
+	params := ""
+	statement := orDefault(t.Statement, t.Timeouts().Report)
+	if statement > 0 {
+		params += fmt.Sprintf(" statement_timeout=%d", statement.Milliseconds())
+	}
+	idle := orDefault(t.IdleInTransaction, defaultIdleInTransactionTimeout)
+	if idle > 0 {
+		params += fmt.Sprintf(" idle_in_transaction_session_timeout=%d", idle.Milliseconds())
+	}
+
+	return params
+}
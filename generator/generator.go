@@ -0,0 +1,328 @@
+// Package generator builds believable studio datasets for load and demo
+// environments: clients with valid telephones and mails, trainings in the
+// training hours, and bookings that peak in the mornings and the evenings
+// while clients come and go.
+//
+// Generate is deterministic, the same Config always gives the same Dataset.
+// Load writes a dataset through the repositories of any backend,
+// postgreSQL.ImportDataset copies it into an empty Postgres in bulk.
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/services"
+)
+
+var ErrInvalidConfig = errors.New("Generator error! Некорректная конфигурация генератора!")
+
+type Config struct {
+	// Seed makes the dataset reproducible.
+	Seed    int64
+	Clients int
+	Coaches int
+	Halls   int
+	// From is the first day of the schedule and Days the number of days in it.
+	From time.Time
+	Days int
+	// Density is the share of hours a hall has a training at in the busiest
+	// hours, from 0 to 1.
+	Density float64
+	// Occupancy is the share of places booked in the busiest hours, from 0
+	// to 1.
+	Occupancy float64
+	// Churn is the share of clients that leave the studio in a month, from 0
+	// to 1.
+	Churn float64
+	// FirstTrainingTime and LastTrainingTime are the training hours, the
+	// last one excluded, as in config.Config: those of the services when
+	// they set no range.
+	FirstTrainingTime int
+	LastTrainingTime  int
+}
+
+// Sizes are ready configurations by name.
+var Sizes = map[string]Config{
+	"small": {
+		Seed: 1, Clients: 300, Coaches: 5, Halls: 2,
+		From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Days: 30,
+		Density: 0.7, Occupancy: 0.8, Churn: 0.05,
+	},
+	"medium": {
+		Seed: 1, Clients: 3000, Coaches: 15, Halls: 4,
+		From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Days: 365,
+		Density: 0.7, Occupancy: 0.8, Churn: 0.05,
+	},
+	"large": {
+		Seed: 1, Clients: 10000, Coaches: 30, Halls: 8,
+		From: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), Days: 3 * 365,
+		Density: 0.7, Occupancy: 0.8, Churn: 0.05,
+	},
+}
+
+func (c Config) validate() error {
+	if c.Clients < 0 || c.Coaches < 0 || c.Halls < 0 || c.Days < 0 {
+		return fmt.Errorf("%w: negative size", ErrInvalidConfig)
+	}
+	for _, share := range []float64{c.Density, c.Occupancy, c.Churn} {
+		if share < 0 || share > 1 {
+			return fmt.Errorf("%w: shares must be from 0 to 1", ErrInvalidConfig)
+		}
+	}
+
+	if c.FirstTrainingTime < 0 || c.LastTrainingTime > 24 {
+		return fmt.Errorf("%w: training hours must be from 0 to 24", ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+// trainingHours returns the training hours of the config, those of the
+// services when it sets no range.
+func (c Config) trainingHours() (int, int) {
+	if c.LastTrainingTime <= c.FirstTrainingTime {
+		return services.FirstTrainingTime, services.LastTrainingTime
+	}
+
+	return c.FirstTrainingTime, c.LastTrainingTime
+}
+
+// Booking is a client booked on a training of a Dataset.
+type Booking struct {
+	ClientID   uint64
+	TrainingID uint64
+}
+
+// Dataset is a generated studio. The ID of an entity is its position in its
+// slice plus one, trainings and bookings refer to these IDs.
+type Dataset struct {
+	Clients   []models.Client
+	Coaches   []models.Coach
+	Halls     []models.Hall
+	Trainings []models.Training
+	Bookings  []Booking
+}
+
+// membership is the days [join, leave) a client is active on.
+type membership struct {
+	join  int
+	leave int
+}
+
+// regulars is the share of clients that are members from the first day, the
+// others join over the period.
+const regulars = 0.4
+
+// monthDays is the length of a month for Config.Churn.
+const monthDays = 30
+
+func Generate(cfg Config) (*Dataset, error) {
+	err := cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generation{
+		cfg:        cfg,
+		rng:        rand.New(rand.NewSource(cfg.Seed)),
+		telephones: make(map[string]bool, cfg.Clients),
+		coachNames: make(map[string]bool, cfg.Coaches),
+	}
+
+	for i := 0; i < cfg.Coaches; i++ {
+		g.addCoach()
+	}
+	for i := 0; i < cfg.Halls; i++ {
+		g.dataset.Halls = append(g.dataset.Halls, models.Hall{ID: uint64(i + 1), Number: uint64(i + 1)})
+	}
+	for i := 0; i < cfg.Clients; i++ {
+		g.addClient()
+	}
+
+	start := time.Date(cfg.From.Year(), cfg.From.Month(), cfg.From.Day(), 0, 0, 0, 0, cfg.From.Location())
+	for day := 0; day < cfg.Days; day++ {
+		g.addDay(day, start.AddDate(0, 0, day))
+	}
+
+	return &g.dataset, nil
+}
+
+type generation struct {
+	cfg     Config
+	rng     *rand.Rand
+	dataset Dataset
+
+	telephones  map[string]bool
+	coachNames  map[string]bool
+	memberships []membership
+}
+
+var (
+	femaleNames = []string{"Anna", "Maria", "Elena", "Olga", "Natalia", "Irina", "Ekaterina", "Svetlana", "Daria", "Polina", "Alina", "Sofia", "Victoria", "Ksenia", "Yulia"}
+	maleNames   = []string{"Alexander", "Dmitry", "Sergey", "Andrey", "Alexey", "Maxim", "Ivan", "Mikhail", "Nikita", "Artem", "Pavel", "Egor", "Kirill", "Roman", "Denis"}
+	surnames    = []string{"Ivanov", "Smirnov", "Kuznetsov", "Popov", "Vasiliev", "Petrov", "Sokolov", "Mikhailov", "Novikov", "Fedorov", "Morozov", "Volkov", "Alexeev", "Lebedev", "Semenov", "Egorov", "Pavlov", "Kozlov", "Stepanov", "Nikolaev"}
+	domains     = []string{"mail.ru", "yandex.ru", "gmail.com", "inbox.ru", "bk.ru", "list.ru"}
+	trainings   = []string{"Yoga", "Pilates", "Stretching", "Crossfit", "Functional", "Boxing", "Zumba", "Cycling", "TRX", "Body Pump"}
+	placesNums  = []uint64{8, 10, 12, 15, 20}
+)
+
+const passwordAlphabet = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+func (g *generation) pick(values []string) string {
+	return values[g.rng.Intn(len(values))]
+}
+
+// person returns a first name and a surname that agree in gender.
+func (g *generation) person() (string, string) {
+	if g.rng.Intn(3) < 2 {
+		return g.pick(femaleNames), g.pick(surnames) + "a"
+	}
+
+	return g.pick(maleNames), g.pick(surnames)
+}
+
+func (g *generation) addCoach() {
+	first, last := g.person()
+	name := first + " " + last
+	for n := 2; g.coachNames[name]; n++ {
+		name = fmt.Sprintf("%s %s %d", first, last, n)
+	}
+	g.coachNames[name] = true
+
+	g.dataset.Coaches = append(g.dataset.Coaches, models.Coach{ID: uint64(len(g.dataset.Coaches) + 1), Name: name})
+}
+
+// telephone returns an unused Russian mobile number, +79XXXXXXXXX.
+func (g *generation) telephone() string {
+	for {
+		telephone := fmt.Sprintf("+79%09d", g.rng.Intn(1_000_000_000))
+		if !g.telephones[telephone] {
+			g.telephones[telephone] = true
+			return telephone
+		}
+	}
+}
+
+func (g *generation) password() string {
+	password := make([]byte, 12)
+	for i := range password {
+		password[i] = passwordAlphabet[g.rng.Intn(len(passwordAlphabet))]
+	}
+
+	return string(password)
+}
+
+func (g *generation) addClient() {
+	id := uint64(len(g.dataset.Clients) + 1)
+	first, last := g.person()
+
+	g.dataset.Clients = append(g.dataset.Clients, models.Client{
+		ID:        id,
+		Name:      first + " " + last,
+		Telephone: g.telephone(),
+		// The ID keeps the mails unique.
+		Mail:     fmt.Sprintf("%s.%s%d@%s", strings.ToLower(first), strings.ToLower(last), id, g.pick(domains)),
+		Password: g.password(),
+	})
+
+	member := membership{leave: g.cfg.Days}
+	if g.rng.Float64() >= regulars && g.cfg.Days > 0 {
+		member.join = g.rng.Intn(g.cfg.Days)
+	}
+	if g.cfg.Churn > 0 {
+		stay := int(g.rng.ExpFloat64() * monthDays / g.cfg.Churn)
+		member.leave = min(member.leave, member.join+stay+1)
+	}
+	g.memberships = append(g.memberships, member)
+}
+
+// popularity is the share of Config.Density and Config.Occupancy an hour of
+// a weekday gets. Weekdays peak in the evening and a little in the morning,
+// weekends from late morning to the afternoon.
+func popularity(weekday time.Weekday, hour int) float64 {
+	if weekday == time.Saturday || weekday == time.Sunday {
+		if hour >= 11 && hour < 17 {
+			return 0.9
+		}
+		return 0.5
+	}
+
+	switch {
+	case hour >= 18 && hour < 21:
+		return 1
+	case hour < 12:
+		return 0.6
+	case hour >= 21:
+		return 0.5
+	default:
+		return 0.3
+	}
+}
+
+func (g *generation) addDay(day int, date time.Time) {
+	active := make([]int, 0, len(g.memberships))
+	for client, member := range g.memberships {
+		if member.join <= day && day < member.leave {
+			active = append(active, client)
+		}
+	}
+
+	first, last := g.cfg.trainingHours()
+	for hour := first; hour < last; hour++ {
+		dateTime := date.Add(time.Duration(hour) * time.Hour)
+		weight := popularity(date.Weekday(), hour)
+
+		freeCoaches := make([]int, len(g.dataset.Coaches))
+		for i := range freeCoaches {
+			freeCoaches[i] = i
+		}
+		busyClients := make(map[int]bool)
+
+		for hall := range g.dataset.Halls {
+			if len(freeCoaches) == 0 || g.rng.Float64() >= g.cfg.Density*weight {
+				continue
+			}
+
+			i := g.rng.Intn(len(freeCoaches))
+			coach := freeCoaches[i]
+			freeCoaches = append(freeCoaches[:i], freeCoaches[i+1:]...)
+
+			training := models.Training{
+				ID:        uint64(len(g.dataset.Trainings) + 1),
+				CoachID:   g.dataset.Coaches[coach].ID,
+				HallID:    g.dataset.Halls[hall].ID,
+				Name:      g.pick(trainings),
+				DateTime:  dateTime,
+				PlacesNum: placesNums[g.rng.Intn(len(placesNums))],
+			}
+			g.dataset.Trainings = append(g.dataset.Trainings, training)
+
+			g.book(training, weight, active, busyClients)
+		}
+	}
+}
+
+// book books active clients that are not busy at the time of the training,
+// about Occupancy*weight of its places.
+func (g *generation) book(training models.Training, weight float64, active []int, busy map[int]bool) {
+	jitter := 0.85 + 0.3*g.rng.Float64()
+	booked := int(math.Round(float64(training.PlacesNum) * math.Min(1, g.cfg.Occupancy*weight*jitter)))
+	booked = min(booked, len(active)-len(busy))
+
+	for attempts := 0; booked > 0 && attempts < 8*int(training.PlacesNum); attempts++ {
+		client := active[g.rng.Intn(len(active))]
+		if busy[client] {
+			continue
+		}
+		busy[client] = true
+		booked--
+
+		g.dataset.Bookings = append(g.dataset.Bookings, Booking{ClientID: g.dataset.Clients[client].ID, TrainingID: training.ID})
+	}
+}
@@ -0,0 +1,234 @@
+package generator
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/nkarakotova/lim-core/services"
+	"github.com/nkarakotova/lim-repo/inmemory"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type GeneratorSuite struct {
+	suite.Suite
+}
+
+var (
+	telephonePattern = regexp.MustCompile(`^\+79\d{9}$`)
+	mailPattern      = regexp.MustCompile(`^[a-z]+\.[a-z]+\d+@[a-z]+\.[a-z]+$`)
+)
+
+func (s *GeneratorSuite) TestGenerateDeterministic(t provider.T) {
+	t.Title("[Generate] The same config gives the same dataset")
+	t.Tags("Generator")
+	t.WithNewStep("Deterministic", func(sCtx provider.StepCtx) {
+		first, err := Generate(Sizes["small"])
+		sCtx.Assert().NoError(err)
+		second, err := Generate(Sizes["small"])
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(first, second)
+
+		cfg := Sizes["small"]
+		cfg.Seed = 2
+		other, err := Generate(cfg)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().NotEqual(first.Clients, other.Clients)
+	})
+}
+
+func (s *GeneratorSuite) TestGenerateSmall(t provider.T) {
+	t.Title("[Generate] A small studio is believable")
+	t.Tags("Generator")
+	t.WithNewStep("Small", func(sCtx provider.StepCtx) {
+		cfg := Sizes["small"]
+		dataset, err := Generate(cfg)
+		sCtx.Assert().NoError(err)
+
+		sCtx.Assert().Len(dataset.Clients, cfg.Clients)
+		sCtx.Assert().Len(dataset.Coaches, cfg.Coaches)
+		sCtx.Assert().Len(dataset.Halls, cfg.Halls)
+		sCtx.Assert().NotEmpty(dataset.Trainings)
+		sCtx.Assert().NotEmpty(dataset.Bookings)
+
+		telephones := map[string]bool{}
+		for _, client := range dataset.Clients {
+			sCtx.Assert().Regexp(telephonePattern, client.Telephone)
+			sCtx.Assert().Regexp(mailPattern, client.Mail)
+			sCtx.Assert().False(telephones[client.Telephone], "telephone %s is used twice", client.Telephone)
+			telephones[client.Telephone] = true
+		}
+
+		type slot struct {
+			kind     string
+			id       uint64
+			dateTime time.Time
+		}
+		busy := map[slot]bool{}
+		for _, training := range dataset.Trainings {
+			hour := training.DateTime.Hour()
+			sCtx.Assert().True(hour >= services.FirstTrainingTime && hour < services.LastTrainingTime)
+			sCtx.Assert().False(training.DateTime.Before(cfg.From))
+			sCtx.Assert().True(training.DateTime.Before(cfg.From.AddDate(0, 0, cfg.Days)))
+
+			coach := slot{"coach", training.CoachID, training.DateTime}
+			hall := slot{"hall", training.HallID, training.DateTime}
+			sCtx.Assert().False(busy[coach], "coach %d has two trainings at %v", training.CoachID, training.DateTime)
+			sCtx.Assert().False(busy[hall], "hall %d has two trainings at %v", training.HallID, training.DateTime)
+			busy[coach], busy[hall] = true, true
+		}
+
+		booked := make(map[uint64]uint64)
+		for _, booking := range dataset.Bookings {
+			training := dataset.Trainings[booking.TrainingID-1]
+			client := slot{"client", booking.ClientID, training.DateTime}
+			sCtx.Assert().False(busy[client], "client %d has two trainings at %v", booking.ClientID, training.DateTime)
+			busy[client] = true
+
+			booked[training.ID]++
+			sCtx.Assert().LessOrEqual(booked[training.ID], training.PlacesNum)
+		}
+	})
+}
+
+func (s *GeneratorSuite) TestGeneratePeakHours(t provider.T) {
+	t.Title("[Generate] Weekday evenings are busier than afternoons")
+	t.Tags("Generator")
+	t.WithNewStep("Peak hours", func(sCtx provider.StepCtx) {
+		dataset, err := Generate(Sizes["small"])
+		sCtx.Assert().NoError(err)
+
+		trainings := make(map[uint64]time.Time, len(dataset.Trainings))
+		for _, training := range dataset.Trainings {
+			trainings[training.ID] = training.DateTime
+		}
+
+		var evening, afternoon int
+		for _, booking := range dataset.Bookings {
+			dateTime := trainings[booking.TrainingID]
+			if dateTime.Weekday() == time.Saturday || dateTime.Weekday() == time.Sunday {
+				continue
+			}
+			switch hour := dateTime.Hour(); {
+			case hour >= 18 && hour < 21:
+				evening++
+			case hour >= 13 && hour < 16:
+				afternoon++
+			}
+		}
+		sCtx.Assert().Greater(evening, 2*afternoon)
+	})
+}
+
+func (s *GeneratorSuite) TestGenerateTrainingHours(t provider.T) {
+	t.Title("[Generate] Trainings are in the configured hours")
+	t.Tags("Generator")
+	t.WithNewStep("Training hours", func(sCtx provider.StepCtx) {
+		cfg := Sizes["small"]
+		cfg.FirstTrainingTime, cfg.LastTrainingTime = 8, 12
+		dataset, err := Generate(cfg)
+		sCtx.Require().NoError(err)
+
+		hours := map[int]bool{}
+		for _, training := range dataset.Trainings {
+			hours[training.DateTime.Hour()] = true
+		}
+		sCtx.Assert().Equal(map[int]bool{8: true, 9: true, 10: true, 11: true}, hours)
+	})
+}
+
+func (s *GeneratorSuite) TestGenerateChurn(t provider.T) {
+	t.Title("[Generate] Clients that leave stop booking")
+	t.Tags("Generator")
+	t.WithNewStep("Churn", func(sCtx provider.StepCtx) {
+		cfg := Sizes["small"]
+		cfg.Churn = 0.9
+		churned, err := Generate(cfg)
+		sCtx.Assert().NoError(err)
+
+		cfg.Churn = 0
+		kept, err := Generate(cfg)
+		sCtx.Assert().NoError(err)
+
+		last := cfg.From.AddDate(0, 0, cfg.Days-7)
+		sCtx.Assert().Greater(lateClients(kept, last), lateClients(churned, last))
+	})
+}
+
+// lateClients is the number of clients booked on or after from.
+func lateClients(dataset *Dataset, from time.Time) int {
+	clients := map[uint64]bool{}
+	for _, booking := range dataset.Bookings {
+		if !dataset.Trainings[booking.TrainingID-1].DateTime.Before(from) {
+			clients[booking.ClientID] = true
+		}
+	}
+
+	return len(clients)
+}
+
+func (s *GeneratorSuite) TestGenerateInvalidConfig(t provider.T) {
+	t.Title("[Generate] Invalid config")
+	t.Tags("Generator")
+	t.WithNewStep("Invalid config", func(sCtx provider.StepCtx) {
+		cfg := Sizes["small"]
+		cfg.Occupancy = 1.5
+		_, err := Generate(cfg)
+		sCtx.Assert().ErrorIs(err, ErrInvalidConfig)
+
+		cfg = Sizes["small"]
+		cfg.Clients = -1
+		_, err = Generate(cfg)
+		sCtx.Assert().ErrorIs(err, ErrInvalidConfig)
+
+		cfg = Sizes["small"]
+		cfg.LastTrainingTime = 25
+		_, err = Generate(cfg)
+		sCtx.Assert().ErrorIs(err, ErrInvalidConfig)
+	})
+}
+
+func (s *GeneratorSuite) TestLoad(t provider.T) {
+	t.Title("[Load] The dataset is created in the repositories")
+	t.Tags("Generator")
+	t.WithNewStep("Load", func(sCtx provider.StepCtx) {
+		cfg := Sizes["small"]
+		cfg.Days = 7
+		dataset, err := Generate(cfg)
+		sCtx.Assert().NoError(err)
+
+		ctx := context.TODO()
+		storage := inmemory.NewStorage()
+		repos := Repositories{
+			Client:   inmemory.NewClientInMemoryRepository(storage),
+			Coach:    inmemory.NewCoachInMemoryRepository(storage),
+			Hall:     inmemory.NewHallInMemoryRepository(storage),
+			Training: inmemory.NewTrainingInMemoryRepository(storage),
+		}
+		err = Load(ctx, dataset, repos)
+		sCtx.Assert().NoError(err)
+
+		coaches, err := repos.Coach.GetAll(ctx)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Len(coaches, cfg.Coaches)
+
+		trainings, err := repos.Training.GetAllBetweenDateTime(ctx, cfg.From, cfg.From.AddDate(0, 0, cfg.Days))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Len(trainings, len(dataset.Trainings))
+
+		booked := 0
+		for _, training := range trainings {
+			clients, err := repos.Client.GetByTraining(ctx, training.ID)
+			sCtx.Assert().NoError(err)
+			booked += len(clients)
+		}
+		sCtx.Assert().Equal(len(dataset.Bookings), booked)
+	})
+}
+
+func TestGeneratorSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(GeneratorSuite))
+}
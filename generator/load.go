@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"context"
+
+	"github.com/nkarakotova/lim-core/repositories"
+)
+
+// Repositories are the repositories Load writes to. They must share the
+// storage.
+type Repositories struct {
+	Client   repositories.ClientRepository
+	Coach    repositories.CoachRepository
+	Hall     repositories.HallRepository
+	Training repositories.TrainingRepository
+}
+
+// Load creates the dataset one entity at a time, so it works with every
+// backend. The IDs the repositories assign do not have to match the IDs of
+// the dataset, Load maps them. Every booking takes a place
+// of its training, as booking through the services does. The dataset is not
+// changed.
+func Load(ctx context.Context, dataset *Dataset, repos Repositories) error {
+	coachIDs := make([]uint64, len(dataset.Coaches)+1)
+	for _, coach := range dataset.Coaches {
+		created := coach
+		err := repos.Coach.Create(ctx, &created)
+		if err != nil {
+			return err
+		}
+		coachIDs[coach.ID] = created.ID
+	}
+
+	hallIDs := make([]uint64, len(dataset.Halls)+1)
+	for _, hall := range dataset.Halls {
+		created := hall
+		err := repos.Hall.Create(ctx, &created)
+		if err != nil {
+			return err
+		}
+		hallIDs[hall.ID] = created.ID
+	}
+
+	clientIDs := make([]uint64, len(dataset.Clients)+1)
+	for _, client := range dataset.Clients {
+		created := client
+		err := repos.Client.Create(ctx, &created)
+		if err != nil {
+			return err
+		}
+		clientIDs[client.ID] = created.ID
+	}
+
+	trainingIDs := make([]uint64, len(dataset.Trainings)+1)
+	for _, training := range dataset.Trainings {
+		created := training
+		created.CoachID = coachIDs[training.CoachID]
+		created.HallID = hallIDs[training.HallID]
+
+		err := repos.Training.Create(ctx, &created)
+		if err != nil {
+			return err
+		}
+		trainingIDs[training.ID] = created.ID
+	}
+
+	for _, booking := range dataset.Bookings {
+		err := repos.Client.CreateAssignment(ctx, clientIDs[booking.ClientID], trainingIDs[booking.TrainingID])
+		if err != nil {
+			return err
+		}
+
+		err = repos.Training.ReduceAvailablePlacesNum(ctx, trainingIDs[booking.TrainingID])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+)
+
+type ClientInMemoryRepository struct {
+	storage *Storage
+}
+
+func NewClientInMemoryRepository(storage *Storage) repositories.ClientRepository {
+	return &ClientInMemoryRepository{storage: storage}
+}
+
+func (c *ClientInMemoryRepository) Create(ctx context.Context, client *models.Client) error {
+	defer c.storage.lock(ctx)()
+
+	for _, existing := range c.storage.data.clients {
+		if existing.Telephone == client.Telephone {
+			return extRepositoriesErrors.EntityAlreadyExists
+		}
+	}
+
+	c.storage.data.clientSeq++
+	client.ID = c.storage.data.clientSeq
+	c.storage.data.clients[client.ID] = *client
+
+	return nil
+}
+
+func (c *ClientInMemoryRepository) GetByID(ctx context.Context, id uint64) (*models.Client, error) {
+	defer c.storage.lock(ctx)()
+
+	client, ok := c.storage.data.clients[id]
+	if !ok {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	}
+
+	return &client, nil
+}
+
+func (c *ClientInMemoryRepository) GetByTelephone(ctx context.Context, telephone string) (*models.Client, error) {
+	defer c.storage.lock(ctx)()
+
+	for _, client := range c.storage.data.clients {
+		if client.Telephone == telephone {
+			return &client, nil
+		}
+	}
+
+	return nil, repositoriesErrors.EntityDoesNotExists
+}
+
+func (c *ClientInMemoryRepository) GetByTraining(ctx context.Context, id uint64) ([]models.Client, error) {
+	defer c.storage.lock(ctx)()
+
+	clientModels := []models.Client{}
+	for a := range c.storage.data.assignments {
+		if a.trainingID == id {
+			clientModels = append(clientModels, c.storage.data.clients[a.clientID])
+		}
+	}
+	sort.Slice(clientModels, func(i, j int) bool { return clientModels[i].ID < clientModels[j].ID })
+
+	return clientModels, nil
+}
+
+func (c *ClientInMemoryRepository) CreateAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	defer c.storage.lock(ctx)()
+
+	if _, ok := c.storage.data.clients[clientID]; !ok {
+		return repositoriesErrors.EntityDoesNotExists
+	}
+	if _, ok := c.storage.data.trainings[trainingID]; !ok {
+		return repositoriesErrors.EntityDoesNotExists
+	}
+
+	a := assignment{clientID: clientID, trainingID: trainingID}
+	if _, ok := c.storage.data.assignments[a]; ok {
+		return extRepositoriesErrors.EntityAlreadyExists
+	}
+	c.storage.data.assignments[a] = struct{}{}
+
+	return nil
+}
+
+func (c *ClientInMemoryRepository) DeleteAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	defer c.storage.lock(ctx)()
+
+	a := assignment{clientID: clientID, trainingID: trainingID}
+	if _, ok := c.storage.data.assignments[a]; !ok {
+		return repositoriesErrors.EntityDoesNotExists
+	}
+	delete(c.storage.data.assignments, a)
+
+	return nil
+}
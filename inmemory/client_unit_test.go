@@ -0,0 +1,151 @@
+package inmemory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type ClientSuite struct {
+	suite.Suite
+	storage    *Storage
+	repository repositories.ClientRepository
+	training   *models.Training
+	ctx        context.Context
+}
+
+func (s *ClientSuite) BeforeEach(t provider.T) {
+	s.ctx = context.Background()
+	s.storage = NewStorage()
+	s.repository = NewClientInMemoryRepository(s.storage)
+
+	s.training = postgreSQLObjectMother.CreateTestTraining()
+	if err := NewCoachInMemoryRepository(s.storage).Create(s.ctx, postgreSQLObjectMother.CreateTestCoach()); err != nil {
+		t.Fatalf("error creating coach: %v", err)
+	}
+	if err := NewHallInMemoryRepository(s.storage).Create(s.ctx, postgreSQLObjectMother.CreateTestHall()); err != nil {
+		t.Fatalf("error creating hall: %v", err)
+	}
+	if err := NewTrainingInMemoryRepository(s.storage).Create(s.ctx, s.training); err != nil {
+		t.Fatalf("error creating training: %v", err)
+	}
+}
+
+func (s *ClientSuite) TestClientInMemoryCreateSuccess(t provider.T) {
+	t.Title("ClientInMemoryCreate: Success")
+	t.Tags("Client")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		client := postgreSQLObjectMother.CreateTestClient()
+		client.ID = 0
+
+		err := s.repository.Create(s.ctx, client)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(uint64(1), client.ID)
+	})
+}
+
+func (s *ClientSuite) TestClientInMemoryCreateDuplicateTelephone(t provider.T) {
+	t.Title("ClientInMemoryCreate: Duplicate telephone")
+	t.Tags("Client")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestClient()))
+
+		err := s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestClient())
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.EntityAlreadyExists)
+	})
+}
+
+func (s *ClientSuite) TestClientInMemoryGetByIDSuccess(t provider.T) {
+	t.Title("ClientInMemoryGetByID: Success")
+	t.Tags("Client")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestClient()))
+
+		client, err := s.repository.GetByID(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(postgreSQLObjectMother.CreateTestClient(), client)
+	})
+}
+
+func (s *ClientSuite) TestClientInMemoryGetByIDFailure(t provider.T) {
+	t.Title("ClientInMemoryGetByID: Failure")
+	t.Tags("Client")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		_, err := s.repository.GetByID(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *ClientSuite) TestClientInMemoryGetByTelephoneFailure(t provider.T) {
+	t.Title("ClientInMemoryGetByTelephone: Failure")
+	t.Tags("Client")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		_, err := s.repository.GetByTelephone(s.ctx, "1234567890")
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *ClientSuite) TestClientInMemoryAssignmentSuccess(t provider.T) {
+	t.Title("ClientInMemoryAssignment: Create, get and delete")
+	t.Tags("Client")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		client := postgreSQLObjectMother.CreateTestClient()
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, client))
+
+		sCtx.Assert().NoError(s.repository.CreateAssignment(s.ctx, client.ID, s.training.ID))
+		clients, err := s.repository.GetByTraining(s.ctx, s.training.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Client{*client}, clients)
+
+		sCtx.Assert().NoError(s.repository.DeleteAssignment(s.ctx, client.ID, s.training.ID))
+		clients, err = s.repository.GetByTraining(s.ctx, s.training.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(clients)
+	})
+}
+
+func (s *ClientSuite) TestClientInMemoryCreateAssignmentFailure(t provider.T) {
+	t.Title("ClientInMemoryCreateAssignment: Failure")
+	t.Tags("Client")
+	t.WithNewStep("Unknown client", func(sCtx provider.StepCtx) {
+		err := s.repository.CreateAssignment(s.ctx, 1, s.training.ID)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+	t.WithNewStep("Duplicate", func(sCtx provider.StepCtx) {
+		client := postgreSQLObjectMother.CreateTestClient()
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, client))
+		sCtx.Assert().NoError(s.repository.CreateAssignment(s.ctx, client.ID, s.training.ID))
+
+		err := s.repository.CreateAssignment(s.ctx, client.ID, s.training.ID)
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.EntityAlreadyExists)
+	})
+}
+
+func (s *ClientSuite) TestClientInMemoryDeleteAssignmentFailure(t provider.T) {
+	t.Title("ClientInMemoryDeleteAssignment: Failure")
+	t.Tags("Client")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		err := s.repository.DeleteAssignment(s.ctx, 1, s.training.ID)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func TestClientSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(ClientSuite))
+}
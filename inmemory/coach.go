@@ -0,0 +1,71 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+)
+
+type CoachInMemoryRepository struct {
+	storage *Storage
+}
+
+func NewCoachInMemoryRepository(storage *Storage) repositories.CoachRepository {
+	return &CoachInMemoryRepository{storage: storage}
+}
+
+func (c *CoachInMemoryRepository) Create(ctx context.Context, coach *models.Coach) error {
+	defer c.storage.lock(ctx)()
+
+	for _, existing := range c.storage.data.coaches {
+		if existing.Name == coach.Name {
+			return extRepositoriesErrors.EntityAlreadyExists
+		}
+	}
+
+	c.storage.data.coachSeq++
+	coach.ID = c.storage.data.coachSeq
+	c.storage.data.coaches[coach.ID] = *coach
+
+	return nil
+}
+
+func (c *CoachInMemoryRepository) GetByID(ctx context.Context, id uint64) (*models.Coach, error) {
+	defer c.storage.lock(ctx)()
+
+	coach, ok := c.storage.data.coaches[id]
+	if !ok {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	}
+
+	return &coach, nil
+}
+
+func (c *CoachInMemoryRepository) GetByName(ctx context.Context, name string) (*models.Coach, error) {
+	defer c.storage.lock(ctx)()
+
+	for _, coach := range c.storage.data.coaches {
+		if coach.Name == name {
+			return &coach, nil
+		}
+	}
+
+	return nil, repositoriesErrors.EntityDoesNotExists
+}
+
+func (c *CoachInMemoryRepository) GetAll(ctx context.Context) ([]models.Coach, error) {
+	defer c.storage.lock(ctx)()
+
+	coachModels := []models.Coach{}
+	for _, coach := range c.storage.data.coaches {
+		coachModels = append(coachModels, coach)
+	}
+	sort.Slice(coachModels, func(i, j int) bool { return coachModels[i].ID < coachModels[j].ID })
+
+	return coachModels, nil
+}
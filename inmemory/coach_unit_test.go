@@ -0,0 +1,79 @@
+package inmemory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type CoachSuite struct {
+	suite.Suite
+	repository repositories.CoachRepository
+	ctx        context.Context
+}
+
+func (s *CoachSuite) BeforeEach(t provider.T) {
+	s.repository = NewCoachInMemoryRepository(NewStorage())
+	s.ctx = context.Background()
+}
+
+func (s *CoachSuite) TestCoachInMemoryCreateDuplicateName(t provider.T) {
+	t.Title("CoachInMemoryCreate: Duplicate name")
+	t.Tags("Coach")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestCoach()))
+
+		err := s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestCoach())
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.EntityAlreadyExists)
+	})
+}
+
+func (s *CoachSuite) TestCoachInMemoryGetByNameSuccess(t provider.T) {
+	t.Title("CoachInMemoryGetByName: Success")
+	t.Tags("Coach")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestCoach()))
+
+		coach, err := s.repository.GetByName(s.ctx, "Name")
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(postgreSQLObjectMother.CreateTestCoach(), coach)
+	})
+}
+
+func (s *CoachSuite) TestCoachInMemoryGetByIDFailure(t provider.T) {
+	t.Title("CoachInMemoryGetByID: Failure")
+	t.Tags("Coach")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		_, err := s.repository.GetByID(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *CoachSuite) TestCoachInMemoryGetAllSuccess(t provider.T) {
+	t.Title("CoachInMemoryGetAll: Success")
+	t.Tags("Coach")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, &models.Coach{Name: "B"}))
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, &models.Coach{Name: "A"}))
+
+		coaches, err := s.repository.GetAll(s.ctx)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Coach{{ID: 1, Name: "B"}, {ID: 2, Name: "A"}}, coaches)
+	})
+}
+
+func TestCoachSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(CoachSuite))
+}
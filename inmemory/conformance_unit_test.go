@@ -0,0 +1,22 @@
+package inmemory
+
+import (
+	"testing"
+
+	"github.com/nkarakotova/lim-repo/repotest"
+
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+func TestConformanceSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, &repotest.Suite{New: func() (repotest.Repositories, func(), error) {
+		storage := NewStorage()
+
+		return repotest.Repositories{
+			Client:   NewClientInMemoryRepository(storage),
+			Coach:    NewCoachInMemoryRepository(storage),
+			Hall:     NewHallInMemoryRepository(storage),
+			Training: NewTrainingInMemoryRepository(storage),
+		}, func() {}, nil
+	}})
+}
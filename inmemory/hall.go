@@ -0,0 +1,69 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+)
+
+type HallInMemoryRepository struct {
+	storage *Storage
+}
+
+func NewHallInMemoryRepository(storage *Storage) repositories.HallRepository {
+	return &HallInMemoryRepository{storage: storage}
+}
+
+func (h *HallInMemoryRepository) Create(ctx context.Context, hall *models.Hall) error {
+	defer h.storage.lock(ctx)()
+
+	for _, existing := range h.storage.data.halls {
+		if existing.Number == hall.Number {
+			return extRepositoriesErrors.EntityAlreadyExists
+		}
+	}
+
+	h.storage.data.hallSeq++
+	hall.ID = h.storage.data.hallSeq
+	h.storage.data.halls[hall.ID] = *hall
+
+	return nil
+}
+
+func (h *HallInMemoryRepository) GetByID(ctx context.Context, id uint64) (*models.Hall, error) {
+	defer h.storage.lock(ctx)()
+
+	hall, ok := h.storage.data.halls[id]
+	if !ok {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	}
+
+	return &hall, nil
+}
+
+func (h *HallInMemoryRepository) GetByNumber(ctx context.Context, number uint64) (*models.Hall, error) {
+	defer h.storage.lock(ctx)()
+
+	for _, hall := range h.storage.data.halls {
+		if hall.Number == number {
+			return &hall, nil
+		}
+	}
+
+	return nil, repositoriesErrors.EntityDoesNotExists
+}
+
+func (h *HallInMemoryRepository) GetAll(ctx context.Context) (map[uint64]models.Hall, error) {
+	defer h.storage.lock(ctx)()
+
+	hallModels := make(map[uint64]models.Hall, len(h.storage.data.halls))
+	for id, hall := range h.storage.data.halls {
+		hallModels[id] = hall
+	}
+
+	return hallModels, nil
+}
@@ -0,0 +1,78 @@
+package inmemory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type HallSuite struct {
+	suite.Suite
+	repository repositories.HallRepository
+	ctx        context.Context
+}
+
+func (s *HallSuite) BeforeEach(t provider.T) {
+	s.repository = NewHallInMemoryRepository(NewStorage())
+	s.ctx = context.Background()
+}
+
+func (s *HallSuite) TestHallInMemoryCreateDuplicateNumber(t provider.T) {
+	t.Title("HallInMemoryCreate: Duplicate number")
+	t.Tags("Hall")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestHall()))
+
+		err := s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestHall())
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.EntityAlreadyExists)
+	})
+}
+
+func (s *HallSuite) TestHallInMemoryGetByNumberSuccess(t provider.T) {
+	t.Title("HallInMemoryGetByNumber: Success")
+	t.Tags("Hall")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestHall()))
+
+		hall, err := s.repository.GetByNumber(s.ctx, postgreSQLObjectMother.CreateTestHall().Number)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(postgreSQLObjectMother.CreateTestHall(), hall)
+	})
+}
+
+func (s *HallSuite) TestHallInMemoryGetByIDFailure(t provider.T) {
+	t.Title("HallInMemoryGetByID: Failure")
+	t.Tags("Hall")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		_, err := s.repository.GetByID(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *HallSuite) TestHallInMemoryGetAllSuccess(t provider.T) {
+	t.Title("HallInMemoryGetAll: Success")
+	t.Tags("Hall")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, &models.Hall{Number: 7}))
+
+		halls, err := s.repository.GetAll(s.ctx)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(map[uint64]models.Hall{1: {ID: 1, Number: 7}}, halls)
+	})
+}
+
+func TestHallSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(HallSuite))
+}
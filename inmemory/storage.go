@@ -0,0 +1,123 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nkarakotova/lim-core/models"
+
+	trmcontext "github.com/avito-tech/go-transaction-manager/trm/v2/context"
+)
+
+type assignment struct {
+	clientID   uint64
+	trainingID uint64
+}
+
+type trainingRecord struct {
+	training           models.Training
+	availablePlacesNum uint64
+}
+
+// Snapshot is a copy of the whole Storage state. It is used to roll back
+// transactions and can be used by tests to reset the storage between cases.
+type Snapshot struct {
+	clients     map[uint64]models.Client
+	coaches     map[uint64]models.Coach
+	halls       map[uint64]models.Hall
+	trainings   map[uint64]trainingRecord
+	assignments map[assignment]struct{}
+
+	clientSeq   uint64
+	coachSeq    uint64
+	hallSeq     uint64
+	trainingSeq uint64
+}
+
+// Storage keeps the data of all in-memory repositories. It follows the
+// constraints of the PostgreSQL schema: unique client telephone, coach name,
+// hall number and client assignment, foreign keys with cascade deletion.
+//
+// Operations outside of a transaction wait until the running transaction is
+// finished, so a transaction never observes foreign writes and nobody
+// observes its uncommitted ones.
+type Storage struct {
+	txMu sync.Mutex
+	mu   sync.Mutex
+	data Snapshot
+}
+
+func NewStorage() *Storage {
+	return &Storage{data: emptySnapshot()}
+}
+
+func emptySnapshot() Snapshot {
+	return Snapshot{
+		clients:     map[uint64]models.Client{},
+		coaches:     map[uint64]models.Coach{},
+		halls:       map[uint64]models.Hall{},
+		trainings:   map[uint64]trainingRecord{},
+		assignments: map[assignment]struct{}{},
+	}
+}
+
+func (s Snapshot) clone() Snapshot {
+	c := s
+	c.clients = make(map[uint64]models.Client, len(s.clients))
+	for k, v := range s.clients {
+		c.clients[k] = v
+	}
+	c.coaches = make(map[uint64]models.Coach, len(s.coaches))
+	for k, v := range s.coaches {
+		c.coaches[k] = v
+	}
+	c.halls = make(map[uint64]models.Hall, len(s.halls))
+	for k, v := range s.halls {
+		c.halls[k] = v
+	}
+	c.trainings = make(map[uint64]trainingRecord, len(s.trainings))
+	for k, v := range s.trainings {
+		c.trainings[k] = v
+	}
+	c.assignments = make(map[assignment]struct{}, len(s.assignments))
+	for k := range s.assignments {
+		c.assignments[k] = struct{}{}
+	}
+
+	return c
+}
+
+// Snapshot returns a copy of the current state.
+func (s *Storage) Snapshot(ctx context.Context) *Snapshot {
+	defer s.lock(ctx)()
+
+	snapshot := s.data.clone()
+	return &snapshot
+}
+
+// Restore replaces the current state with snapshot.
+func (s *Storage) Restore(ctx context.Context, snapshot *Snapshot) {
+	defer s.lock(ctx)()
+
+	s.data = snapshot.clone()
+}
+
+func (s *Storage) inTransaction(ctx context.Context) bool {
+	tr := trmcontext.DefaultManager.Default(ctx)
+	return tr != nil && tr.IsActive() && tr.Transaction() == s
+}
+
+// lock guards a single operation and returns the function releasing it.
+func (s *Storage) lock(ctx context.Context) func() {
+	if s.inTransaction(ctx) {
+		s.mu.Lock()
+		return s.mu.Unlock
+	}
+
+	s.txMu.Lock()
+	s.mu.Lock()
+	return func() {
+		s.mu.Unlock()
+		s.txMu.Unlock()
+	}
+}
@@ -0,0 +1,159 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+)
+
+type TrainingInMemoryRepository struct {
+	storage *Storage
+}
+
+func NewTrainingInMemoryRepository(storage *Storage) repositories.TrainingRepository {
+	return &TrainingInMemoryRepository{storage: storage}
+}
+
+func (t *TrainingInMemoryRepository) Create(ctx context.Context, training *models.Training) error {
+	defer t.storage.lock(ctx)()
+
+	if _, ok := t.storage.data.coaches[training.CoachID]; !ok {
+		return repositoriesErrors.EntityDoesNotExists
+	}
+	if _, ok := t.storage.data.halls[training.HallID]; !ok {
+		return repositoriesErrors.EntityDoesNotExists
+	}
+
+	t.storage.data.trainingSeq++
+	training.ID = t.storage.data.trainingSeq
+	t.storage.data.trainings[training.ID] = trainingRecord{training: *training, availablePlacesNum: training.PlacesNum}
+
+	return nil
+}
+
+func (t *TrainingInMemoryRepository) Delete(ctx context.Context, id uint64) error {
+	defer t.storage.lock(ctx)()
+
+	if _, ok := t.storage.data.trainings[id]; !ok {
+		return repositoriesErrors.EntityDoesNotExists
+	}
+	delete(t.storage.data.trainings, id)
+
+	for a := range t.storage.data.assignments {
+		if a.trainingID == id {
+			delete(t.storage.data.assignments, a)
+		}
+	}
+
+	return nil
+}
+
+func (t *TrainingInMemoryRepository) GetByID(ctx context.Context, id uint64) (*models.Training, error) {
+	defer t.storage.lock(ctx)()
+
+	record, ok := t.storage.data.trainings[id]
+	if !ok {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	}
+
+	return &record.training, nil
+}
+
+func (t *TrainingInMemoryRepository) GetAllByClient(ctx context.Context, id uint64) ([]models.Training, error) {
+	defer t.storage.lock(ctx)()
+
+	return t.filter(func(training models.Training) bool {
+		_, ok := t.storage.data.assignments[assignment{clientID: id, trainingID: training.ID}]
+		return ok
+	}), nil
+}
+
+// GetAllByCoachOnDate returns the trainings starting on the calendar day of
+// date in the location of date.
+func (t *TrainingInMemoryRepository) GetAllByCoachOnDate(ctx context.Context, id uint64, date time.Time) ([]models.Training, error) {
+	defer t.storage.lock(ctx)()
+
+	year, month, day := date.Date()
+	return t.filter(func(training models.Training) bool {
+		y, m, d := training.DateTime.In(date.Location()).Date()
+		return training.CoachID == id && y == year && m == month && d == day
+	}), nil
+}
+
+func (t *TrainingInMemoryRepository) GetAllByDateTime(ctx context.Context, dateTime time.Time) ([]models.Training, error) {
+	defer t.storage.lock(ctx)()
+
+	return t.filter(func(training models.Training) bool {
+		return training.DateTime.Equal(dateTime)
+	}), nil
+}
+
+func (t *TrainingInMemoryRepository) GetAllBetweenDateTime(ctx context.Context, start time.Time, end time.Time) ([]models.Training, error) {
+	defer t.storage.lock(ctx)()
+
+	return t.filter(func(training models.Training) bool {
+		return !training.DateTime.Before(start) && !training.DateTime.After(end)
+	}), nil
+}
+
+func (t *TrainingInMemoryRepository) ReduceAvailablePlacesNum(ctx context.Context, id uint64) error {
+	defer t.storage.lock(ctx)()
+
+	record, ok := t.storage.data.trainings[id]
+	if !ok {
+		return repositoriesErrors.EntityDoesNotExists
+	}
+	if record.availablePlacesNum == 0 {
+		return extRepositoriesErrors.NoAvailablePlacesNum
+	}
+
+	record.availablePlacesNum--
+	t.storage.data.trainings[id] = record
+
+	return nil
+}
+
+func (t *TrainingInMemoryRepository) IncreaseAvailablePlacesNum(ctx context.Context, id uint64) error {
+	defer t.storage.lock(ctx)()
+
+	record, ok := t.storage.data.trainings[id]
+	if !ok {
+		return repositoriesErrors.EntityDoesNotExists
+	}
+
+	record.availablePlacesNum++
+	t.storage.data.trainings[id] = record
+
+	return nil
+}
+
+// AvailablePlacesNum returns the number of free places left on the training.
+func (t *TrainingInMemoryRepository) AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error) {
+	defer t.storage.lock(ctx)()
+
+	record, ok := t.storage.data.trainings[id]
+	if !ok {
+		return 0, repositoriesErrors.EntityDoesNotExists
+	}
+
+	return record.availablePlacesNum, nil
+}
+
+// filter must be called with the storage locked.
+func (t *TrainingInMemoryRepository) filter(match func(training models.Training) bool) []models.Training {
+	trainingModels := []models.Training{}
+	for _, record := range t.storage.data.trainings {
+		if match(record.training) {
+			trainingModels = append(trainingModels, record.training)
+		}
+	}
+	sort.Slice(trainingModels, func(i, j int) bool { return trainingModels[i].ID < trainingModels[j].ID })
+
+	return trainingModels
+}
@@ -0,0 +1,143 @@
+package inmemory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type TrainingSuite struct {
+	suite.Suite
+	storage    *Storage
+	repository *TrainingInMemoryRepository
+	ctx        context.Context
+}
+
+func (s *TrainingSuite) BeforeEach(t provider.T) {
+	s.ctx = context.Background()
+	s.storage = NewStorage()
+	s.repository = NewTrainingInMemoryRepository(s.storage).(*TrainingInMemoryRepository)
+
+	if err := NewCoachInMemoryRepository(s.storage).Create(s.ctx, postgreSQLObjectMother.CreateTestCoach()); err != nil {
+		t.Fatalf("error creating coach: %v", err)
+	}
+	if err := NewHallInMemoryRepository(s.storage).Create(s.ctx, postgreSQLObjectMother.CreateTestHall()); err != nil {
+		t.Fatalf("error creating hall: %v", err)
+	}
+}
+
+func (s *TrainingSuite) TestTrainingInMemoryCreateSuccess(t provider.T) {
+	t.Title("TrainingInMemoryCreate: Success")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		err := s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestTraining())
+		sCtx.Assert().NoError(err)
+
+		training, err := s.repository.GetByID(s.ctx, 1)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(postgreSQLObjectMother.CreateTestTraining(), training)
+
+		places, err := s.repository.AvailablePlacesNum(s.ctx, 1)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(training.PlacesNum, places)
+	})
+}
+
+func (s *TrainingSuite) TestTrainingInMemoryCreateUnknownCoach(t provider.T) {
+	t.Title("TrainingInMemoryCreate: Unknown coach")
+	t.Tags("Training")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		training := postgreSQLObjectMother.CreateTestTraining()
+		training.CoachID = 2
+
+		err := s.repository.Create(s.ctx, training)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *TrainingSuite) TestTrainingInMemoryDeleteCascade(t provider.T) {
+	t.Title("TrainingInMemoryDelete: Assignments are removed")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		clients := NewClientInMemoryRepository(s.storage)
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestTraining()))
+		sCtx.Assert().NoError(clients.Create(s.ctx, postgreSQLObjectMother.CreateTestClient()))
+		sCtx.Assert().NoError(clients.CreateAssignment(s.ctx, 1, 1))
+
+		sCtx.Assert().NoError(s.repository.Delete(s.ctx, 1))
+
+		trainings, err := s.repository.GetAllByClient(s.ctx, 1)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(trainings)
+		sCtx.Assert().ErrorIs(s.repository.Delete(s.ctx, 1), repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *TrainingSuite) TestTrainingInMemoryGetAllByCoachOnDate(t provider.T) {
+	t.Title("TrainingInMemoryGetAllByCoachOnDate: Success")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		training := postgreSQLObjectMother.CreateTestTraining()
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, training))
+		other := postgreSQLObjectMother.CreateTestTraining()
+		other.DateTime = other.DateTime.AddDate(0, 0, 1)
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, other))
+
+		trainings, err := s.repository.GetAllByCoachOnDate(s.ctx, 1, time.Date(2024, 7, 7, 0, 0, 0, 0, time.UTC))
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Training{*training}, trainings)
+	})
+}
+
+func (s *TrainingSuite) TestTrainingInMemoryGetAllBetweenDateTime(t provider.T) {
+	t.Title("TrainingInMemoryGetAllBetweenDateTime: Bounds are inclusive")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		training := postgreSQLObjectMother.CreateTestTraining()
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, training))
+
+		trainings, err := s.repository.GetAllBetweenDateTime(s.ctx, training.DateTime, training.DateTime.Add(time.Hour))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Training{*training}, trainings)
+
+		trainings, err = s.repository.GetAllByDateTime(s.ctx, training.DateTime.Add(time.Hour))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(trainings)
+	})
+}
+
+func (s *TrainingSuite) TestTrainingInMemoryAvailablePlacesNum(t provider.T) {
+	t.Title("TrainingInMemoryAvailablePlacesNum: Reduce and increase")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		training := postgreSQLObjectMother.CreateTestTraining()
+		training.PlacesNum = 1
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, training))
+
+		sCtx.Assert().NoError(s.repository.ReduceAvailablePlacesNum(s.ctx, 1))
+		sCtx.Assert().ErrorIs(s.repository.ReduceAvailablePlacesNum(s.ctx, 1), extRepositoriesErrors.NoAvailablePlacesNum)
+		sCtx.Assert().NoError(s.repository.IncreaseAvailablePlacesNum(s.ctx, 1))
+
+		places, err := s.repository.AvailablePlacesNum(s.ctx, 1)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(uint64(1), places)
+	})
+	t.WithNewStep("Unknown training", func(sCtx provider.StepCtx) {
+		sCtx.Assert().ErrorIs(s.repository.ReduceAvailablePlacesNum(s.ctx, 2), repositoriesErrors.EntityDoesNotExists)
+		sCtx.Assert().ErrorIs(s.repository.IncreaseAvailablePlacesNum(s.ctx, 2), repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func TestTrainingSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(TrainingSuite))
+}
@@ -0,0 +1,93 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/nkarakotova/lim-core/managers"
+	transactionManager "github.com/nkarakotova/lim-core/managers/implementation"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/drivers"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+)
+
+// Transaction takes a snapshot of the Storage on begin and restores it on
+// rollback. The top level transaction holds the Storage exclusively until it
+// is finished; nested ones (trm.PropagationNested) work as savepoints.
+//
+// trm.PropagationRequiresNew inside a running transaction of the same
+// Storage deadlocks, as there is only one writer at a time.
+type Transaction struct {
+	storage  *Storage
+	snapshot Snapshot
+	nested   bool
+	isClosed *drivers.IsClosed
+}
+
+// NewFactory returns a trm.TrFactory for manager.New and manager.Must.
+func NewFactory(storage *Storage) trm.TrFactory {
+	return func(ctx context.Context, _ trm.Settings) (context.Context, trm.Transaction, error) {
+		storage.txMu.Lock()
+		storage.mu.Lock()
+		defer storage.mu.Unlock()
+
+		return ctx, &Transaction{storage: storage, snapshot: storage.data.clone(), isClosed: drivers.NewIsClosed()}, nil
+	}
+}
+
+// NewTransactionManager returns the lim-core transaction manager working on
+// storage.
+func NewTransactionManager(storage *Storage) managers.TransactionManager {
+	return transactionManager.NewTransactionManagerImplementation(manager.Must(NewFactory(storage)))
+}
+
+func (t *Transaction) Transaction() interface{} {
+	return t.storage
+}
+
+func (t *Transaction) Begin(ctx context.Context, _ trm.Settings) (context.Context, trm.Transaction, error) {
+	t.storage.mu.Lock()
+	defer t.storage.mu.Unlock()
+
+	return ctx, &Transaction{storage: t.storage, snapshot: t.storage.data.clone(), nested: true, isClosed: drivers.NewIsClosed()}, nil
+}
+
+func (t *Transaction) Commit(_ context.Context) error {
+	if t.isClosed.IsClosed() {
+		return trm.ErrAlreadyClosed
+	}
+
+	t.close()
+
+	return nil
+}
+
+func (t *Transaction) Rollback(_ context.Context) error {
+	if t.isClosed.IsClosed() {
+		return trm.ErrAlreadyClosed
+	}
+
+	t.storage.mu.Lock()
+	t.storage.data = t.snapshot
+	t.storage.mu.Unlock()
+
+	t.close()
+
+	return nil
+}
+
+func (t *Transaction) IsActive() bool {
+	return t.isClosed.IsActive()
+}
+
+func (t *Transaction) Closed() <-chan struct{} {
+	return t.isClosed.Closed()
+}
+
+func (t *Transaction) close() {
+	t.isClosed.Close()
+	t.snapshot = Snapshot{}
+	if !t.nested {
+		t.storage.txMu.Unlock()
+	}
+}
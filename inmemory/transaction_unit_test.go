@@ -0,0 +1,151 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/nkarakotova/lim-core/managers"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/settings"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type TransactionSuite struct {
+	suite.Suite
+	storage    *Storage
+	repository repositories.CoachRepository
+	manager    managers.TransactionManager
+	ctx        context.Context
+}
+
+func (s *TransactionSuite) BeforeEach(t provider.T) {
+	s.storage = NewStorage()
+	s.repository = NewCoachInMemoryRepository(s.storage)
+	s.manager = NewTransactionManager(s.storage)
+	s.ctx = context.Background()
+}
+
+func (s *TransactionSuite) TestTransactionCommit(t provider.T) {
+	t.Title("Transaction: Commit keeps the changes")
+	t.Tags("Transaction")
+	t.WithNewStep("Commit", func(sCtx provider.StepCtx) {
+		err := s.manager.WithinTransaction(s.ctx, func(txCtx context.Context) error {
+			return s.repository.Create(txCtx, postgreSQLObjectMother.CreateTestCoach())
+		})
+		sCtx.Assert().NoError(err)
+
+		coaches, err := s.repository.GetAll(s.ctx)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Len(coaches, 1)
+	})
+}
+
+func (s *TransactionSuite) TestTransactionRollback(t provider.T) {
+	t.Title("Transaction: Rollback restores the snapshot")
+	t.Tags("Transaction")
+	t.WithNewStep("Rollback", func(sCtx provider.StepCtx) {
+		fnErr := errors.New("fail")
+		err := s.manager.WithinTransaction(s.ctx, func(txCtx context.Context) error {
+			if err := s.repository.Create(txCtx, postgreSQLObjectMother.CreateTestCoach()); err != nil {
+				return err
+			}
+			return fnErr
+		})
+		sCtx.Assert().ErrorIs(err, fnErr)
+
+		coaches, err := s.repository.GetAll(s.ctx)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(coaches)
+
+		coach := postgreSQLObjectMother.CreateTestCoach()
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, coach))
+		sCtx.Assert().Equal(uint64(1), coach.ID)
+	})
+}
+
+func (s *TransactionSuite) TestTransactionNestedRollback(t provider.T) {
+	t.Title("Transaction: Nested rollback works as a savepoint")
+	t.Tags("Transaction")
+	t.WithNewStep("Nested", func(sCtx provider.StepCtx) {
+		m := manager.Must(NewFactory(s.storage))
+		nested := settings.Must(settings.WithPropagation(trm.PropagationNested))
+
+		err := m.Do(s.ctx, func(txCtx context.Context) error {
+			if err := s.repository.Create(txCtx, &models.Coach{Name: "Outer"}); err != nil {
+				return err
+			}
+			_ = m.DoWithSettings(txCtx, nested, func(nestedCtx context.Context) error {
+				if err := s.repository.Create(nestedCtx, &models.Coach{Name: "Inner"}); err != nil {
+					return err
+				}
+				return errors.New("fail")
+			})
+			return nil
+		})
+		sCtx.Assert().NoError(err)
+
+		coaches, err := s.repository.GetAll(s.ctx)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Coach{{ID: 1, Name: "Outer"}}, coaches)
+	})
+}
+
+func (s *TransactionSuite) TestTransactionIsolation(t provider.T) {
+	t.Title("Transaction: Concurrent writers wait for the transaction")
+	t.Tags("Transaction")
+	t.WithNewStep("Isolation", func(sCtx provider.StepCtx) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var wg sync.WaitGroup
+		var seen []models.Coach
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = s.manager.WithinTransaction(s.ctx, func(txCtx context.Context) error {
+				_ = s.repository.Create(txCtx, &models.Coach{Name: "Tx"})
+				close(started)
+				<-release
+				return errors.New("fail")
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			<-started
+			seen, _ = s.repository.GetAll(s.ctx)
+		}()
+
+		<-started
+		close(release)
+		wg.Wait()
+
+		sCtx.Assert().Empty(seen)
+	})
+}
+
+func (s *TransactionSuite) TestStorageSnapshotRestore(t provider.T) {
+	t.Title("Storage: Snapshot and restore")
+	t.Tags("Transaction")
+	t.WithNewStep("Restore", func(sCtx provider.StepCtx) {
+		snapshot := s.storage.Snapshot(s.ctx)
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestCoach()))
+
+		s.storage.Restore(s.ctx, snapshot)
+
+		coaches, err := s.repository.GetAll(s.ctx)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(coaches)
+	})
+}
+
+func TestTransactionSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(TransactionSuite))
+}
@@ -0,0 +1,85 @@
+// Package listen waits for the notifications of a Postgres channel on a
+// connection of its own, reconnecting while the database is down. The cache
+// and events packages drive it with their own handlers.
+package listen
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jackc/pgconn"
+)
+
+const maxReconnectDelay = time.Minute
+
+// Conn is the connection the notifications are waited for on, a *pgx.Conn.
+type Conn interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	WaitForNotification(ctx context.Context) (*pgconn.Notification, error)
+	Close(ctx context.Context) error
+}
+
+type Listener struct {
+	Channel string
+	Connect func(ctx context.Context) (Conn, error)
+	// ReconnectDelay is the first delay before a reconnection, doubled up
+	// to a minute while the database is down.
+	ReconnectDelay time.Duration
+	// Listened is called once a connection listens on Channel,
+	// resubscribed after a connection that listened was lost: the
+	// notifications in between are lost.
+	Listened func(resubscribed bool)
+	// Notify is called with the payload of each notification.
+	Notify func(payload string)
+	Logger *log.Logger
+	// Lost is the message logged when a connection is lost.
+	Lost string
+}
+
+// Run listens until ctx is done.
+func (l *Listener) Run(ctx context.Context) {
+	delay := l.ReconnectDelay
+	subscribed := false
+	for {
+		listened, err := l.listen(ctx, subscribed)
+		if ctx.Err() != nil {
+			return
+		}
+		if listened {
+			subscribed = true
+			delay = l.ReconnectDelay
+		}
+		l.Logger.Warn(l.Lost, "err", err, "delay", delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxReconnectDelay)
+	}
+}
+
+// listen returns once the connection is lost, telling whether it listened.
+func (l *Listener) listen(ctx context.Context, resubscribed bool) (bool, error) {
+	conn, err := l.Connect(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "listen "+l.Channel)
+	if err != nil {
+		return false, err
+	}
+	l.Listened(resubscribed)
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+		l.Notify(notification.Payload)
+	}
+}
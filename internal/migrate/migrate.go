@@ -0,0 +1,92 @@
+// Package migrate applies the embedded migrations of a backend and records
+// them in schema_migrations.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"sort"
+)
+
+// pattern matches the migration files of a backend.
+const pattern = "migrations/*.sql"
+
+// Names returns the names of the migration files of fsys in the order they
+// are applied.
+func Names(fsys fs.FS) ([]string, error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// Run applies every migration of fsys that is not yet recorded in
+// schema_migrations. Each migration runs in its own transaction. now is the
+// current timestamp expression of the dialect, the default of applied_at;
+// setup, when not empty, starts every transaction.
+func Run(ctx context.Context, db *sql.DB, fsys fs.FS, now string, setup string) error {
+	query := `create table if not exists schema_migrations(version text primary key, applied_at timestamp not null default ` + now + `);`
+	_, err := db.ExecContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	names, err := Names(fsys)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		err = apply(ctx, db, fsys, name, setup)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func apply(ctx context.Context, db *sql.DB, fsys fs.FS, name string, setup string) error {
+	text, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if setup != "" {
+		_, err = tx.ExecContext(ctx, setup)
+		if err != nil {
+			return err
+		}
+	}
+
+	query := `insert into schema_migrations(version) values($1) on conflict do nothing;`
+	result, err := tx.ExecContext(ctx, query, name)
+	if err != nil {
+		return err
+	}
+
+	applied, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if applied == 0 {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, string(text))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,16 @@
+// Package txctx tells the decorators whether a call runs within a
+// transaction of the go-transaction-manager found in its context.
+package txctx
+
+import (
+	"context"
+
+	trmcontext "github.com/avito-tech/go-transaction-manager/trm/v2/context"
+)
+
+// InTransaction reports whether ctx carries an active transaction.
+func InTransaction(ctx context.Context) bool {
+	tr := trmcontext.DefaultManager.Default(ctx)
+
+	return tr != nil && tr.IsActive()
+}
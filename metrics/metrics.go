@@ -0,0 +1,163 @@
+// Package metrics records the latency, errors and returned rows of every
+// repository call and the stats of the connection pools.
+//
+// The constructors of this package decorate the client, coach, hall and
+// training repositories and report to a Metrics, so the backend is
+// pluggable; prometheusMetrics is the Prometheus one:
+//
+//	m, err := prometheusMetrics.New(prometheus.DefaultRegisterer)
+//	err = postgreSQL.InstrumentPostgresRepositoryFields(fields, m)
+//	clients := postgreSQL.CreateClientPostgreSQLRepository(fields)
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+)
+
+// ErrorClass groups the errors of the repositories for alerting.
+type ErrorClass string
+
+const (
+	// ClassNotFound is an entity that does not exist.
+	ClassNotFound ErrorClass = "not_found"
+	// ClassConstraint is a violated constraint of the database or of the
+	// studio: a duplicate, no free places, a closed hall.
+	ClassConstraint ErrorClass = "constraint"
+	// ClassTimeout is an exceeded deadline or statement timeout.
+	ClassTimeout ErrorClass = "timeout"
+	// ClassUnavailable is a call rejected by an open circuit breaker.
+	ClassUnavailable ErrorClass = "unavailable"
+	// ClassOther is any other error.
+	ClassOther ErrorClass = "other"
+)
+
+// NoRows is the row count of a call that returns no entities or failed.
+const NoRows = -1
+
+// Call is a finished repository method call.
+type Call struct {
+	Repository string
+	Method     string
+	Duration   time.Duration
+	// Rows is the number of returned entities or NoRows.
+	Rows int
+	// Class is empty when the call succeeded.
+	Class ErrorClass
+}
+
+// CacheStats are the counters of a cache of repository lookups since it
+// was created.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	// Shared are the misses that waited for the load of another one.
+	Shared        uint64
+	Evictions     uint64
+	Invalidations uint64
+	// Entries is the number of cached lookups.
+	Entries int
+}
+
+// Metrics is the backend the repositories report to.
+type Metrics interface {
+	// ObserveCall records a finished repository method call.
+	ObserveCall(call Call)
+	// RegisterPool reports the stats of a connection pool under name. stats
+	// is called whenever the metrics are collected.
+	RegisterPool(name string, stats func() sql.DBStats) error
+	// ObserveBreakerState records that the circuit breaker name changed to
+	// state: closed, open or half-open.
+	ObserveBreakerState(name string, state string)
+	// RegisterCache reports the stats of a cache under name. stats is
+	// called whenever the metrics are collected.
+	RegisterCache(name string, stats func() CacheStats) error
+}
+
+var constraintErrors = []error{
+	extRepositoriesErrors.EntityAlreadyExists,
+	extRepositoriesErrors.NoAvailablePlacesNum,
+	extRepositoriesErrors.CoachNotAvailable,
+	extRepositoriesErrors.PlacesNumMoreThenCapacity,
+	extRepositoriesErrors.HallClosed,
+}
+
+// sqliteConstraint is the primary result code of a violated constraint in
+// SQLite.
+const sqliteConstraint = 19
+
+// Classify returns the class of err, empty for nil.
+func Classify(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+
+	if errors.Is(err, repositoriesErrors.EntityDoesNotExists) || errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+		return ClassNotFound
+	}
+
+	if errors.Is(err, extRepositoriesErrors.DatabaseUnavailable) {
+		return ClassUnavailable
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ClassTimeout
+	}
+	var timeout interface{ Timeout() bool }
+	if errors.As(err, &timeout) && timeout.Timeout() {
+		return ClassTimeout
+	}
+
+	for _, constraint := range constraintErrors {
+		if errors.Is(err, constraint) {
+			return ClassConstraint
+		}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		// query_canceled is raised by statement_timeout, lock_not_available
+		// by lock_timeout.
+		case pgErr.Code == "57014" || pgErr.Code == "55P03":
+			return ClassTimeout
+		case len(pgErr.Code) == 5 && pgErr.Code[:2] == "23":
+			return ClassConstraint
+		}
+	}
+
+	// The SQLite driver reports extended result codes.
+	var sqliteErr interface{ Code() int }
+	if errors.As(err, &sqliteErr) && sqliteErr.Code()&0xff == sqliteConstraint {
+		return ClassConstraint
+	}
+
+	return ClassOther
+}
+
+type recorder struct {
+	metrics    Metrics
+	repository string
+}
+
+func (r recorder) observe(method string, start time.Time, rows int, err error) {
+	if err != nil {
+		rows = NoRows
+	}
+
+	r.metrics.ObserveCall(Call{
+		Repository: r.repository,
+		Method:     method,
+		Duration:   time.Since(start),
+		Rows:       rows,
+		Class:      Classify(err),
+	})
+}
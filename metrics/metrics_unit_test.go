@@ -0,0 +1,148 @@
+package metrics_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-repo/inmemory"
+	"github.com/nkarakotova/lim-repo/metrics"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+	"github.com/nkarakotova/lim-repo/sqlite"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+// recording keeps the calls it observes.
+type recording struct {
+	mutex sync.Mutex
+	calls []metrics.Call
+}
+
+func (r *recording) ObserveCall(call metrics.Call) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *recording) RegisterPool(string, func() sql.DBStats) error {
+	return nil
+}
+
+func (r *recording) ObserveBreakerState(string, string) {}
+
+func (r *recording) RegisterCache(string, func() metrics.CacheStats) error {
+	return nil
+}
+
+type MetricsSuite struct {
+	suite.Suite
+	recording *recording
+	storage   *inmemory.Storage
+	ctx       context.Context
+}
+
+func (s *MetricsSuite) BeforeEach(t provider.T) {
+	s.recording = &recording{}
+	s.storage = inmemory.NewStorage()
+	s.ctx = context.Background()
+}
+
+func (s *MetricsSuite) TestClassify(t provider.T) {
+	t.Title("Classify: Errors are grouped by class")
+	t.Tags("Metrics")
+	t.WithNewStep("Classes", func(sCtx provider.StepCtx) {
+		for _, tc := range []struct {
+			err   error
+			class metrics.ErrorClass
+		}{
+			{nil, ""},
+			{repositoriesErrors.EntityDoesNotExists, metrics.ClassNotFound},
+			{sql.ErrNoRows, metrics.ClassNotFound},
+			{fmt.Errorf("booking: %w", extRepositoriesErrors.NoAvailablePlacesNum), metrics.ClassConstraint},
+			{&pgconn.PgError{Code: "23505"}, metrics.ClassConstraint},
+			{&pgconn.PgError{Code: "57014"}, metrics.ClassTimeout},
+			{context.DeadlineExceeded, metrics.ClassTimeout},
+			{extRepositoriesErrors.DatabaseUnavailable, metrics.ClassUnavailable},
+			{&pgconn.PgError{Code: "42P01"}, metrics.ClassOther},
+			{errors.New("boom"), metrics.ClassOther},
+		} {
+			sCtx.Assert().Equal(tc.class, metrics.Classify(tc.err), fmt.Sprint(tc.err))
+		}
+	})
+}
+
+func (s *MetricsSuite) TestClassifySQLiteConstraint(t provider.T) {
+	t.Title("Classify: A violated SQLite constraint")
+	t.Tags("Metrics")
+	t.WithNewStep("Duplicate", func(sCtx provider.StepCtx) {
+		db, err := sqlite.SetupTestDatabase()
+		sCtx.Require().NoError(err)
+		defer db.Close()
+
+		_, err = db.ExecContext(s.ctx, `insert into halls(number) values(1), (1);`)
+		sCtx.Require().Error(err)
+
+		sCtx.Assert().Equal(metrics.ClassConstraint, metrics.Classify(err))
+	})
+}
+
+func (s *MetricsSuite) TestRepositoryCalls(t provider.T) {
+	t.Title("Repository: Calls are recorded with rows and error class")
+	t.Tags("Metrics")
+	t.WithNewStep("Calls", func(sCtx provider.StepCtx) {
+		repository := metrics.NewCoachRepository(inmemory.NewCoachInMemoryRepository(s.storage), s.recording)
+
+		sCtx.Require().NoError(repository.Create(s.ctx, postgreSQLObjectMother.NewCoach().Build()))
+		_, err := repository.GetAll(s.ctx)
+		sCtx.Require().NoError(err)
+		_, err = repository.GetByID(s.ctx, 42)
+		sCtx.Require().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		calls := s.recording.calls
+		sCtx.Require().Len(calls, 3)
+		sCtx.Assert().Equal("CoachRepository", calls[0].Repository)
+		sCtx.Assert().Equal("Create", calls[0].Method)
+		sCtx.Assert().Equal(metrics.NoRows, calls[0].Rows)
+		sCtx.Assert().Empty(calls[0].Class)
+		sCtx.Assert().Equal("GetAll", calls[1].Method)
+		sCtx.Assert().Equal(1, calls[1].Rows)
+		sCtx.Assert().Equal("GetByID", calls[2].Method)
+		sCtx.Assert().Equal(metrics.NoRows, calls[2].Rows)
+		sCtx.Assert().Equal(metrics.ClassNotFound, calls[2].Class)
+	})
+}
+
+func (s *MetricsSuite) TestBookingFailure(t provider.T) {
+	t.Title("Repository: A failed booking is recorded as a constraint error")
+	t.Tags("Metrics")
+	t.WithNewStep("No places", func(sCtx provider.StepCtx) {
+		scenario := postgreSQLObjectMother.NewScenario().Days(1).TrainingsPerDay(1).PlacesNum(1).Occupancy(1).Build()
+		err := scenario.Persist(s.ctx,
+			inmemory.NewClientInMemoryRepository(s.storage),
+			inmemory.NewCoachInMemoryRepository(s.storage),
+			inmemory.NewHallInMemoryRepository(s.storage),
+			inmemory.NewTrainingInMemoryRepository(s.storage))
+		sCtx.Require().NoError(err)
+
+		repository := metrics.NewTrainingRepository(inmemory.NewTrainingInMemoryRepository(s.storage), s.recording)
+		err = repository.ReduceAvailablePlacesNum(s.ctx, scenario.Trainings[0].ID)
+		sCtx.Require().ErrorIs(err, extRepositoriesErrors.NoAvailablePlacesNum)
+
+		sCtx.Require().Len(s.recording.calls, 1)
+		sCtx.Assert().Equal("ReduceAvailablePlacesNum", s.recording.calls[0].Method)
+		sCtx.Assert().Equal(metrics.ClassConstraint, s.recording.calls[0].Class)
+	})
+}
+
+func TestMetricsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(MetricsSuite))
+}
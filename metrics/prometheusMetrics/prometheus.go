@@ -0,0 +1,248 @@
+// Package prometheusMetrics is the Prometheus backend of the metrics
+// package. It exports
+//
+//	lim_repository_call_duration_seconds{repository, method}  histogram
+//	lim_repository_errors_total{repository, method, class}    counter
+//	lim_repository_rows_returned{repository, method}          histogram
+//	lim_db_connections_open{pool}                             gauge
+//	lim_db_connections_max_open{pool}                         gauge
+//	lim_db_connections_in_use{pool}                           gauge
+//	lim_db_connections_idle{pool}                             gauge
+//	lim_db_wait_count_total{pool}                             counter
+//	lim_db_wait_duration_seconds_total{pool}                  counter
+//	lim_breaker_state{breaker, state}                         gauge
+//	lim_breaker_transitions_total{breaker, state}             counter
+//	lim_cache_hits_total{cache}                               counter
+//	lim_cache_misses_total{cache}                             counter
+//	lim_cache_shared_total{cache}                             counter
+//	lim_cache_evictions_total{cache}                          counter
+//	lim_cache_invalidations_total{cache}                      counter
+//	lim_cache_entries{cache}                                  gauge
+//
+// The pool and cache stats are read when Prometheus scrapes them.
+package prometheusMetrics
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/nkarakotova/lim-repo/metrics"
+)
+
+const namespace = "lim"
+
+type PrometheusMetrics struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	rows     *prometheus.HistogramVec
+	pools    *poolCollector
+	caches   *cacheCollector
+	breakers *prometheus.GaugeVec
+	changes  *prometheus.CounterVec
+}
+
+var breakerStates = []string{"closed", "open", "half-open"}
+
+// New registers the metrics with registerer.
+func New(registerer prometheus.Registerer) (*PrometheusMetrics, error) {
+	m := &PrometheusMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "call_duration_seconds",
+			Help:      "Latency of the repository method calls.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"repository", "method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "errors_total",
+			Help:      "Failed repository method calls by error class.",
+		}, []string{"repository", "method", "class"}),
+		rows: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "rows_returned",
+			Help:      "Entities returned by the repository method calls.",
+			Buckets:   []float64{0, 1, 5, 10, 25, 50, 100, 250, 500, 1000},
+		}, []string{"repository", "method"}),
+		pools:  newPoolCollector(),
+		caches: newCacheCollector(),
+		breakers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "breaker",
+			Name:      "state",
+			Help:      "Current state of the circuit breaker, 1 for the state it is in.",
+		}, []string{"breaker", "state"}),
+		changes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "breaker",
+			Name:      "transitions_total",
+			Help:      "Changes of the circuit breaker to the state.",
+		}, []string{"breaker", "state"}),
+	}
+
+	for _, collector := range []prometheus.Collector{m.duration, m.errors, m.rows, m.pools, m.caches, m.breakers, m.changes} {
+		err := registerer.Register(collector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func (m *PrometheusMetrics) ObserveCall(call metrics.Call) {
+	m.duration.WithLabelValues(call.Repository, call.Method).Observe(call.Duration.Seconds())
+	if call.Class != "" {
+		m.errors.WithLabelValues(call.Repository, call.Method, string(call.Class)).Inc()
+	}
+	if call.Rows != metrics.NoRows {
+		m.rows.WithLabelValues(call.Repository, call.Method).Observe(float64(call.Rows))
+	}
+}
+
+func (m *PrometheusMetrics) RegisterPool(name string, stats func() sql.DBStats) error {
+	return m.pools.register(name, stats)
+}
+
+func (m *PrometheusMetrics) RegisterCache(name string, stats func() metrics.CacheStats) error {
+	return m.caches.register(name, stats)
+}
+
+func (m *PrometheusMetrics) ObserveBreakerState(name string, state string) {
+	for _, s := range breakerStates {
+		value := 0.0
+		if s == state {
+			value = 1
+		}
+		m.breakers.WithLabelValues(name, s).Set(value)
+	}
+	m.changes.WithLabelValues(name, state).Inc()
+}
+
+var (
+	openDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "connections_open"),
+		"Open connections of the pool.", []string{"pool"}, nil)
+	maxOpenDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "connections_max_open"),
+		"Maximum open connections of the pool, 0 is unlimited.", []string{"pool"}, nil)
+	inUseDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "connections_in_use"),
+		"Connections of the pool in use.", []string{"pool"}, nil)
+	idleDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "connections_idle"),
+		"Idle connections of the pool.", []string{"pool"}, nil)
+	waitCountDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "wait_count_total"),
+		"Connections waited for.", []string{"pool"}, nil)
+	waitDurationDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "wait_duration_seconds_total"),
+		"Time spent waiting for a connection.", []string{"pool"}, nil)
+)
+
+type poolCollector struct {
+	mutex sync.Mutex
+	pools map[string]func() sql.DBStats
+}
+
+func newPoolCollector() *poolCollector {
+	return &poolCollector{pools: map[string]func() sql.DBStats{}}
+}
+
+func (c *poolCollector) register(name string, stats func() sql.DBStats) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.pools[name]; ok {
+		return fmt.Errorf("pool %q is already registered", name)
+	}
+	c.pools[name] = stats
+
+	return nil
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{openDesc, maxOpenDesc, inUseDesc, idleDesc, waitCountDesc, waitDurationDesc} {
+		ch <- desc
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	// The stats are read without the lock, a pool may take a while.
+	c.mutex.Lock()
+	pools := make(map[string]func() sql.DBStats, len(c.pools))
+	for name, stats := range c.pools {
+		pools[name] = stats
+	}
+	c.mutex.Unlock()
+
+	for name, read := range pools {
+		stats := read()
+		ch <- prometheus.MustNewConstMetric(openDesc, prometheus.GaugeValue, float64(stats.OpenConnections), name)
+		ch <- prometheus.MustNewConstMetric(maxOpenDesc, prometheus.GaugeValue, float64(stats.MaxOpenConnections), name)
+		ch <- prometheus.MustNewConstMetric(inUseDesc, prometheus.GaugeValue, float64(stats.InUse), name)
+		ch <- prometheus.MustNewConstMetric(idleDesc, prometheus.GaugeValue, float64(stats.Idle), name)
+		ch <- prometheus.MustNewConstMetric(waitCountDesc, prometheus.CounterValue, float64(stats.WaitCount), name)
+		ch <- prometheus.MustNewConstMetric(waitDurationDesc, prometheus.CounterValue, stats.WaitDuration.Seconds(), name)
+	}
+}
+
+var (
+	hitsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "hits_total"),
+		"Lookups answered by the cache.", []string{"cache"}, nil)
+	missesDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "misses_total"),
+		"Lookups loaded from the repository.", []string{"cache"}, nil)
+	sharedDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "shared_total"),
+		"Misses that waited for the load of a concurrent one.", []string{"cache"}, nil)
+	evictionsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "evictions_total"),
+		"Lookups evicted or expired.", []string{"cache"}, nil)
+	invalidationsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "invalidations_total"),
+		"Invalidations of the cache by a write.", []string{"cache"}, nil)
+	entriesDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "entries"),
+		"Cached lookups.", []string{"cache"}, nil)
+)
+
+type cacheCollector struct {
+	mutex  sync.Mutex
+	caches map[string]func() metrics.CacheStats
+}
+
+func newCacheCollector() *cacheCollector {
+	return &cacheCollector{caches: map[string]func() metrics.CacheStats{}}
+}
+
+func (c *cacheCollector) register(name string, stats func() metrics.CacheStats) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.caches[name]; ok {
+		return fmt.Errorf("cache %q is already registered", name)
+	}
+	c.caches[name] = stats
+
+	return nil
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{hitsDesc, missesDesc, sharedDesc, evictionsDesc, invalidationsDesc, entriesDesc} {
+		ch <- desc
+	}
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.Lock()
+	caches := make(map[string]func() metrics.CacheStats, len(c.caches))
+	for name, stats := range c.caches {
+		caches[name] = stats
+	}
+	c.mutex.Unlock()
+
+	for name, read := range caches {
+		stats := read()
+		ch <- prometheus.MustNewConstMetric(hitsDesc, prometheus.CounterValue, float64(stats.Hits), name)
+		ch <- prometheus.MustNewConstMetric(missesDesc, prometheus.CounterValue, float64(stats.Misses), name)
+		ch <- prometheus.MustNewConstMetric(sharedDesc, prometheus.CounterValue, float64(stats.Shared), name)
+		ch <- prometheus.MustNewConstMetric(evictionsDesc, prometheus.CounterValue, float64(stats.Evictions), name)
+		ch <- prometheus.MustNewConstMetric(invalidationsDesc, prometheus.CounterValue, float64(stats.Invalidations), name)
+		ch <- prometheus.MustNewConstMetric(entriesDesc, prometheus.GaugeValue, float64(stats.Entries), name)
+	}
+}
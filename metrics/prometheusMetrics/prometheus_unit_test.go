@@ -0,0 +1,158 @@
+package prometheusMetrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/nkarakotova/lim-repo/metrics"
+	"github.com/nkarakotova/lim-repo/sqlite"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type PrometheusSuite struct {
+	suite.Suite
+	registry *prometheus.Registry
+	metrics  *PrometheusMetrics
+}
+
+func (s *PrometheusSuite) BeforeEach(t provider.T) {
+	s.registry = prometheus.NewRegistry()
+
+	var err error
+	s.metrics, err = New(s.registry)
+	if err != nil {
+		t.Fatalf("error registering metrics: %v", err)
+	}
+}
+
+// gather returns the metric of family name whose labels include labels.
+func (s *PrometheusSuite) gather(t provider.StepCtx, name string, labels map[string]string) *dto.Metric {
+	families, err := s.registry.Gather()
+	t.Require().NoError(err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metric:
+		for _, metric := range family.GetMetric() {
+			matched := 0
+			for _, label := range metric.GetLabel() {
+				value, ok := labels[label.GetName()]
+				if ok && value != label.GetValue() {
+					continue metric
+				}
+				if ok {
+					matched++
+				}
+			}
+			if matched == len(labels) {
+				return metric
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *PrometheusSuite) TestObserveCall(t provider.T) {
+	t.Title("Prometheus: Latency, errors and rows of the calls")
+	t.Tags("Metrics")
+	t.WithNewStep("Calls", func(sCtx provider.StepCtx) {
+		s.metrics.ObserveCall(metrics.Call{Repository: "TrainingRepository", Method: "GetAllByDateTime", Duration: 3 * time.Millisecond, Rows: 12})
+		s.metrics.ObserveCall(metrics.Call{Repository: "TrainingRepository", Method: "GetAllByDateTime", Duration: time.Millisecond, Rows: 4})
+		s.metrics.ObserveCall(metrics.Call{Repository: "ClientRepository", Method: "CreateAssignment", Duration: time.Millisecond, Rows: metrics.NoRows, Class: metrics.ClassConstraint})
+
+		labels := map[string]string{"repository": "TrainingRepository", "method": "GetAllByDateTime"}
+		duration := s.gather(sCtx, "lim_repository_call_duration_seconds", labels)
+		sCtx.Require().NotNil(duration)
+		sCtx.Assert().Equal(uint64(2), duration.GetHistogram().GetSampleCount())
+		sCtx.Assert().InDelta(0.004, duration.GetHistogram().GetSampleSum(), 1e-9)
+
+		rows := s.gather(sCtx, "lim_repository_rows_returned", labels)
+		sCtx.Require().NotNil(rows)
+		sCtx.Assert().Equal(16.0, rows.GetHistogram().GetSampleSum())
+
+		failed := s.gather(sCtx, "lim_repository_errors_total",
+			map[string]string{"repository": "ClientRepository", "method": "CreateAssignment", "class": "constraint"})
+		sCtx.Require().NotNil(failed)
+		sCtx.Assert().Equal(1.0, failed.GetCounter().GetValue())
+		sCtx.Assert().Nil(s.gather(sCtx, "lim_repository_rows_returned", map[string]string{"method": "CreateAssignment"}))
+	})
+}
+
+func (s *PrometheusSuite) TestPoolStats(t provider.T) {
+	t.Title("Prometheus: Pool stats are read on scrape")
+	t.Tags("Metrics")
+	t.WithNewStep("Pool", func(sCtx provider.StepCtx) {
+		db, err := sqlite.SetupTestDatabase()
+		sCtx.Require().NoError(err)
+		defer db.Close()
+
+		sCtx.Require().NoError(s.metrics.RegisterPool("sqlite", db.Stats))
+		sCtx.Assert().Error(s.metrics.RegisterPool("sqlite", db.Stats))
+
+		conn, err := db.Conn(context.Background())
+		sCtx.Require().NoError(err)
+
+		labels := map[string]string{"pool": "sqlite"}
+		sCtx.Assert().Equal(1.0, s.gather(sCtx, "lim_db_connections_max_open", labels).GetGauge().GetValue())
+		sCtx.Assert().Equal(1.0, s.gather(sCtx, "lim_db_connections_in_use", labels).GetGauge().GetValue())
+		sCtx.Assert().Equal(0.0, s.gather(sCtx, "lim_db_connections_idle", labels).GetGauge().GetValue())
+
+		sCtx.Require().NoError(conn.Close())
+		sCtx.Assert().Equal(0.0, s.gather(sCtx, "lim_db_connections_in_use", labels).GetGauge().GetValue())
+		sCtx.Assert().Equal(1.0, s.gather(sCtx, "lim_db_connections_idle", labels).GetGauge().GetValue())
+		sCtx.Assert().NotNil(s.gather(sCtx, "lim_db_wait_count_total", labels))
+		sCtx.Assert().NotNil(s.gather(sCtx, "lim_db_wait_duration_seconds_total", labels))
+	})
+}
+
+func (s *PrometheusSuite) TestCacheStats(t provider.T) {
+	t.Title("Prometheus: Cache stats are read on scrape")
+	t.Tags("Metrics")
+	t.WithNewStep("Cache", func(sCtx provider.StepCtx) {
+		stats := metrics.CacheStats{Hits: 3, Misses: 2, Shared: 1, Entries: 2}
+		read := func() metrics.CacheStats { return stats }
+
+		sCtx.Require().NoError(s.metrics.RegisterCache("coaches", read))
+		sCtx.Assert().Error(s.metrics.RegisterCache("coaches", read))
+
+		labels := map[string]string{"cache": "coaches"}
+		sCtx.Assert().Equal(3.0, s.gather(sCtx, "lim_cache_hits_total", labels).GetCounter().GetValue())
+		sCtx.Assert().Equal(2.0, s.gather(sCtx, "lim_cache_entries", labels).GetGauge().GetValue())
+
+		stats.Invalidations, stats.Entries = 1, 0
+		sCtx.Assert().Equal(1.0, s.gather(sCtx, "lim_cache_invalidations_total", labels).GetCounter().GetValue())
+		sCtx.Assert().Equal(0.0, s.gather(sCtx, "lim_cache_entries", labels).GetGauge().GetValue())
+	})
+}
+
+func (s *PrometheusSuite) TestBreakerState(t provider.T) {
+	t.Title("Prometheus: The state of a breaker is a gauge per state")
+	t.Tags("Metrics")
+	t.WithNewStep("Breaker", func(sCtx provider.StepCtx) {
+		s.metrics.ObserveBreakerState("postgres", "closed")
+		s.metrics.ObserveBreakerState("postgres", "open")
+
+		state := func(state string) float64 {
+			return s.gather(sCtx, "lim_breaker_state", map[string]string{"breaker": "postgres", "state": state}).GetGauge().GetValue()
+		}
+		sCtx.Assert().Equal(0.0, state("closed"))
+		sCtx.Assert().Equal(1.0, state("open"))
+		sCtx.Assert().Equal(0.0, state("half-open"))
+
+		transitions := s.gather(sCtx, "lim_breaker_transitions_total", map[string]string{"breaker": "postgres", "state": "open"})
+		sCtx.Assert().Equal(1.0, transitions.GetCounter().GetValue())
+	})
+}
+
+func TestPrometheusSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(PrometheusSuite))
+}
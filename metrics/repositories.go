@@ -0,0 +1,325 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+)
+
+func found[T any](entity *T) int {
+	if entity == nil {
+		return 0
+	}
+
+	return 1
+}
+
+type ClientRepository struct {
+	next     repositories.ClientRepository
+	recorder recorder
+}
+
+// NewClientRepository records every call of next in m.
+func NewClientRepository(next repositories.ClientRepository, m Metrics) repositories.ClientRepository {
+	return &ClientRepository{next: next, recorder: recorder{metrics: m, repository: "ClientRepository"}}
+}
+
+func (c *ClientRepository) Create(ctx context.Context, client *models.Client) error {
+	start := time.Now()
+	err := c.next.Create(ctx, client)
+	c.recorder.observe("Create", start, NoRows, err)
+
+	return err
+}
+
+func (c *ClientRepository) GetByID(ctx context.Context, id uint64) (*models.Client, error) {
+	start := time.Now()
+	client, err := c.next.GetByID(ctx, id)
+	c.recorder.observe("GetByID", start, found(client), err)
+
+	return client, err
+}
+
+func (c *ClientRepository) GetByTelephone(ctx context.Context, telephone string) (*models.Client, error) {
+	start := time.Now()
+	client, err := c.next.GetByTelephone(ctx, telephone)
+	c.recorder.observe("GetByTelephone", start, found(client), err)
+
+	return client, err
+}
+
+func (c *ClientRepository) GetByTraining(ctx context.Context, id uint64) ([]models.Client, error) {
+	start := time.Now()
+	clients, err := c.next.GetByTraining(ctx, id)
+	c.recorder.observe("GetByTraining", start, len(clients), err)
+
+	return clients, err
+}
+
+func (c *ClientRepository) CreateAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	start := time.Now()
+	err := c.next.CreateAssignment(ctx, clientID, trainingID)
+	c.recorder.observe("CreateAssignment", start, NoRows, err)
+
+	return err
+}
+
+func (c *ClientRepository) DeleteAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	start := time.Now()
+	err := c.next.DeleteAssignment(ctx, clientID, trainingID)
+	c.recorder.observe("DeleteAssignment", start, NoRows, err)
+
+	return err
+}
+
+type CoachRepository struct {
+	next     repositories.CoachRepository
+	recorder recorder
+}
+
+// NewCoachRepository records every call of next in m.
+func NewCoachRepository(next repositories.CoachRepository, m Metrics) repositories.CoachRepository {
+	return &CoachRepository{next: next, recorder: recorder{metrics: m, repository: "CoachRepository"}}
+}
+
+func (c *CoachRepository) Create(ctx context.Context, coach *models.Coach) error {
+	start := time.Now()
+	err := c.next.Create(ctx, coach)
+	c.recorder.observe("Create", start, NoRows, err)
+
+	return err
+}
+
+func (c *CoachRepository) GetByID(ctx context.Context, id uint64) (*models.Coach, error) {
+	start := time.Now()
+	coach, err := c.next.GetByID(ctx, id)
+	c.recorder.observe("GetByID", start, found(coach), err)
+
+	return coach, err
+}
+
+func (c *CoachRepository) GetByName(ctx context.Context, name string) (*models.Coach, error) {
+	start := time.Now()
+	coach, err := c.next.GetByName(ctx, name)
+	c.recorder.observe("GetByName", start, found(coach), err)
+
+	return coach, err
+}
+
+func (c *CoachRepository) GetAll(ctx context.Context) ([]models.Coach, error) {
+	start := time.Now()
+	coaches, err := c.next.GetAll(ctx)
+	c.recorder.observe("GetAll", start, len(coaches), err)
+
+	return coaches, err
+}
+
+type HallRepository struct {
+	next     repositories.HallRepository
+	recorder recorder
+}
+
+// NewHallRepository records every call of next in m.
+func NewHallRepository(next repositories.HallRepository, m Metrics) repositories.HallRepository {
+	return newHallRepository(next, m)
+}
+
+func newHallRepository(next repositories.HallRepository, m Metrics) *HallRepository {
+	return &HallRepository{next: next, recorder: recorder{metrics: m, repository: "HallRepository"}}
+}
+
+func (h *HallRepository) Create(ctx context.Context, hall *models.Hall) error {
+	start := time.Now()
+	err := h.next.Create(ctx, hall)
+	h.recorder.observe("Create", start, NoRows, err)
+
+	return err
+}
+
+func (h *HallRepository) GetByID(ctx context.Context, id uint64) (*models.Hall, error) {
+	start := time.Now()
+	hall, err := h.next.GetByID(ctx, id)
+	h.recorder.observe("GetByID", start, found(hall), err)
+
+	return hall, err
+}
+
+func (h *HallRepository) GetByNumber(ctx context.Context, number uint64) (*models.Hall, error) {
+	start := time.Now()
+	hall, err := h.next.GetByNumber(ctx, number)
+	h.recorder.observe("GetByNumber", start, found(hall), err)
+
+	return hall, err
+}
+
+func (h *HallRepository) GetAll(ctx context.Context) (map[uint64]models.Hall, error) {
+	start := time.Now()
+	halls, err := h.next.GetAll(ctx)
+	h.recorder.observe("GetAll", start, len(halls), err)
+
+	return halls, err
+}
+
+// ExtHallRepository also records the methods of the extended hall repository.
+type ExtHallRepository struct {
+	*HallRepository
+	next extRepositories.HallRepository
+}
+
+// NewExtHallRepository is NewHallRepository for the extended hall
+// repository.
+func NewExtHallRepository(next extRepositories.HallRepository, m Metrics) extRepositories.HallRepository {
+	return &ExtHallRepository{HallRepository: newHallRepository(next, m), next: next}
+}
+
+func (h *ExtHallRepository) GetDetails(ctx context.Context, id uint64) (*extModels.HallDetails, error) {
+	start := time.Now()
+	details, err := h.next.GetDetails(ctx, id)
+	h.recorder.observe("GetDetails", start, found(details), err)
+
+	return details, err
+}
+
+func (h *ExtHallRepository) UpdateDetails(ctx context.Context, details *extModels.HallDetails) error {
+	start := time.Now()
+	err := h.next.UpdateDetails(ctx, details)
+	h.recorder.observe("UpdateDetails", start, NoRows, err)
+
+	return err
+}
+
+func (h *ExtHallRepository) SetEquipment(ctx context.Context, id uint64, equipment extModels.Equipment, count uint64) error {
+	start := time.Now()
+	err := h.next.SetEquipment(ctx, id, equipment, count)
+	h.recorder.observe("SetEquipment", start, NoRows, err)
+
+	return err
+}
+
+func (h *ExtHallRepository) GetAllByEquipment(ctx context.Context, required map[extModels.Equipment]uint64) (map[uint64]models.Hall, error) {
+	start := time.Now()
+	halls, err := h.next.GetAllByEquipment(ctx, required)
+	h.recorder.observe("GetAllByEquipment", start, len(halls), err)
+
+	return halls, err
+}
+
+type TrainingRepository struct {
+	next     repositories.TrainingRepository
+	recorder recorder
+}
+
+// NewTrainingRepository records every call of next in m.
+func NewTrainingRepository(next repositories.TrainingRepository, m Metrics) repositories.TrainingRepository {
+	return newTrainingRepository(next, m)
+}
+
+func newTrainingRepository(next repositories.TrainingRepository, m Metrics) *TrainingRepository {
+	return &TrainingRepository{next: next, recorder: recorder{metrics: m, repository: "TrainingRepository"}}
+}
+
+func (t *TrainingRepository) Create(ctx context.Context, training *models.Training) error {
+	start := time.Now()
+	err := t.next.Create(ctx, training)
+	t.recorder.observe("Create", start, NoRows, err)
+
+	return err
+}
+
+func (t *TrainingRepository) Delete(ctx context.Context, id uint64) error {
+	start := time.Now()
+	err := t.next.Delete(ctx, id)
+	t.recorder.observe("Delete", start, NoRows, err)
+
+	return err
+}
+
+func (t *TrainingRepository) GetByID(ctx context.Context, id uint64) (*models.Training, error) {
+	start := time.Now()
+	training, err := t.next.GetByID(ctx, id)
+	t.recorder.observe("GetByID", start, found(training), err)
+
+	return training, err
+}
+
+func (t *TrainingRepository) GetAllByClient(ctx context.Context, id uint64) ([]models.Training, error) {
+	start := time.Now()
+	trainings, err := t.next.GetAllByClient(ctx, id)
+	t.recorder.observe("GetAllByClient", start, len(trainings), err)
+
+	return trainings, err
+}
+
+func (t *TrainingRepository) GetAllByCoachOnDate(ctx context.Context, id uint64, date time.Time) ([]models.Training, error) {
+	start := time.Now()
+	trainings, err := t.next.GetAllByCoachOnDate(ctx, id, date)
+	t.recorder.observe("GetAllByCoachOnDate", start, len(trainings), err)
+
+	return trainings, err
+}
+
+func (t *TrainingRepository) GetAllByDateTime(ctx context.Context, dateTime time.Time) ([]models.Training, error) {
+	start := time.Now()
+	trainings, err := t.next.GetAllByDateTime(ctx, dateTime)
+	t.recorder.observe("GetAllByDateTime", start, len(trainings), err)
+
+	return trainings, err
+}
+
+func (t *TrainingRepository) GetAllBetweenDateTime(ctx context.Context, start time.Time, end time.Time) ([]models.Training, error) {
+	called := time.Now()
+	trainings, err := t.next.GetAllBetweenDateTime(ctx, start, end)
+	t.recorder.observe("GetAllBetweenDateTime", called, len(trainings), err)
+
+	return trainings, err
+}
+
+func (t *TrainingRepository) ReduceAvailablePlacesNum(ctx context.Context, id uint64) error {
+	start := time.Now()
+	err := t.next.ReduceAvailablePlacesNum(ctx, id)
+	t.recorder.observe("ReduceAvailablePlacesNum", start, NoRows, err)
+
+	return err
+}
+
+func (t *TrainingRepository) IncreaseAvailablePlacesNum(ctx context.Context, id uint64) error {
+	start := time.Now()
+	err := t.next.IncreaseAvailablePlacesNum(ctx, id)
+	t.recorder.observe("IncreaseAvailablePlacesNum", start, NoRows, err)
+
+	return err
+}
+
+// ExtTrainingRepository also records the methods of the extended training
+// repository.
+type ExtTrainingRepository struct {
+	*TrainingRepository
+	next extRepositories.TrainingRepository
+}
+
+// NewExtTrainingRepository is NewTrainingRepository for the extended
+// training repository.
+func NewExtTrainingRepository(next extRepositories.TrainingRepository, m Metrics) extRepositories.TrainingRepository {
+	return &ExtTrainingRepository{TrainingRepository: newTrainingRepository(next, m), next: next}
+}
+
+func (t *ExtTrainingRepository) Reschedule(ctx context.Context, id uint64, dateTime time.Time) error {
+	start := time.Now()
+	err := t.next.Reschedule(ctx, id, dateTime)
+	t.recorder.observe("Reschedule", start, NoRows, err)
+
+	return err
+}
+
+func (t *ExtTrainingRepository) AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error) {
+	start := time.Now()
+	available, err := t.next.AvailablePlacesNum(ctx, id)
+	t.recorder.observe("AvailablePlacesNum", start, NoRows, err)
+
+	return available, err
+}
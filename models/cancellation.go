@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type CancellationKind string
+
+const (
+	FreeCancellation CancellationKind = "free"
+	LateCancellation CancellationKind = "late"
+)
+
+type Cancellation struct {
+	ID          uint64
+	ClientID    uint64
+	TrainingID  uint64
+	CancelledAt time.Time
+	Kind        CancellationKind
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// CoachAvailability is a weekly working interval of a coach, [StartHour, EndHour).
+type CoachAvailability struct {
+	ID        uint64
+	CoachID   uint64
+	Weekday   time.Weekday
+	StartHour uint64
+	EndHour   uint64
+}
+
+type CoachTimeOff struct {
+	ID      uint64
+	CoachID uint64
+	Start   time.Time
+	End     time.Time
+	Reason  string
+}
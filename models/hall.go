@@ -0,0 +1,19 @@
+package models
+
+type Equipment string
+
+const (
+	Mats      Equipment = "mats"
+	Bikes     Equipment = "bikes"
+	Reformers Equipment = "reformers"
+)
+
+// HallDetails holds the physical attributes of a hall. Zero Capacity and Area
+// mean the attribute is not set.
+type HallDetails struct {
+	HallID    uint64
+	Capacity  uint64
+	Area      float64
+	Floor     int64
+	Equipment map[Equipment]uint64
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// HallClosure is a period [Start, End) when no trainings can run in the hall.
+type HallClosure struct {
+	ID     uint64
+	HallID uint64
+	Start  time.Time
+	End    time.Time
+	Reason string
+}
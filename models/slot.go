@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type Slot struct {
+	HallID   uint64
+	DateTime time.Time
+}
+
+// SlotFilter describes the slots to search for: every slot starts in
+// [Start, End - Duration]. Zero Duration means one training hour, zero CoachID
+// matches any coach and zero MinCapacity matches any hall.
+type SlotFilter struct {
+	Start       time.Time
+	End         time.Time
+	Duration    time.Duration
+	CoachID     uint64
+	MinCapacity uint64
+}
@@ -0,0 +1,316 @@
+package postgreSQL
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nkarakotova/lim-core/managers"
+	transactionManager "github.com/nkarakotova/lim-core/managers/implementation"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	"github.com/nkarakotova/lim-repo/generator"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/jmoiron/sqlx"
+)
+
+// The benchmarks run the hot paths of the repositories against a Postgres
+// seeded with generator.Sizes[-lim.size], on both drivers:
+//
+//	go test ./postgreSQL -run '^$' -bench . -lim.size medium -lim.out old.json
+//	# change the schema or a query
+//	go test ./postgreSQL -run '^$' -bench . -lim.size medium -lim.baseline old.json
+//
+// With -lim.baseline the p50 and p99 latencies are compared with the ones of
+// the earlier run and the run fails when a p99 latency grew by more than
+// -lim.threshold times. The output is the usual one of go test, so benchstat
+// works on it as well.
+var benchSize = flag.String("lim.size", "small", "generator size the benchmarks seed Postgres with")
+
+// benchCandidates is the number of distinct arguments a benchmark cycles
+// through, so it does not measure a single cached row.
+const benchCandidates = 1024
+
+type benchDriver struct {
+	name      string
+	clients   repositories.ClientRepository
+	trainings repositories.TrainingRepository
+	manager   managers.TransactionManager
+}
+
+type benchFixture struct {
+	schema  *TestSchema
+	dataset *generator.Dataset
+	drivers []benchDriver
+	err     error
+}
+
+var (
+	benchOnce  sync.Once
+	benchSetup benchFixture
+)
+
+// benchmarkFixture seeds a schema once for all benchmarks of the run.
+func benchmarkFixture(b *testing.B) *benchFixture {
+	skipWithoutDatabase(b)
+
+	benchOnce.Do(func() {
+		ctx := context.Background()
+
+		cfg, ok := generator.Sizes[*benchSize]
+		if !ok {
+			benchSetup.err = errors.New("unknown -lim.size " + *benchSize)
+			return
+		}
+		benchSetup.dataset, benchSetup.err = generator.Generate(cfg)
+		if benchSetup.err != nil {
+			return
+		}
+
+		benchSetup.schema, benchSetup.err = testDatabase.NewSchema(ctx)
+		if benchSetup.err != nil {
+			return
+		}
+		pool := benchSetup.schema.Pool
+
+		benchSetup.err = ImportDataset(ctx, pool, benchSetup.dataset)
+		if benchSetup.err != nil {
+			return
+		}
+		_, benchSetup.err = pool.Exec(ctx, `analyze;`)
+		if benchSetup.err != nil {
+			return
+		}
+
+		dbx := sqlx.NewDb(benchSetup.schema.DB, "pgx")
+		benchSetup.drivers = []benchDriver{
+			{
+				name:      "stdlib",
+				clients:   NewClientPostgreSQLRepository(dbx),
+				trainings: NewTrainingPostgreSQLRepository(dbx),
+				manager:   transactionManager.NewTransactionManagerImplementation(manager.Must(trmsqlx.NewDefaultFactory(dbx))),
+			},
+			{
+				name:      "pgxpool",
+				clients:   NewClientPgxRepository(pool),
+				trainings: NewTrainingPgxRepository(pool),
+				manager:   transactionManager.NewTransactionManagerImplementation(manager.Must(trmpgx.NewDefaultFactory(pool))),
+			},
+		}
+	})
+	if benchSetup.err != nil {
+		b.Fatalf("error seeding benchmark database: %v", benchSetup.err)
+	}
+
+	return &benchSetup
+}
+
+// closeBenchmarkFixture drops the schema of the benchmarks, if they ran.
+func closeBenchmarkFixture(ctx context.Context) error {
+	if benchSetup.schema == nil {
+		return nil
+	}
+
+	return benchSetup.schema.Close(ctx)
+}
+
+// runDrivers runs bench for every driver as a sub-benchmark.
+func (f *benchFixture) runDrivers(b *testing.B, bench func(b *testing.B, driver benchDriver)) {
+	for _, driver := range f.drivers {
+		b.Run(driver.name, func(b *testing.B) {
+			bench(b, driver)
+		})
+	}
+}
+
+func (f *benchFixture) rng() *rand.Rand {
+	return rand.New(rand.NewSource(1))
+}
+
+func (f *benchFixture) randomClients() []models.Client {
+	rng := f.rng()
+	clients := make([]models.Client, benchCandidates)
+	for i := range clients {
+		clients[i] = f.dataset.Clients[rng.Intn(len(f.dataset.Clients))]
+	}
+
+	return clients
+}
+
+func (f *benchFixture) randomTrainings() []models.Training {
+	rng := f.rng()
+	trainings := make([]models.Training, benchCandidates)
+	for i := range trainings {
+		trainings[i] = f.dataset.Trainings[rng.Intn(len(f.dataset.Trainings))]
+	}
+
+	return trainings
+}
+
+// freeBookings returns client and training pairs that can be booked: the
+// client is not on the training yet and the training has a free place.
+func (f *benchFixture) freeBookings() []generator.Booking {
+	booked := make(map[generator.Booking]bool, len(f.dataset.Bookings))
+	taken := make(map[uint64]uint64, len(f.dataset.Trainings))
+	for _, booking := range f.dataset.Bookings {
+		booked[booking] = true
+		taken[booking.TrainingID]++
+	}
+
+	rng := f.rng()
+	bookings := make([]generator.Booking, 0, benchCandidates)
+	for len(bookings) < benchCandidates {
+		training := f.dataset.Trainings[rng.Intn(len(f.dataset.Trainings))]
+		booking := generator.Booking{
+			ClientID:   f.dataset.Clients[rng.Intn(len(f.dataset.Clients))].ID,
+			TrainingID: training.ID,
+		}
+		if !booked[booking] && taken[training.ID] < training.PlacesNum {
+			bookings = append(bookings, booking)
+		}
+	}
+
+	return bookings
+}
+
+// errRollback rolls back the transaction of a benchmark, so every iteration
+// starts from the seeded data.
+var errRollback = errors.New("rollback")
+
+func BenchmarkBooking(b *testing.B) {
+	f := benchmarkFixture(b)
+	bookings := f.freeBookings()
+
+	f.runDrivers(b, func(b *testing.B, driver benchDriver) {
+		ctx := context.Background()
+		measure(b, func(i int) error {
+			booking := bookings[i%len(bookings)]
+			err := driver.manager.WithinTransaction(ctx, func(ctx context.Context) error {
+				err := driver.clients.CreateAssignment(ctx, booking.ClientID, booking.TrainingID)
+				if err != nil {
+					return err
+				}
+
+				err = driver.trainings.ReduceAvailablePlacesNum(ctx, booking.TrainingID)
+				if err != nil {
+					return err
+				}
+
+				return errRollback
+			})
+			if errors.Is(err, errRollback) {
+				return nil
+			}
+
+			return err
+		})
+	})
+}
+
+func BenchmarkTrainingGetAllBetweenDateTime(b *testing.B) {
+	f := benchmarkFixture(b)
+	trainings := f.randomTrainings()
+
+	for _, window := range []struct {
+		name   string
+		length time.Duration
+	}{{"day", 24 * time.Hour}, {"week", 7 * 24 * time.Hour}} {
+		b.Run(window.name, func(b *testing.B) {
+			f.runDrivers(b, func(b *testing.B, driver benchDriver) {
+				ctx := context.Background()
+				measure(b, func(i int) error {
+					start := trainings[i%len(trainings)].DateTime.Truncate(24 * time.Hour)
+					_, err := driver.trainings.GetAllBetweenDateTime(ctx, start, start.Add(window.length))
+					return err
+				})
+			})
+		})
+	}
+}
+
+func BenchmarkTrainingGetAllByCoachOnDate(b *testing.B) {
+	f := benchmarkFixture(b)
+	trainings := f.randomTrainings()
+
+	f.runDrivers(b, func(b *testing.B, driver benchDriver) {
+		ctx := context.Background()
+		measure(b, func(i int) error {
+			training := trainings[i%len(trainings)]
+			_, err := driver.trainings.GetAllByCoachOnDate(ctx, training.CoachID, training.DateTime)
+			return err
+		})
+	})
+}
+
+func BenchmarkTrainingGetAllByDateTime(b *testing.B) {
+	f := benchmarkFixture(b)
+	trainings := f.randomTrainings()
+
+	f.runDrivers(b, func(b *testing.B, driver benchDriver) {
+		ctx := context.Background()
+		measure(b, func(i int) error {
+			_, err := driver.trainings.GetAllByDateTime(ctx, trainings[i%len(trainings)].DateTime)
+			return err
+		})
+	})
+}
+
+func BenchmarkTrainingGetAllByClient(b *testing.B) {
+	f := benchmarkFixture(b)
+	clients := f.randomClients()
+
+	f.runDrivers(b, func(b *testing.B, driver benchDriver) {
+		ctx := context.Background()
+		measure(b, func(i int) error {
+			_, err := driver.trainings.GetAllByClient(ctx, clients[i%len(clients)].ID)
+			return err
+		})
+	})
+}
+
+func BenchmarkClientGetByID(b *testing.B) {
+	f := benchmarkFixture(b)
+	clients := f.randomClients()
+
+	f.runDrivers(b, func(b *testing.B, driver benchDriver) {
+		ctx := context.Background()
+		measure(b, func(i int) error {
+			_, err := driver.clients.GetByID(ctx, clients[i%len(clients)].ID)
+			return err
+		})
+	})
+}
+
+func BenchmarkClientGetByTelephone(b *testing.B) {
+	f := benchmarkFixture(b)
+	clients := f.randomClients()
+
+	f.runDrivers(b, func(b *testing.B, driver benchDriver) {
+		ctx := context.Background()
+		measure(b, func(i int) error {
+			_, err := driver.clients.GetByTelephone(ctx, clients[i%len(clients)].Telephone)
+			return err
+		})
+	})
+}
+
+func BenchmarkClientGetByTraining(b *testing.B) {
+	f := benchmarkFixture(b)
+	trainings := f.randomTrainings()
+
+	f.runDrivers(b, func(b *testing.B, driver benchDriver) {
+		ctx := context.Background()
+		measure(b, func(i int) error {
+			_, err := driver.clients.GetByTraining(ctx, trainings[i%len(trainings)].ID)
+			return err
+		})
+	})
+}
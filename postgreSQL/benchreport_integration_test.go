@@ -0,0 +1,147 @@
+package postgreSQL
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	benchOut       = flag.String("lim.out", "", "write p50 and p99 latencies of the benchmarks to this JSON file")
+	benchBaseline  = flag.String("lim.baseline", "", "compare the benchmarks with the p50 and p99 latencies of this JSON file")
+	benchThreshold = flag.Float64("lim.threshold", 1.2, "with -lim.baseline, fail when a p99 latency grows by more than this factor")
+)
+
+// benchResult is the latency of the last run of a benchmark, in nanoseconds.
+type benchResult struct {
+	P50 float64 `json:"p50_ns"`
+	P99 float64 `json:"p99_ns"`
+}
+
+var benchResults = struct {
+	sync.Mutex
+	byName map[string]benchResult
+}{byName: map[string]benchResult{}}
+
+// measure runs op b.N times and reports allocations and the p50 and p99
+// latency of an operation. op gets the number of the iteration.
+func measure(b *testing.B, op func(i int) error) {
+	b.ReportAllocs()
+	durations := make([]time.Duration, b.N)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		err := op(i)
+		durations[i] = time.Since(start)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	result := benchResult{
+		P50: float64(percentile(durations, 0.50).Nanoseconds()),
+		P99: float64(percentile(durations, 0.99).Nanoseconds()),
+	}
+	b.ReportMetric(result.P50, "p50-ns")
+	b.ReportMetric(result.P99, "p99-ns")
+
+	// The benchmark is run with a growing b.N, the last run is the one
+	// reported.
+	benchResults.Lock()
+	benchResults.byName[b.Name()] = result
+	benchResults.Unlock()
+}
+
+// percentile of sorted durations, nearest rank.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.5) - 1
+
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
+
+// finishBenchReport writes -lim.out and compares with -lim.baseline. It
+// returns false when a benchmark regressed.
+func finishBenchReport(w io.Writer) (bool, error) {
+	benchResults.Lock()
+	defer benchResults.Unlock()
+
+	if len(benchResults.byName) == 0 {
+		return true, nil
+	}
+
+	if *benchOut != "" {
+		data, err := json.MarshalIndent(benchResults.byName, "", "\t")
+		if err != nil {
+			return false, err
+		}
+		err = os.WriteFile(*benchOut, data, 0o644)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if *benchBaseline == "" {
+		return true, nil
+	}
+
+	data, err := os.ReadFile(*benchBaseline)
+	if err != nil {
+		return false, err
+	}
+	baseline := map[string]benchResult{}
+	err = json.Unmarshal(data, &baseline)
+	if err != nil {
+		return false, err
+	}
+
+	return compareBenchResults(w, baseline, benchResults.byName, *benchThreshold), nil
+}
+
+// compareBenchResults prints the benchmarks found in both baseline and
+// current and returns false when a p99 latency grew by more than threshold
+// times.
+func compareBenchResults(w io.Writer, baseline, current map[string]benchResult, threshold float64) bool {
+	names := make([]string, 0, len(current))
+	for name := range current {
+		if _, ok := baseline[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	ok := true
+	fmt.Fprintf(w, "%-60s %12s %12s %8s %12s %12s %8s\n", "benchmark", "old p50", "new p50", "delta", "old p99", "new p99", "delta")
+	for _, name := range names {
+		old, cur := baseline[name], current[name]
+
+		mark := ""
+		if old.P99 > 0 && cur.P99/old.P99 > threshold {
+			mark = " REGRESSION"
+			ok = false
+		}
+		fmt.Fprintf(w, "%-60s %12.0f %12.0f %7.1f%% %12.0f %12.0f %7.1f%%%s\n",
+			name, old.P50, cur.P50, delta(old.P50, cur.P50), old.P99, cur.P99, delta(old.P99, cur.P99), mark)
+	}
+
+	return ok
+}
+
+func delta(old, cur float64) float64 {
+	if old == 0 {
+		return 0
+	}
+
+	return (cur/old - 1) * 100
+}
@@ -0,0 +1,73 @@
+package postgreSQL
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type BenchReportSuite struct {
+	suite.Suite
+}
+
+func (s *BenchReportSuite) TestPercentile(t provider.T) {
+	t.Title("BenchReport: Percentile")
+	t.Tags("Benchmark")
+	t.WithNewStep("Percentile", func(sCtx provider.StepCtx) {
+		sorted := make([]time.Duration, 100)
+		for i := range sorted {
+			sorted[i] = time.Duration(i+1) * time.Millisecond
+		}
+
+		sCtx.Assert().Equal(50*time.Millisecond, percentile(sorted, 0.50))
+		sCtx.Assert().Equal(99*time.Millisecond, percentile(sorted, 0.99))
+		sCtx.Assert().Equal(time.Millisecond, percentile(sorted[:1], 0.99))
+		sCtx.Assert().Zero(percentile(nil, 0.5))
+	})
+}
+
+func (s *BenchReportSuite) TestCompareRegression(t provider.T) {
+	t.Title("BenchReport: A p99 over the threshold is a regression")
+	t.Tags("Benchmark")
+	t.WithNewStep("Regression", func(sCtx provider.StepCtx) {
+		baseline := map[string]benchResult{
+			"BenchmarkClientGetByID/stdlib": {P50: 100, P99: 200},
+			"BenchmarkRemoved/stdlib":       {P50: 100, P99: 200},
+		}
+		current := map[string]benchResult{
+			"BenchmarkClientGetByID/stdlib": {P50: 110, P99: 300},
+			"BenchmarkAdded/stdlib":         {P50: 100, P99: 200},
+		}
+
+		var out bytes.Buffer
+		ok := compareBenchResults(&out, baseline, current, 1.2)
+
+		sCtx.Assert().False(ok)
+		sCtx.Assert().Contains(out.String(), "BenchmarkClientGetByID/stdlib")
+		sCtx.Assert().Contains(out.String(), "REGRESSION")
+		sCtx.Assert().NotContains(out.String(), "BenchmarkAdded")
+		sCtx.Assert().NotContains(out.String(), "BenchmarkRemoved")
+	})
+}
+
+func (s *BenchReportSuite) TestCompareWithinThreshold(t provider.T) {
+	t.Title("BenchReport: A p99 within the threshold passes")
+	t.Tags("Benchmark")
+	t.WithNewStep("Within threshold", func(sCtx provider.StepCtx) {
+		baseline := map[string]benchResult{"BenchmarkBooking/pgxpool": {P50: 100, P99: 200}}
+		current := map[string]benchResult{"BenchmarkBooking/pgxpool": {P50: 90, P99: 230}}
+
+		var out bytes.Buffer
+		ok := compareBenchResults(&out, baseline, current, 1.2)
+
+		sCtx.Assert().True(ok)
+		sCtx.Assert().NotContains(out.String(), "REGRESSION")
+	})
+}
+
+func TestBenchReportSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(BenchReportSuite))
+}
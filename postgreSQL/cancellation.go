@@ -0,0 +1,163 @@
+package postgreSQL
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/managers"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jinzhu/copier"
+	"github.com/jmoiron/sqlx"
+)
+
+// cancelAssignmentQuery moves a booking from clients_trainings into
+// cancellations. The cancellation is late when it happens inside the
+// late-cancel window of the training, falling back to the window of its hall.
+// With $4 a late cancellation holds its place until the next
+// IncreaseAvailablePlacesNum of the training, see increasePlacesQuery.
+const cancelAssignmentQuery = `with booking as (delete from clients_trainings where client_id=$1 and training_id=$2 returning client_id, training_id),
+cancellation as (select b.client_id, b.training_id,
+$3::timestamp > t.date_time - coalesce(tp.late_cancel_window, hp.late_cancel_window, interval '0') as late
+from booking b join trainings t on t.training_id = b.training_id
+left join training_cancel_policies tp on tp.training_id = t.training_id
+left join hall_cancel_policies hp on hp.hall_id = t.hall_id)
+insert into cancellations(client_id, training_id, cancelled_at, kind, holds_place)
+select client_id, training_id, $3::timestamp, case when late then 'late' else 'free' end, late and $4::boolean
+from cancellation
+returning cancellation_id, client_id, training_id, cancelled_at, kind;`
+
+type CancellationPostgreSQL struct {
+	ID          uint64    `db:"cancellation_id"`
+	ClientID    uint64    `db:"client_id"`
+	TrainingID  uint64    `db:"training_id"`
+	CancelledAt time.Time `db:"cancelled_at"`
+	Kind        string    `db:"kind"`
+}
+
+type CancellationPostgreSQLRepository struct {
+	db         *sqlx.DB
+	txResolver *trmsqlx.CtxGetter
+}
+
+func NewCancellationPostgreSQLRepository(db *sqlx.DB) extRepositories.CancellationRepository {
+	return &CancellationPostgreSQLRepository{db: db, txResolver: trmsqlx.DefaultCtxGetter}
+}
+
+func (c *CancellationPostgreSQLRepository) Cancel(ctx context.Context, clientID, trainingID uint64, cancelledAt time.Time) (*extModels.Cancellation, error) {
+	cancellationDB := &CancellationPostgreSQL{}
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).GetContext(ctx, cancellationDB, cancelAssignmentQuery, clientID, trainingID, cancelledAt.UTC(), false)
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	cancellationModels := &extModels.Cancellation{}
+	err = copier.Copy(cancellationModels, cancellationDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return cancellationModels, nil
+}
+
+func (c *CancellationPostgreSQLRepository) GetAllByTraining(ctx context.Context, trainingID uint64) ([]extModels.Cancellation, error) {
+	query := `select cancellation_id, client_id, training_id, cancelled_at, kind from cancellations where training_id=$1 order by cancelled_at;`
+
+	cancellationDB := []CancellationPostgreSQL{}
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).SelectContext(ctx, &cancellationDB, query, trainingID)
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	cancellationModels := []extModels.Cancellation{}
+	for i := range cancellationDB {
+		cancellation := extModels.Cancellation{}
+		err = copier.Copy(&cancellation, &cancellationDB[i])
+		if err != nil {
+			return nil, err
+		}
+
+		cancellationModels = append(cancellationModels, cancellation)
+	}
+
+	return cancellationModels, nil
+}
+
+func (c *CancellationPostgreSQLRepository) SetHallLateCancelWindow(ctx context.Context, hallID uint64, window time.Duration) error {
+	query := `insert into hall_cancel_policies(hall_id, late_cancel_window) values($1, $2 * interval '1 second')
+on conflict (hall_id) do update set late_cancel_window = excluded.late_cancel_window returning hall_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRowxContext(ctx, query, hallID, window.Seconds()).Scan(&hallID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CancellationPostgreSQLRepository) SetTrainingLateCancelWindow(ctx context.Context, trainingID uint64, window time.Duration) error {
+	query := `insert into training_cancel_policies(training_id, late_cancel_window) values($1, $2 * interval '1 second')
+on conflict (training_id) do update set late_cancel_window = excluded.late_cancel_window returning training_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRowxContext(ctx, query, trainingID, window.Seconds()).Scan(&trainingID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AssignmentCanceller cancels bookings according to the late-cancel policy:
+// the place goes back to the training only for free cancellations.
+type AssignmentCanceller struct {
+	CancellationRepository extRepositories.CancellationRepository
+	TrainingRepository     repositories.TrainingRepository
+	TransactionManager     managers.TransactionManager
+}
+
+func NewAssignmentCanceller(
+	CancellationRepository extRepositories.CancellationRepository,
+	TrainingRepository repositories.TrainingRepository,
+	TransactionManager managers.TransactionManager,
+) *AssignmentCanceller {
+
+	return &AssignmentCanceller{
+		CancellationRepository: CancellationRepository,
+		TrainingRepository:     TrainingRepository,
+		TransactionManager:     TransactionManager,
+	}
+}
+
+func (a *AssignmentCanceller) Cancel(ctx context.Context, clientID, trainingID uint64, cancelledAt time.Time) (*extModels.Cancellation, error) {
+	var cancellation *extModels.Cancellation
+
+	err := a.TransactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+
+		cancellation, err = a.CancellationRepository.Cancel(txCtx, clientID, trainingID, cancelledAt)
+		if err != nil {
+			return err
+		}
+
+		if cancellation.Kind != extModels.FreeCancellation {
+			return nil
+		}
+
+		return a.TrainingRepository.IncreaseAvailablePlacesNum(txCtx, trainingID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cancellation, nil
+}
@@ -0,0 +1,125 @@
+package postgreSQL
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jmoiron/sqlx"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+	"github.com/nkarakotova/lim-core/services"
+	servicesImplementation "github.com/nkarakotova/lim-core/services/implementation"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type CancellationIntegrationSuite struct {
+	suite.Suite
+	schema        *TestSchema
+	clients       repositories.ClientRepository
+	trainings     extRepositories.TrainingRepository
+	cancellations extRepositories.CancellationRepository
+	service       services.ClientService
+	client        *models.Client
+	hall          *models.Hall
+	coach         *models.Coach
+	ctx           context.Context
+}
+
+func (s *CancellationIntegrationSuite) BeforeEach(t provider.T) {
+	s.ctx = context.Background()
+
+	var err error
+	s.schema, err = testDatabase.NewSchema(s.ctx)
+	if err != nil {
+		t.Fatalf("error creating schema: %v", err)
+	}
+
+	dbx := sqlx.NewDb(s.schema.DB, "pgx")
+	s.clients = NewClientPostgreSQLRepository(dbx)
+	s.trainings = NewTrainingPostgreSQLRepository(dbx).(extRepositories.TrainingRepository)
+	s.cancellations = NewCancellationPostgreSQLRepository(dbx)
+	s.service = servicesImplementation.NewClientServiceImplementation(s.clients, s.trainings,
+		CreateTransactionManager(&PostgresRepositoryFields{DB: s.schema.DB}), log.New(io.Discard))
+
+	s.client = postgreSQLObjectMother.NewClient().Build()
+	s.coach = postgreSQLObjectMother.NewCoach().Build()
+	s.hall = postgreSQLObjectMother.NewHall().Build()
+	err = s.clients.Create(s.ctx, s.client)
+	if err == nil {
+		err = NewCoachPostgreSQLRepository(dbx).Create(s.ctx, s.coach)
+	}
+	if err == nil {
+		err = NewHallPostgreSQLRepository(dbx).Create(s.ctx, s.hall)
+	}
+	if err == nil {
+		err = s.cancellations.SetHallLateCancelWindow(s.ctx, s.hall.ID, 2*time.Hour)
+	}
+	if err != nil {
+		t.Fatalf("error creating fixtures: %v", err)
+	}
+}
+
+func (s *CancellationIntegrationSuite) AfterEach(t provider.T) {
+	s.schema.Close(s.ctx)
+}
+
+// book creates a training starting in, books the client on it as the client
+// service does and returns it.
+func (s *CancellationIntegrationSuite) book(sCtx provider.StepCtx, in time.Duration) *models.Training {
+	training := postgreSQLObjectMother.NewTraining().WithCoach(s.coach).WithHall(s.hall).
+		At(time.Now().Add(in).Truncate(time.Hour)).WithPlacesNum(10).Build()
+	sCtx.Require().NoError(s.trainings.Create(s.ctx, training))
+	sCtx.Require().NoError(s.clients.CreateAssignment(s.ctx, s.client.ID, training.ID))
+	sCtx.Require().NoError(s.trainings.ReduceAvailablePlacesNum(s.ctx, training.ID))
+
+	return training
+}
+
+func (s *CancellationIntegrationSuite) available(sCtx provider.StepCtx, training *models.Training) uint64 {
+	available, err := s.trainings.AvailablePlacesNum(s.ctx, training.ID)
+	sCtx.Require().NoError(err)
+
+	return available
+}
+
+func (s *CancellationIntegrationSuite) TestServiceDeleteAssignment(t provider.T) {
+	t.Title("ClientService DeleteAssignment: Only a free cancellation gives the place back")
+	t.Tags("Cancellation", "Integration")
+	t.WithNewStep("Late", func(sCtx provider.StepCtx) {
+		training := s.book(sCtx, 2*time.Hour)
+
+		sCtx.Require().NoError(s.service.DeleteAssignment(s.client.ID, training.ID))
+
+		sCtx.Assert().Equal(uint64(9), s.available(sCtx, training))
+		cancellations, err := s.cancellations.GetAllByTraining(s.ctx, training.ID)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Len(cancellations, 1)
+		sCtx.Assert().Equal(extModels.LateCancellation, cancellations[0].Kind)
+
+		// The hold is used up, a place held otherwise is given back.
+		sCtx.Require().NoError(s.trainings.IncreaseAvailablePlacesNum(s.ctx, training.ID))
+		sCtx.Assert().Equal(uint64(10), s.available(sCtx, training))
+	})
+	t.WithNewStep("Free", func(sCtx provider.StepCtx) {
+		training := s.book(sCtx, 48*time.Hour)
+
+		sCtx.Require().NoError(s.service.DeleteAssignment(s.client.ID, training.ID))
+
+		sCtx.Assert().Equal(uint64(10), s.available(sCtx, training))
+	})
+}
+
+func TestCancellationIntegrationSuiteRunner(t *testing.T) {
+	skipWithoutDatabase(t)
+
+	suite.RunSuite(t, new(CancellationIntegrationSuite))
+}
@@ -0,0 +1,102 @@
+package postgreSQL
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+const cancellationColumns = `cancellation_id, client_id, training_id, cancelled_at, kind`
+
+// CancellationPgxRepository is CancellationPostgreSQLRepository on a native
+// pgx connection or pool, so that it joins the trmpgx transactions of the
+// pgx client and training repositories.
+type CancellationPgxRepository struct {
+	db         trmpgx.Tr
+	txResolver *trmpgx.CtxGetter
+}
+
+func NewCancellationPgxRepository(db trmpgx.Tr) extRepositories.CancellationRepository {
+	return &CancellationPgxRepository{db: db, txResolver: trmpgx.DefaultCtxGetter}
+}
+
+func scanCancellation(row pgx.Row) (*extModels.Cancellation, error) {
+	cancellation := &CancellationPostgreSQL{}
+	err := row.Scan(&cancellation.ID, &cancellation.ClientID, &cancellation.TrainingID, &cancellation.CancelledAt, &cancellation.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return &extModels.Cancellation{
+		ID:          cancellation.ID,
+		ClientID:    cancellation.ClientID,
+		TrainingID:  cancellation.TrainingID,
+		CancelledAt: cancellation.CancelledAt,
+		Kind:        extModels.CancellationKind(cancellation.Kind),
+	}, nil
+}
+
+func (c *CancellationPgxRepository) Cancel(ctx context.Context, clientID, trainingID uint64, cancelledAt time.Time) (*extModels.Cancellation, error) {
+	cancellation, err := scanCancellation(c.txResolver.DefaultTrOrDB(ctx, c.db).
+		QueryRow(ctx, cancelAssignmentQuery, clientID, trainingID, cancelledAt.UTC(), false))
+	if err == pgx.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	return cancellation, nil
+}
+
+func (c *CancellationPgxRepository) GetAllByTraining(ctx context.Context, trainingID uint64) ([]extModels.Cancellation, error) {
+	query := `select ` + cancellationColumns + ` from cancellations where training_id=$1 order by cancelled_at;`
+
+	rows, err := c.txResolver.DefaultTrOrDB(ctx, c.db).Query(ctx, query, trainingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cancellationModels := []extModels.Cancellation{}
+	for rows.Next() {
+		cancellation, err := scanCancellation(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		cancellationModels = append(cancellationModels, *cancellation)
+	}
+
+	return cancellationModels, rows.Err()
+}
+
+func (c *CancellationPgxRepository) SetHallLateCancelWindow(ctx context.Context, hallID uint64, window time.Duration) error {
+	query := `insert into hall_cancel_policies(hall_id, late_cancel_window) values($1, $2 * interval '1 second')
+on conflict (hall_id) do update set late_cancel_window = excluded.late_cancel_window returning hall_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRow(ctx, query, hallID, window.Seconds()).Scan(&hallID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CancellationPgxRepository) SetTrainingLateCancelWindow(ctx context.Context, trainingID uint64, window time.Duration) error {
+	query := `insert into training_cancel_policies(training_id, late_cancel_window) values($1, $2 * interval '1 second')
+on conflict (training_id) do update set late_cancel_window = excluded.late_cancel_window returning training_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRow(ctx, query, trainingID, window.Seconds()).Scan(&trainingID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
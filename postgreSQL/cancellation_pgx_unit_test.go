@@ -0,0 +1,143 @@
+package postgreSQL
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	transactionManager "github.com/nkarakotova/lim-core/managers/implementation"
+	"github.com/nkarakotova/lim-repo/models"
+	"github.com/nkarakotova/lim-repo/repositories"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type CancellationPgxSuite struct {
+	suite.Suite
+	mock       pgxmock.PgxPoolIface
+	repository repositories.CancellationRepository
+	canceller  *AssignmentCanceller
+	ctx        context.Context
+}
+
+func (s *CancellationPgxSuite) BeforeEach(t provider.T) {
+	var err error
+	s.mock, err = pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error creating mock pool: %v", err)
+	}
+	s.repository = NewCancellationPgxRepository(s.mock)
+	s.canceller = NewAssignmentCanceller(
+		s.repository,
+		NewTrainingPgxRepository(s.mock),
+		transactionManager.NewTransactionManagerImplementation(manager.Must(trmpgx.NewDefaultFactory(s.mock))),
+	)
+	s.ctx = context.Background()
+}
+
+func (s *CancellationPgxSuite) AfterEach(t provider.T) {
+	s.mock.Close()
+}
+
+func (s *CancellationPgxSuite) TestCancellationPgxCancelSuccess(t provider.T) {
+	t.Title("CancellationPgxCancel: Success")
+	t.Tags("Cancellation", "Pgx")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		cancelledAt := time.Date(2024, 7, 7, 11, 30, 0, 0, time.UTC)
+		s.mock.ExpectQuery(cancelAssignmentQuery).
+			WithArgs(uint64(1), uint64(1), cancelledAt, false).
+			WillReturnRows(pgxmock.NewRows([]string{"cancellation_id", "client_id", "training_id", "cancelled_at", "kind"}).
+				AddRow(uint64(1), uint64(1), uint64(1), cancelledAt, "late"))
+
+		cancellation, err := s.repository.Cancel(s.ctx, 1, 1, cancelledAt)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(&models.Cancellation{
+			ID:          1,
+			ClientID:    1,
+			TrainingID:  1,
+			CancelledAt: cancelledAt,
+			Kind:        models.LateCancellation,
+		}, cancellation)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CancellationPgxSuite) TestAssignmentCancellerPgxFreeCancellation(t provider.T) {
+	t.Title("AssignmentCanceller: Free cancellation on pgxpool returns the place in the same transaction")
+	t.Tags("Cancellation", "Pgx")
+	t.WithNewStep("Free", func(sCtx provider.StepCtx) {
+		cancelledAt := time.Date(2024, 7, 6, 12, 0, 0, 0, time.UTC)
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(cancelAssignmentQuery).
+			WithArgs(uint64(1), uint64(1), cancelledAt, false).
+			WillReturnRows(pgxmock.NewRows([]string{"cancellation_id", "client_id", "training_id", "cancelled_at", "kind"}).
+				AddRow(uint64(1), uint64(1), uint64(1), cancelledAt, "free"))
+		s.mock.ExpectQuery(increasePlacesQuery).
+			WithArgs(uint64(1)).
+			WillReturnRows(pgxmock.NewRows([]string{"training_id"}).AddRow(uint64(1)))
+		s.mock.ExpectCommit()
+
+		cancellation, err := s.canceller.Cancel(s.ctx, 1, 1, cancelledAt)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(models.FreeCancellation, cancellation.Kind)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CancellationPgxSuite) TestAssignmentCancellerPgxRollback(t provider.T) {
+	t.Title("AssignmentCanceller: A failed place update on pgxpool rolls the cancellation back")
+	t.Tags("Cancellation", "Pgx")
+	t.WithNewStep("Rollback", func(sCtx provider.StepCtx) {
+		cancelledAt := time.Date(2024, 7, 6, 12, 0, 0, 0, time.UTC)
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(cancelAssignmentQuery).
+			WithArgs(uint64(1), uint64(1), cancelledAt, false).
+			WillReturnRows(pgxmock.NewRows([]string{"cancellation_id", "client_id", "training_id", "cancelled_at", "kind"}).
+				AddRow(uint64(1), uint64(1), uint64(1), cancelledAt, "free"))
+		s.mock.ExpectQuery(increasePlacesQuery).
+			WithArgs(uint64(1)).
+			WillReturnError(pgx.ErrNoRows)
+		s.mock.ExpectRollback()
+
+		_, err := s.canceller.Cancel(s.ctx, 1, 1, cancelledAt)
+
+		sCtx.Assert().ErrorIs(err, pgx.ErrNoRows)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+	t.WithNewStep("Missing booking", func(sCtx provider.StepCtx) {
+		cancelledAt := time.Date(2024, 7, 6, 12, 0, 0, 0, time.UTC)
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(cancelAssignmentQuery).WithArgs(uint64(1), uint64(1), cancelledAt, false).WillReturnError(pgx.ErrNoRows)
+		s.mock.ExpectRollback()
+
+		_, err := s.canceller.Cancel(s.ctx, 1, 1, cancelledAt)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestCancellationPgxSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(CancellationPgxSuite))
+}
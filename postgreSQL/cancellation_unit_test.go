@@ -0,0 +1,230 @@
+package postgreSQL
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	transactionManager "github.com/nkarakotova/lim-core/managers/implementation"
+	"github.com/nkarakotova/lim-repo/models"
+	"github.com/nkarakotova/lim-repo/repositories"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type CancellationSuite struct {
+	suite.Suite
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+	repository repositories.CancellationRepository
+	canceller  *AssignmentCanceller
+	ctx        context.Context
+}
+
+func (s *CancellationSuite) BeforeEach(t provider.T) {
+	var err error
+	s.db, s.mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	dbx := sqlx.NewDb(s.db, "pgx")
+	s.repository = NewCancellationPostgreSQLRepository(dbx)
+	s.canceller = NewAssignmentCanceller(
+		s.repository,
+		NewTrainingPostgreSQLRepository(dbx),
+		transactionManager.NewTransactionManagerImplementation(manager.Must(trmsqlx.NewDefaultFactory(dbx))),
+	)
+	s.ctx = context.Background()
+}
+
+func (s *CancellationSuite) AfterEach(t provider.T) {
+	s.db.Close()
+}
+
+func (s *CancellationSuite) TestCancellationMockCancelSuccess(t provider.T) {
+	t.Title("CancellationMockCancel: Success")
+	t.Tags("Cancellation")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		cancelledAt := time.Date(2024, 7, 7, 11, 30, 0, 0, time.UTC)
+		s.mock.ExpectQuery(cancelAssignmentQuery).
+			WithArgs(1, 1, cancelledAt, false).
+			WillReturnRows(sqlmock.NewRows([]string{"cancellation_id", "client_id", "training_id", "cancelled_at", "kind"}).
+				AddRow(1, 1, 1, cancelledAt, "late"))
+
+		cancellation, err := s.repository.Cancel(s.ctx, 1, 1, cancelledAt)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(&models.Cancellation{
+			ID:          1,
+			ClientID:    1,
+			TrainingID:  1,
+			CancelledAt: cancelledAt,
+			Kind:        models.LateCancellation,
+		}, cancellation)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CancellationSuite) TestCancellationMockCancelFailure(t provider.T) {
+	t.Title("CancellationMockCancel: Failure")
+	t.Tags("Cancellation")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		cancelledAt := time.Date(2024, 7, 7, 11, 30, 0, 0, time.UTC)
+		s.mock.ExpectQuery(cancelAssignmentQuery).WithArgs(1, 1, cancelledAt, false).WillReturnError(sql.ErrNoRows)
+
+		_, err := s.repository.Cancel(s.ctx, 1, 1, cancelledAt)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CancellationSuite) TestCancellationMockGetAllByTrainingSuccess(t provider.T) {
+	t.Title("CancellationMockGetAllByTraining: Success")
+	t.Tags("Cancellation")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		cancelledAt := time.Date(2024, 7, 6, 12, 0, 0, 0, time.UTC)
+		s.mock.ExpectQuery(`select cancellation_id, client_id, training_id, cancelled_at, kind from cancellations where training_id=$1 order by cancelled_at;`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"cancellation_id", "client_id", "training_id", "cancelled_at", "kind"}).
+				AddRow(1, 1, 1, cancelledAt, "free"))
+
+		cancellations, err := s.repository.GetAllByTraining(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Cancellation{{
+			ID:          1,
+			ClientID:    1,
+			TrainingID:  1,
+			CancelledAt: cancelledAt,
+			Kind:        models.FreeCancellation,
+		}}, cancellations)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CancellationSuite) TestCancellationMockSetTrainingLateCancelWindowSuccess(t provider.T) {
+	t.Title("CancellationMockSetTrainingLateCancelWindow: Success")
+	t.Tags("Cancellation")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`insert into training_cancel_policies(training_id, late_cancel_window) values($1, $2 * interval '1 second')
+on conflict (training_id) do update set late_cancel_window = excluded.late_cancel_window returning training_id;`).
+			WithArgs(1, float64(7200)).
+			WillReturnRows(sqlmock.NewRows([]string{"training_id"}).AddRow(1))
+
+		err := s.repository.SetTrainingLateCancelWindow(s.ctx, 1, 2*time.Hour)
+
+		sCtx.Assert().NoError(err)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CancellationSuite) TestCancellationMockSetHallLateCancelWindowFailure(t provider.T) {
+	t.Title("CancellationMockSetHallLateCancelWindow: Failure")
+	t.Tags("Cancellation")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`insert into hall_cancel_policies(hall_id, late_cancel_window) values($1, $2 * interval '1 second')
+on conflict (hall_id) do update set late_cancel_window = excluded.late_cancel_window returning hall_id;`).
+			WithArgs(1, float64(3600))
+
+		err := s.repository.SetHallLateCancelWindow(s.ctx, 1, time.Hour)
+
+		sCtx.Assert().Error(err)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CancellationSuite) TestAssignmentCancellerFreeCancellation(t provider.T) {
+	t.Title("AssignmentCanceller: Free cancellation returns the place")
+	t.Tags("Cancellation")
+	t.WithNewStep("Free", func(sCtx provider.StepCtx) {
+		cancelledAt := time.Date(2024, 7, 6, 12, 0, 0, 0, time.UTC)
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(cancelAssignmentQuery).
+			WithArgs(1, 1, cancelledAt, false).
+			WillReturnRows(sqlmock.NewRows([]string{"cancellation_id", "client_id", "training_id", "cancelled_at", "kind"}).
+				AddRow(1, 1, 1, cancelledAt, "free"))
+		s.mock.ExpectQuery(increasePlacesQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"training_id"}).AddRow(1))
+		s.mock.ExpectCommit()
+
+		cancellation, err := s.canceller.Cancel(s.ctx, 1, 1, cancelledAt)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(models.FreeCancellation, cancellation.Kind)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CancellationSuite) TestAssignmentCancellerLateCancellation(t provider.T) {
+	t.Title("AssignmentCanceller: Late cancellation keeps the place")
+	t.Tags("Cancellation")
+	t.WithNewStep("Late", func(sCtx provider.StepCtx) {
+		cancelledAt := time.Date(2024, 7, 7, 11, 30, 0, 0, time.UTC)
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(cancelAssignmentQuery).
+			WithArgs(1, 1, cancelledAt, false).
+			WillReturnRows(sqlmock.NewRows([]string{"cancellation_id", "client_id", "training_id", "cancelled_at", "kind"}).
+				AddRow(1, 1, 1, cancelledAt, "late"))
+		s.mock.ExpectCommit()
+
+		cancellation, err := s.canceller.Cancel(s.ctx, 1, 1, cancelledAt)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(models.LateCancellation, cancellation.Kind)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CancellationSuite) TestAssignmentCancellerRollback(t provider.T) {
+	t.Title("AssignmentCanceller: Missing booking rolls back")
+	t.Tags("Cancellation")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		cancelledAt := time.Date(2024, 7, 6, 12, 0, 0, 0, time.UTC)
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(cancelAssignmentQuery).WithArgs(1, 1, cancelledAt, false).WillReturnError(sql.ErrNoRows)
+		s.mock.ExpectRollback()
+
+		_, err := s.canceller.Cancel(s.ctx, 1, 1, cancelledAt)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestCancellationSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(CancellationSuite))
+}
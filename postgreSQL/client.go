@@ -3,6 +3,7 @@ package postgreSQL
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
 	"github.com/nkarakotova/lim-core/models"
@@ -121,10 +122,13 @@ func (c *ClientPostgreSQLRepository) CreateAssignment(ctx context.Context, clien
 	return nil
 }
 
+// DeleteAssignment records the cancellation of the booking instead of
+// dropping it. A late cancellation holds its place, so that the
+// IncreaseAvailablePlacesNum the client service runs next keeps it taken;
+// AssignmentCanceller applies the late-cancel policy in a single call.
 func (c *ClientPostgreSQLRepository) DeleteAssignment(ctx context.Context, clientID, trainingID uint64) error {
-	query := `delete from clients_trainings where client_id=$1 and training_id=$2 returning client_id;`
-
-	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRowxContext(ctx, query, clientID, trainingID).Scan(&clientID)
+	cancellationDB := &CancellationPostgreSQL{}
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).GetContext(ctx, cancellationDB, cancelAssignmentQuery, clientID, trainingID, time.Now().UTC(), true)
 	if err == sql.ErrNoRows {
 		return repositoriesErrors.EntityDoesNotExists
 	} else if err != nil {
@@ -0,0 +1,131 @@
+package postgreSQL
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+const clientColumns = `client_id, name, telephone, mail, password`
+
+// ClientPgxRepository works on a native pgx connection, a pool or a mock of
+// them, and joins the trmpgx transaction found in the context.
+type ClientPgxRepository struct {
+	db         trmpgx.Tr
+	txResolver *trmpgx.CtxGetter
+}
+
+func NewClientPgxRepository(db trmpgx.Tr) repositories.ClientRepository {
+	return &ClientPgxRepository{db: db, txResolver: trmpgx.DefaultCtxGetter}
+}
+
+func scanClients(rows pgx.Rows) ([]models.Client, error) {
+	defer rows.Close()
+
+	clientModels := []models.Client{}
+	for rows.Next() {
+		client := models.Client{}
+		err := rows.Scan(&client.ID, &client.Name, &client.Telephone, &client.Mail, &client.Password)
+		if err != nil {
+			return nil, err
+		}
+
+		clientModels = append(clientModels, client)
+	}
+
+	return clientModels, rows.Err()
+}
+
+func (c *ClientPgxRepository) Create(ctx context.Context, client *models.Client) error {
+	query := `insert into clients(name, telephone, mail, password) values($1, $2, $3, $4) returning client_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).
+		QueryRow(ctx, query, client.Name, client.Telephone, client.Mail, client.Password).
+		Scan(&client.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Import loads clients with COPY. It is meant for bulk loads, so the
+// generated IDs are not written back to the clients.
+func (c *ClientPgxRepository) Import(ctx context.Context, clients []models.Client) (int64, error) {
+	return c.txResolver.DefaultTrOrDB(ctx, c.db).CopyFrom(ctx,
+		pgx.Identifier{"clients"},
+		[]string{"name", "telephone", "mail", "password"},
+		pgx.CopyFromSlice(len(clients), func(i int) ([]interface{}, error) {
+			return []interface{}{clients[i].Name, clients[i].Telephone, clients[i].Mail, clients[i].Password}, nil
+		}))
+}
+
+func (c *ClientPgxRepository) getOne(ctx context.Context, query string, arg interface{}) (*models.Client, error) {
+	client := &models.Client{}
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).
+		QueryRow(ctx, query, arg).
+		Scan(&client.ID, &client.Name, &client.Telephone, &client.Mail, &client.Password)
+	if err == pgx.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (c *ClientPgxRepository) GetByID(ctx context.Context, id uint64) (*models.Client, error) {
+	query := `select ` + clientColumns + ` from clients where client_id = $1;`
+
+	return c.getOne(ctx, query, id)
+}
+
+func (c *ClientPgxRepository) GetByTelephone(ctx context.Context, telephone string) (*models.Client, error) {
+	query := `select ` + clientColumns + ` from clients where telephone = $1;`
+
+	return c.getOne(ctx, query, telephone)
+}
+
+func (c *ClientPgxRepository) GetByTraining(ctx context.Context, id uint64) ([]models.Client, error) {
+	query := `select ` + clientColumns + ` from clients where client_id in (select client_id from clients_trainings where training_id=$1);`
+
+	rows, err := c.txResolver.DefaultTrOrDB(ctx, c.db).Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanClients(rows)
+}
+
+func (c *ClientPgxRepository) CreateAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	query := `insert into clients_trainings(client_id, training_id) values($1, $2) returning client_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRow(ctx, query, clientID, trainingID).Scan(&clientID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAssignment records the cancellation of the booking the same way
+// ClientPostgreSQLRepository does.
+func (c *ClientPgxRepository) DeleteAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	cancellation := &CancellationPostgreSQL{}
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).
+		QueryRow(ctx, cancelAssignmentQuery, clientID, trainingID, time.Now().UTC(), true).
+		Scan(&cancellation.ID, &cancellation.ClientID, &cancellation.TrainingID, &cancellation.CancelledAt, &cancellation.Kind)
+	if err == pgx.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
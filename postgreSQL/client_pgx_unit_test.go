@@ -0,0 +1,180 @@
+package postgreSQL
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type ClientPgxSuite struct {
+	suite.Suite
+	mock       pgxmock.PgxPoolIface
+	repository repositories.ClientRepository
+	ctx        context.Context
+}
+
+func (s *ClientPgxSuite) BeforeEach(t provider.T) {
+	var err error
+	s.mock, err = pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error creating mock pool: %v", err)
+	}
+	s.repository = NewClientPgxRepository(s.mock)
+	s.ctx = context.Background()
+}
+
+func (s *ClientPgxSuite) AfterEach(t provider.T) {
+	s.mock.Close()
+}
+
+func (s *ClientPgxSuite) TestClientPgxCreateSuccess(t provider.T) {
+	t.Title("ClientPgxCreate: Success")
+	t.Tags("Client", "Pgx")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`insert into clients(name, telephone, mail, password) values($1, $2, $3, $4) returning client_id;`).
+			WithArgs("Name", "1234567890", "mail@mail.ru", "123").
+			WillReturnRows(pgxmock.NewRows([]string{"client_id"}).AddRow(uint64(7)))
+
+		client := postgreSQLObjectMother.CreateTestClient()
+		err := s.repository.Create(s.ctx, client)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(uint64(7), client.ID)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *ClientPgxSuite) TestClientPgxCreateFailure(t provider.T) {
+	t.Title("ClientPgxCreate: Failure")
+	t.Tags("Client", "Pgx")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`insert into clients(name, telephone, mail, password) values($1, $2, $3, $4) returning client_id;`).
+			WithArgs("Name", "1234567890", "mail@mail.ru", "123").
+			WillReturnError(errors.New("duplicate key"))
+
+		client := postgreSQLObjectMother.CreateTestClient()
+		err := s.repository.Create(s.ctx, client)
+
+		sCtx.Assert().Error(err)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *ClientPgxSuite) TestClientPgxGetByIDSuccess(t provider.T) {
+	t.Title("ClientPgxGetByID: Success")
+	t.Tags("Client", "Pgx")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`select client_id, name, telephone, mail, password from clients where client_id = $1;`).
+			WithArgs(uint64(1)).
+			WillReturnRows(pgxmock.NewRows([]string{"client_id", "name", "telephone", "mail", "password"}).
+				AddRow(uint64(1), "Name", "1234567890", "mail@mail.ru", "123"))
+
+		client, err := s.repository.GetByID(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(postgreSQLObjectMother.CreateTestClient(), client)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *ClientPgxSuite) TestClientPgxGetByIDNotFound(t provider.T) {
+	t.Title("ClientPgxGetByID: Not found")
+	t.Tags("Client", "Pgx")
+	t.WithNewStep("Not found", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`select client_id, name, telephone, mail, password from clients where client_id = $1;`).
+			WithArgs(uint64(1)).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := s.repository.GetByID(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *ClientPgxSuite) TestClientPgxGetByTrainingSuccess(t provider.T) {
+	t.Title("ClientPgxGetByTraining: Success")
+	t.Tags("Client", "Pgx")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`select client_id, name, telephone, mail, password from clients where client_id in (select client_id from clients_trainings where training_id=$1);`).
+			WithArgs(uint64(1)).
+			WillReturnRows(pgxmock.NewRows([]string{"client_id", "name", "telephone", "mail", "password"}).
+				AddRow(uint64(1), "Name", "1234567890", "mail@mail.ru", "123").
+				AddRow(uint64(2), "Other", "0987654321", "other@mail.ru", "321"))
+
+		clients, err := s.repository.GetByTraining(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Len(clients, 2)
+		sCtx.Assert().Equal(*postgreSQLObjectMother.CreateTestClient(), clients[0])
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *ClientPgxSuite) TestClientPgxImportSuccess(t provider.T) {
+	t.Title("ClientPgxImport: Success")
+	t.Tags("Client", "Pgx")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectCopyFrom(`"clients"`, []string{"name", "telephone", "mail", "password"}).
+			WillReturnResult(2)
+
+		clients := []models.Client{*postgreSQLObjectMother.CreateTestClient(), *postgreSQLObjectMother.CreateTestClient()}
+		count, err := s.repository.(*ClientPgxRepository).Import(s.ctx, clients)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(int64(2), count)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *ClientPgxSuite) TestClientPgxDeleteAssignmentNotFound(t provider.T) {
+	t.Title("ClientPgxDeleteAssignment: Not found")
+	t.Tags("Client", "Pgx")
+	t.WithNewStep("Not found", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(cancelAssignmentQuery).
+			WithArgs(uint64(1), uint64(1), pgxmock.AnyArg(), true).
+			WillReturnError(pgx.ErrNoRows)
+
+		err := s.repository.DeleteAssignment(s.ctx, 1, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestClientPgxSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(ClientPgxSuite))
+}
@@ -3,12 +3,16 @@ package postgreSQL
 import (
 	"context"
 	"database/sql"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/charmbracelet/log"
 	"github.com/jmoiron/sqlx"
 	"github.com/nkarakotova/lim-core/models"
 	"github.com/nkarakotova/lim-core/repositories"
+	servicesImplementation "github.com/nkarakotova/lim-core/services/implementation"
 
 	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
 	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
@@ -227,9 +231,10 @@ func (s *ClientSuite) TestClientMockDeleteAssignmentSuccess(t provider.T) {
 	t.Title("ClientMockDeleteAssignment: Success")
 	t.Tags("Client")
 	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
-		s.mock.ExpectQuery(`delete from clients_trainings where client_id=$1 and training_id=$2 returning client_id;`).
-			WithArgs(1, 1).
-			WillReturnRows(sqlmock.NewRows([]string{"client_id"}).AddRow(1))
+		s.mock.ExpectQuery(cancelAssignmentQuery).
+			WithArgs(1, 1, sqlmock.AnyArg(), true).
+			WillReturnRows(sqlmock.NewRows([]string{"cancellation_id", "client_id", "training_id", "cancelled_at", "kind"}).
+				AddRow(1, 1, 1, time.Date(2024, 7, 7, 10, 0, 0, 0, time.UTC), "free"))
 
 		err := s.repository.DeleteAssignment(s.ctx, 1, 1)
 
@@ -245,11 +250,39 @@ func (s *ClientSuite) TestClientMockDeleteAssignmentFailure(t provider.T) {
 	t.Title("ClientMockDeleteAssignment: Failure")
 	t.Tags("Client")
 	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
-		s.mock.ExpectQuery(`delete from clients_trainings where client_id=$1 and training_id=$2 returning client_id;`).WithArgs(1, 1)
+		s.mock.ExpectQuery(cancelAssignmentQuery).WithArgs(1, 1, sqlmock.AnyArg(), true).WillReturnError(sql.ErrNoRows)
 
 		err := s.repository.DeleteAssignment(s.ctx, 1, 1)
 
-		sCtx.Assert().Error(err)
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *ClientSuite) TestClientServiceDeleteAssignmentHoldsLatePlace(t provider.T) {
+	t.Title("ClientService DeleteAssignment: A late cancellation holds its place for IncreaseAvailablePlacesNum")
+	t.Tags("Client", "Cancellation")
+	t.WithNewStep("Service", func(sCtx provider.StepCtx) {
+		dbx := sqlx.NewDb(s.db, "pgx")
+		service := servicesImplementation.NewClientServiceImplementation(s.repository, NewTrainingPostgreSQLRepository(dbx),
+			CreateTransactionManager(&PostgresRepositoryFields{DB: s.db}), log.New(io.Discard))
+
+		s.mock.ExpectBegin()
+		s.mock.ExpectQuery(cancelAssignmentQuery).
+			WithArgs(1, 1, sqlmock.AnyArg(), true).
+			WillReturnRows(sqlmock.NewRows([]string{"cancellation_id", "client_id", "training_id", "cancelled_at", "kind"}).
+				AddRow(1, 1, 1, time.Date(2024, 7, 7, 11, 30, 0, 0, time.UTC), "late"))
+		s.mock.ExpectQuery(increasePlacesQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"training_id"}).AddRow(1))
+		s.mock.ExpectCommit()
+
+		err := service.DeleteAssignment(1, 1)
+
+		sCtx.Assert().NoError(err)
 
 		if err := s.mock.ExpectationsWereMet(); err != nil {
 			t.Errorf("there were unfulfilled expectations: %s", err)
@@ -0,0 +1,79 @@
+package postgreSQL
+
+import (
+	"context"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+type CoachPgxRepository struct {
+	db         trmpgx.Tr
+	txResolver *trmpgx.CtxGetter
+}
+
+func NewCoachPgxRepository(db trmpgx.Tr) repositories.CoachRepository {
+	return &CoachPgxRepository{db: db, txResolver: trmpgx.DefaultCtxGetter}
+}
+
+func (c *CoachPgxRepository) Create(ctx context.Context, coach *models.Coach) error {
+	query := `insert into coaches(name) values($1) returning coach_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRow(ctx, query, coach.Name).Scan(&coach.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CoachPgxRepository) getOne(ctx context.Context, query string, arg interface{}) (*models.Coach, error) {
+	coach := &models.Coach{}
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRow(ctx, query, arg).Scan(&coach.ID, &coach.Name)
+	if err == pgx.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	return coach, nil
+}
+
+func (c *CoachPgxRepository) GetByID(ctx context.Context, id uint64) (*models.Coach, error) {
+	query := `select coach_id, name from coaches where coach_id = $1;`
+
+	return c.getOne(ctx, query, id)
+}
+
+func (c *CoachPgxRepository) GetByName(ctx context.Context, name string) (*models.Coach, error) {
+	query := `select coach_id, name from coaches where name = $1;`
+
+	return c.getOne(ctx, query, name)
+}
+
+func (c *CoachPgxRepository) GetAll(ctx context.Context) ([]models.Coach, error) {
+	query := `select coach_id, name from coaches;`
+
+	rows, err := c.txResolver.DefaultTrOrDB(ctx, c.db).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	coachModels := []models.Coach{}
+	for rows.Next() {
+		coach := models.Coach{}
+		err = rows.Scan(&coach.ID, &coach.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		coachModels = append(coachModels, coach)
+	}
+
+	return coachModels, rows.Err()
+}
@@ -0,0 +1,202 @@
+package postgreSQL
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+
+	"github.com/nkarakotova/lim-repo/models"
+	"github.com/nkarakotova/lim-repo/repositories"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jinzhu/copier"
+	"github.com/jmoiron/sqlx"
+)
+
+// coachAvailableQuery checks that a one hour training starting at $2 fits into
+// the weekly availability of coach $1 and does not touch any of the coach's
+// time off. A coach without configured availability is treated as always
+// available. $2 is in UTC, as stored date times are; the weekday and hours of
+// the availability are taken in the TimeZone of the session, the studio's.
+const coachAvailableQuery = `with l as (select ($2::timestamp at time zone 'UTC')::timestamp as l)
+select (not exists (select 1 from coach_availability where coach_id=$1)
+or exists (select 1 from coach_availability, l where coach_id=$1 and weekday=extract(dow from l)
+and l >= l::date + make_interval(hours => start_hour) and l + interval '1 hour' <= l::date + make_interval(hours => end_hour)))
+and not exists (select 1 from coach_time_off where coach_id=$1 and start_date_time < $2::timestamp + interval '1 hour' and end_date_time > $2::timestamp) as available;`
+
+// coachFreeSlotsQuery returns the hourly start times on date $2 from the
+// hour $3 until the hour $4 when coach $1 is available and has no training.
+// The hours l are those of the TimeZone of the session, the start times s
+// are in UTC.
+const coachFreeSlotsQuery = `select s from generate_series($2::date + make_interval(hours => $3), $2::date + make_interval(hours => $4 - 1), interval '1 hour') g(l)
+cross join lateral (select g.l::timestamptz at time zone 'UTC') u(s)
+where (not exists (select 1 from coach_availability where coach_id=$1)
+or exists (select 1 from coach_availability a where a.coach_id=$1 and a.weekday=extract(dow from l)
+and l >= l::date + make_interval(hours => a.start_hour) and l + interval '1 hour' <= l::date + make_interval(hours => a.end_hour)))
+and not exists (select 1 from coach_time_off o where o.coach_id=$1 and o.start_date_time < s + interval '1 hour' and o.end_date_time > s)
+and not exists (select 1 from trainings t where t.coach_id=$1 and t.date_time < s + interval '1 hour' and t.date_time + interval '1 hour' > s)
+order by s;`
+
+type CoachAvailabilityPostgreSQL struct {
+	ID        uint64 `db:"coach_availability_id"`
+	CoachID   uint64 `db:"coach_id"`
+	Weekday   int    `db:"weekday"`
+	StartHour uint64 `db:"start_hour"`
+	EndHour   uint64 `db:"end_hour"`
+}
+
+type CoachTimeOffPostgreSQL struct {
+	ID      uint64    `db:"coach_time_off_id"`
+	CoachID uint64    `db:"coach_id"`
+	Start   time.Time `db:"start_date_time"`
+	End     time.Time `db:"end_date_time"`
+	Reason  string    `db:"reason"`
+}
+
+type CoachSchedulePostgreSQLRepository struct {
+	db                *sqlx.DB
+	txResolver        *trmsqlx.CtxGetter
+	firstTrainingTime int
+	lastTrainingTime  int
+}
+
+// NewCoachSchedulePostgreSQLRepository returns the repository offering the
+// free slots from the firstTrainingTime hour until the lastTrainingTime hour,
+// as config.Config sets them.
+func NewCoachSchedulePostgreSQLRepository(db *sqlx.DB, firstTrainingTime, lastTrainingTime int) repositories.CoachScheduleRepository {
+	return &CoachSchedulePostgreSQLRepository{
+		db:                db,
+		txResolver:        trmsqlx.DefaultCtxGetter,
+		firstTrainingTime: firstTrainingTime,
+		lastTrainingTime:  lastTrainingTime,
+	}
+}
+
+func (c *CoachSchedulePostgreSQLRepository) CreateAvailability(ctx context.Context, availability *models.CoachAvailability) error {
+	query := `insert into coach_availability(coach_id, weekday, start_hour, end_hour) values($1, $2, $3, $4) returning coach_availability_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).
+		QueryRowxContext(ctx, query, availability.CoachID, int(availability.Weekday), availability.StartHour, availability.EndHour).
+		Scan(&availability.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CoachSchedulePostgreSQLRepository) GetAvailabilityByCoach(ctx context.Context, coachID uint64) ([]models.CoachAvailability, error) {
+	query := `select * from coach_availability where coach_id=$1 order by weekday, start_hour;`
+
+	availabilityDB := []CoachAvailabilityPostgreSQL{}
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).SelectContext(ctx, &availabilityDB, query, coachID)
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	availabilityModels := []models.CoachAvailability{}
+	for i := range availabilityDB {
+		availability := models.CoachAvailability{}
+		err = copier.Copy(&availability, &availabilityDB[i])
+		if err != nil {
+			return nil, err
+		}
+
+		availabilityModels = append(availabilityModels, availability)
+	}
+
+	return availabilityModels, nil
+}
+
+func (c *CoachSchedulePostgreSQLRepository) DeleteAvailability(ctx context.Context, id uint64) error {
+	query := `delete from coach_availability where coach_availability_id=$1 returning coach_availability_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRowxContext(ctx, query, id).Scan(&id)
+	if err == sql.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CoachSchedulePostgreSQLRepository) CreateTimeOff(ctx context.Context, timeOff *models.CoachTimeOff) error {
+	query := `insert into coach_time_off(coach_id, start_date_time, end_date_time, reason) values($1, $2, $3, $4) returning coach_time_off_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).
+		QueryRowxContext(ctx, query, timeOff.CoachID, timeOff.Start.UTC(), timeOff.End.UTC(), timeOff.Reason).
+		Scan(&timeOff.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CoachSchedulePostgreSQLRepository) GetTimeOffByCoach(ctx context.Context, coachID uint64) ([]models.CoachTimeOff, error) {
+	query := `select * from coach_time_off where coach_id=$1 order by start_date_time;`
+
+	timeOffDB := []CoachTimeOffPostgreSQL{}
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).SelectContext(ctx, &timeOffDB, query, coachID)
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	timeOffModels := []models.CoachTimeOff{}
+	for i := range timeOffDB {
+		timeOff := models.CoachTimeOff{}
+		err = copier.Copy(&timeOff, &timeOffDB[i])
+		if err != nil {
+			return nil, err
+		}
+
+		timeOffModels = append(timeOffModels, timeOff)
+	}
+
+	return timeOffModels, nil
+}
+
+func (c *CoachSchedulePostgreSQLRepository) DeleteTimeOff(ctx context.Context, id uint64) error {
+	query := `delete from coach_time_off where coach_time_off_id=$1 returning coach_time_off_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRowxContext(ctx, query, id).Scan(&id)
+	if err == sql.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CoachSchedulePostgreSQLRepository) IsAvailable(ctx context.Context, coachID uint64, dateTime time.Time) (bool, error) {
+	var available bool
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).GetContext(ctx, &available, coachAvailableQuery, coachID, dateTime.UTC())
+	if err != nil {
+		return false, err
+	}
+
+	return available, nil
+}
+
+// GetFreeSlots returns the hourly start times on the calendar day of date of
+// the one hour trainings ending by the last training time of the studio when
+// the coach is available and has no training yet.
+func (c *CoachSchedulePostgreSQLRepository) GetFreeSlots(ctx context.Context, coachID uint64, date time.Time) ([]time.Time, error) {
+	slots := []time.Time{}
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).
+		SelectContext(ctx, &slots, coachFreeSlotsQuery, coachID, date, c.firstTrainingTime, c.lastTrainingTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return slots, nil
+}
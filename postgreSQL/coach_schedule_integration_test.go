@@ -0,0 +1,99 @@
+package postgreSQL
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nkarakotova/lim-core/models"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+// CoachScheduleIntegrationSuite runs the sessions in Moscow, three hours
+// ahead of UTC all year.
+type CoachScheduleIntegrationSuite struct {
+	suite.Suite
+	schema     *TestSchema
+	repository extRepositories.CoachScheduleRepository
+	slots      extRepositories.SlotRepository
+	coach      *models.Coach
+	ctx        context.Context
+}
+
+func (s *CoachScheduleIntegrationSuite) BeforeEach(t provider.T) {
+	s.ctx = context.Background()
+
+	var err error
+	s.schema, err = testDatabase.NewSchemaInTimeZone(s.ctx, "Europe/Moscow")
+	if err != nil {
+		t.Fatalf("error creating schema: %v", err)
+	}
+
+	dbx := sqlx.NewDb(s.schema.DB, "pgx")
+	s.repository = NewCoachSchedulePostgreSQLRepository(dbx, 10, 22)
+	s.slots = NewSlotPostgreSQLRepository(dbx, 10, 22)
+
+	s.coach = postgreSQLObjectMother.NewCoach().Build()
+	err = NewCoachPostgreSQLRepository(dbx).Create(s.ctx, s.coach)
+	if err == nil {
+		err = NewHallPostgreSQLRepository(dbx).Create(s.ctx, postgreSQLObjectMother.NewHall().Build())
+	}
+	if err == nil {
+		// Monday from 10:00 to 18:00 in Moscow.
+		err = s.repository.CreateAvailability(s.ctx, &extModels.CoachAvailability{
+			CoachID: s.coach.ID, Weekday: time.Monday, StartHour: 10, EndHour: 18,
+		})
+	}
+	if err != nil {
+		t.Fatalf("error creating fixtures: %v", err)
+	}
+}
+
+func (s *CoachScheduleIntegrationSuite) AfterEach(t provider.T) {
+	s.schema.Close(s.ctx)
+}
+
+func (s *CoachScheduleIntegrationSuite) TestStudioTimeZone(t provider.T) {
+	t.Title("CoachSchedule: The availability and the training hours are taken in the time zone of the studio")
+	t.Tags("CoachSchedule", "Slot", "Integration")
+	t.WithNewStep("IsAvailable", func(sCtx provider.StepCtx) {
+		available, err := s.repository.IsAvailable(s.ctx, s.coach.ID, time.Date(2024, 7, 8, 7, 0, 0, 0, time.UTC))
+		sCtx.Require().NoError(err)
+		sCtx.Assert().True(available, "10:00 in Moscow")
+
+		available, err = s.repository.IsAvailable(s.ctx, s.coach.ID, time.Date(2024, 7, 8, 16, 0, 0, 0, time.UTC))
+		sCtx.Require().NoError(err)
+		sCtx.Assert().False(available, "19:00 in Moscow")
+	})
+	t.WithNewStep("GetFreeSlots", func(sCtx provider.StepCtx) {
+		slots, err := s.repository.GetFreeSlots(s.ctx, s.coach.ID, time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC))
+		sCtx.Require().NoError(err)
+		sCtx.Require().Len(slots, 8)
+		sCtx.Assert().True(time.Date(2024, 7, 8, 7, 0, 0, 0, time.UTC).Equal(slots[0]), "first slot %v", slots[0])
+		sCtx.Assert().True(time.Date(2024, 7, 8, 14, 0, 0, 0, time.UTC).Equal(slots[7]), "last slot %v", slots[7])
+	})
+	t.WithNewStep("GetFree", func(sCtx provider.StepCtx) {
+		slots, err := s.slots.GetFree(s.ctx, extModels.SlotFilter{
+			Start:    time.Date(2024, 7, 7, 21, 0, 0, 0, time.UTC),
+			End:      time.Date(2024, 7, 8, 21, 0, 0, 0, time.UTC),
+			Duration: time.Hour,
+		})
+		sCtx.Require().NoError(err)
+		sCtx.Require().Len(slots, 12)
+		sCtx.Assert().True(time.Date(2024, 7, 8, 7, 0, 0, 0, time.UTC).Equal(slots[0].DateTime), "first slot %v", slots[0].DateTime)
+		sCtx.Assert().True(time.Date(2024, 7, 8, 18, 0, 0, 0, time.UTC).Equal(slots[11].DateTime), "last slot %v", slots[11].DateTime)
+	})
+}
+
+func TestCoachScheduleIntegrationSuiteRunner(t *testing.T) {
+	skipWithoutDatabase(t)
+
+	suite.RunSuite(t, new(CoachScheduleIntegrationSuite))
+}
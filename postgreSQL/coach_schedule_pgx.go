@@ -0,0 +1,169 @@
+package postgreSQL
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+
+	"github.com/nkarakotova/lim-repo/models"
+	"github.com/nkarakotova/lim-repo/repositories"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	coachAvailabilityColumns = `coach_availability_id, coach_id, weekday, start_hour, end_hour`
+	coachTimeOffColumns      = `coach_time_off_id, coach_id, start_date_time, end_date_time, reason`
+)
+
+type CoachSchedulePgxRepository struct {
+	db                trmpgx.Tr
+	txResolver        *trmpgx.CtxGetter
+	firstTrainingTime int
+	lastTrainingTime  int
+}
+
+// NewCoachSchedulePgxRepository returns the repository offering the free
+// slots from the firstTrainingTime hour until the lastTrainingTime hour, as
+// config.Config sets them.
+func NewCoachSchedulePgxRepository(db trmpgx.Tr, firstTrainingTime, lastTrainingTime int) repositories.CoachScheduleRepository {
+	return &CoachSchedulePgxRepository{
+		db:                db,
+		txResolver:        trmpgx.DefaultCtxGetter,
+		firstTrainingTime: firstTrainingTime,
+		lastTrainingTime:  lastTrainingTime,
+	}
+}
+
+func (c *CoachSchedulePgxRepository) CreateAvailability(ctx context.Context, availability *models.CoachAvailability) error {
+	query := `insert into coach_availability(coach_id, weekday, start_hour, end_hour) values($1, $2, $3, $4) returning coach_availability_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).
+		QueryRow(ctx, query, availability.CoachID, int(availability.Weekday), availability.StartHour, availability.EndHour).
+		Scan(&availability.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CoachSchedulePgxRepository) GetAvailabilityByCoach(ctx context.Context, coachID uint64) ([]models.CoachAvailability, error) {
+	query := `select ` + coachAvailabilityColumns + ` from coach_availability where coach_id=$1 order by weekday, start_hour;`
+
+	rows, err := c.txResolver.DefaultTrOrDB(ctx, c.db).Query(ctx, query, coachID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	availabilityModels := []models.CoachAvailability{}
+	for rows.Next() {
+		availability := models.CoachAvailability{}
+		var weekday int
+		err = rows.Scan(&availability.ID, &availability.CoachID, &weekday, &availability.StartHour, &availability.EndHour)
+		if err != nil {
+			return nil, err
+		}
+		availability.Weekday = time.Weekday(weekday)
+
+		availabilityModels = append(availabilityModels, availability)
+	}
+
+	return availabilityModels, rows.Err()
+}
+
+func (c *CoachSchedulePgxRepository) DeleteAvailability(ctx context.Context, id uint64) error {
+	query := `delete from coach_availability where coach_availability_id=$1 returning coach_availability_id;`
+
+	return c.delete(ctx, query, id)
+}
+
+func (c *CoachSchedulePgxRepository) CreateTimeOff(ctx context.Context, timeOff *models.CoachTimeOff) error {
+	query := `insert into coach_time_off(coach_id, start_date_time, end_date_time, reason) values($1, $2, $3, $4) returning coach_time_off_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).
+		QueryRow(ctx, query, timeOff.CoachID, timeOff.Start.UTC(), timeOff.End.UTC(), timeOff.Reason).
+		Scan(&timeOff.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CoachSchedulePgxRepository) GetTimeOffByCoach(ctx context.Context, coachID uint64) ([]models.CoachTimeOff, error) {
+	query := `select ` + coachTimeOffColumns + ` from coach_time_off where coach_id=$1 order by start_date_time;`
+
+	rows, err := c.txResolver.DefaultTrOrDB(ctx, c.db).Query(ctx, query, coachID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	timeOffModels := []models.CoachTimeOff{}
+	for rows.Next() {
+		timeOff := models.CoachTimeOff{}
+		err = rows.Scan(&timeOff.ID, &timeOff.CoachID, &timeOff.Start, &timeOff.End, &timeOff.Reason)
+		if err != nil {
+			return nil, err
+		}
+
+		timeOffModels = append(timeOffModels, timeOff)
+	}
+
+	return timeOffModels, rows.Err()
+}
+
+func (c *CoachSchedulePgxRepository) DeleteTimeOff(ctx context.Context, id uint64) error {
+	query := `delete from coach_time_off where coach_time_off_id=$1 returning coach_time_off_id;`
+
+	return c.delete(ctx, query, id)
+}
+
+func (c *CoachSchedulePgxRepository) delete(ctx context.Context, query string, id uint64) error {
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRow(ctx, query, id).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CoachSchedulePgxRepository) IsAvailable(ctx context.Context, coachID uint64, dateTime time.Time) (bool, error) {
+	var available bool
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRow(ctx, coachAvailableQuery, coachID, dateTime.UTC()).Scan(&available)
+	if err != nil {
+		return false, err
+	}
+
+	return available, nil
+}
+
+// GetFreeSlots returns the slots of CoachSchedulePostgreSQLRepository.GetFreeSlots.
+func (c *CoachSchedulePgxRepository) GetFreeSlots(ctx context.Context, coachID uint64, date time.Time) ([]time.Time, error) {
+	rows, err := c.txResolver.DefaultTrOrDB(ctx, c.db).
+		Query(ctx, coachFreeSlotsQuery, coachID, date, c.firstTrainingTime, c.lastTrainingTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slots := []time.Time{}
+	for rows.Next() {
+		var slot time.Time
+		err = rows.Scan(&slot)
+		if err != nil {
+			return nil, err
+		}
+
+		slots = append(slots, slot)
+	}
+
+	return slots, rows.Err()
+}
@@ -0,0 +1,197 @@
+package postgreSQL
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-repo/models"
+	"github.com/nkarakotova/lim-repo/repositories"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type CoachScheduleSuite struct {
+	suite.Suite
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+	repository repositories.CoachScheduleRepository
+	ctx        context.Context
+}
+
+func (s *CoachScheduleSuite) BeforeEach(t provider.T) {
+	var err error
+	s.db, s.mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	dbx := sqlx.NewDb(s.db, "pgx")
+	s.repository = NewCoachSchedulePostgreSQLRepository(dbx, 10, 22)
+	s.ctx = context.Background()
+}
+
+func (s *CoachScheduleSuite) AfterEach(t provider.T) {
+	s.db.Close()
+}
+
+func (s *CoachScheduleSuite) TestCoachScheduleMockCreateAvailabilitySuccess(t provider.T) {
+	t.Title("CoachScheduleMockCreateAvailability: Success")
+	t.Tags("CoachSchedule")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`insert into coach_availability(coach_id, weekday, start_hour, end_hour) values($1, $2, $3, $4) returning coach_availability_id;`).
+			WithArgs(1, 1, 10, 18).
+			WillReturnRows(sqlmock.NewRows([]string{"coach_availability_id"}).AddRow(1))
+
+		availability := &models.CoachAvailability{CoachID: 1, Weekday: time.Monday, StartHour: 10, EndHour: 18}
+		err := s.repository.CreateAvailability(s.ctx, availability)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(uint64(1), availability.ID)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CoachScheduleSuite) TestCoachScheduleMockGetAvailabilityByCoachSuccess(t provider.T) {
+	t.Title("CoachScheduleMockGetAvailabilityByCoach: Success")
+	t.Tags("CoachSchedule")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`select * from coach_availability where coach_id=$1 order by weekday, start_hour;`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"coach_availability_id", "coach_id", "weekday", "start_hour", "end_hour"}).
+				AddRow(1, 1, 1, 10, 18))
+
+		availability, err := s.repository.GetAvailabilityByCoach(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.CoachAvailability{{ID: 1, CoachID: 1, Weekday: time.Monday, StartHour: 10, EndHour: 18}}, availability)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CoachScheduleSuite) TestCoachScheduleMockDeleteAvailabilityFailure(t provider.T) {
+	t.Title("CoachScheduleMockDeleteAvailability: Failure")
+	t.Tags("CoachSchedule")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`delete from coach_availability where coach_availability_id=$1 returning coach_availability_id;`).
+			WithArgs(1).WillReturnError(sql.ErrNoRows)
+
+		err := s.repository.DeleteAvailability(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CoachScheduleSuite) TestCoachScheduleMockCreateTimeOffSuccess(t provider.T) {
+	t.Title("CoachScheduleMockCreateTimeOff: Success")
+	t.Tags("CoachSchedule")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		start := time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC)
+		s.mock.ExpectQuery(`insert into coach_time_off(coach_id, start_date_time, end_date_time, reason) values($1, $2, $3, $4) returning coach_time_off_id;`).
+			WithArgs(1, start, end, "vacation").
+			WillReturnRows(sqlmock.NewRows([]string{"coach_time_off_id"}).AddRow(1))
+
+		timeOff := &models.CoachTimeOff{CoachID: 1, Start: start, End: end, Reason: "vacation"}
+		err := s.repository.CreateTimeOff(s.ctx, timeOff)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(uint64(1), timeOff.ID)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CoachScheduleSuite) TestCoachScheduleMockGetTimeOffByCoachSuccess(t provider.T) {
+	t.Title("CoachScheduleMockGetTimeOffByCoach: Success")
+	t.Tags("CoachSchedule")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		start := time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC)
+		s.mock.ExpectQuery(`select * from coach_time_off where coach_id=$1 order by start_date_time;`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"coach_time_off_id", "coach_id", "start_date_time", "end_date_time", "reason"}).
+				AddRow(1, 1, start, end, "sick day"))
+
+		timeOff, err := s.repository.GetTimeOffByCoach(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.CoachTimeOff{{ID: 1, CoachID: 1, Start: start, End: end, Reason: "sick day"}}, timeOff)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CoachScheduleSuite) TestCoachScheduleMockIsAvailableSuccess(t provider.T) {
+	t.Title("CoachScheduleMockIsAvailable: Success")
+	t.Tags("CoachSchedule")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(coachAvailableQuery).
+			WithArgs(1, time.Date(2024, 7, 8, 12, 0, 0, 0, time.UTC)).
+			WillReturnRows(sqlmock.NewRows([]string{"available"}).AddRow(false))
+
+		available, err := s.repository.IsAvailable(s.ctx, 1, time.Date(2024, 7, 8, 12, 0, 0, 0, time.UTC))
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().False(available)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *CoachScheduleSuite) TestCoachScheduleMockGetFreeSlotsSuccess(t provider.T) {
+	t.Title("CoachScheduleMockGetFreeSlots: Success")
+	t.Tags("CoachSchedule")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		date := time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)
+		s.mock.ExpectQuery(`select s from generate_series($2::date + make_interval(hours => $3), $2::date + make_interval(hours => $4 - 1), interval '1 hour') g(l)
+cross join lateral (select g.l::timestamptz at time zone 'UTC') u(s)
+where (not exists (select 1 from coach_availability where coach_id=$1)
+or exists (select 1 from coach_availability a where a.coach_id=$1 and a.weekday=extract(dow from l)
+and l >= l::date + make_interval(hours => a.start_hour) and l + interval '1 hour' <= l::date + make_interval(hours => a.end_hour)))
+and not exists (select 1 from coach_time_off o where o.coach_id=$1 and o.start_date_time < s + interval '1 hour' and o.end_date_time > s)
+and not exists (select 1 from trainings t where t.coach_id=$1 and t.date_time < s + interval '1 hour' and t.date_time + interval '1 hour' > s)
+order by s;`).
+			WithArgs(1, date, 10, 22).
+			WillReturnRows(sqlmock.NewRows([]string{"s"}).
+				AddRow(time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)).
+				AddRow(time.Date(2024, 7, 8, 11, 0, 0, 0, time.UTC)))
+
+		slots, err := s.repository.GetFreeSlots(s.ctx, 1, date)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]time.Time{
+			time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC),
+			time.Date(2024, 7, 8, 11, 0, 0, 0, time.UTC),
+		}, slots)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestCoachScheduleSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(CoachScheduleSuite))
+}
@@ -0,0 +1,90 @@
+package postgreSQL
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/nkarakotova/lim-repo/breaker"
+	"github.com/nkarakotova/lim-repo/cache"
+	"github.com/nkarakotova/lim-repo/metrics/prometheusMetrics"
+	"github.com/nkarakotova/lim-repo/repotest"
+	"github.com/nkarakotova/lim-repo/retry"
+
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+func TestConformanceSuiteRunner(t *testing.T) {
+	skipWithoutDatabase(t)
+
+	suite.RunSuite(t, &repotest.Suite{New: func() (repotest.Repositories, func(), error) {
+		schema, err := testDatabase.NewSchema(context.Background())
+		if err != nil {
+			return repotest.Repositories{}, nil, err
+		}
+		dbx := sqlx.NewDb(schema.DB, "pgx")
+
+		return repotest.Repositories{
+			Client:   NewClientPostgreSQLRepository(dbx),
+			Coach:    NewCoachPostgreSQLRepository(dbx),
+			Hall:     NewHallPostgreSQLRepository(dbx),
+			Training: NewTrainingPostgreSQLRepository(dbx),
+		}, func() { schema.Close(context.Background()) }, nil
+	}})
+}
+
+func TestPgxConformanceSuiteRunner(t *testing.T) {
+	skipWithoutDatabase(t)
+
+	suite.RunSuite(t, &repotest.Suite{New: func() (repotest.Repositories, func(), error) {
+		schema, err := testDatabase.NewSchema(context.Background())
+		if err != nil {
+			return repotest.Repositories{}, nil, err
+		}
+
+		return repotest.Repositories{
+			Client:   NewClientPgxRepository(schema.Pool),
+			Coach:    NewCoachPgxRepository(schema.Pool),
+			Hall:     NewHallPgxRepository(schema.Pool),
+			Training: NewTrainingPgxRepository(schema.Pool),
+		}, func() { schema.Close(context.Background()) }, nil
+	}})
+}
+
+// TestDecoratedConformanceSuiteRunner runs the suite through every decorator
+// the Create functions wrap the repositories in.
+func TestDecoratedConformanceSuiteRunner(t *testing.T) {
+	skipWithoutDatabase(t)
+
+	suite.RunSuite(t, &repotest.Suite{New: func() (repotest.Repositories, func(), error) {
+		schema, err := testDatabase.NewSchema(context.Background())
+		if err != nil {
+			return repotest.Repositories{}, nil, err
+		}
+		m, err := prometheusMetrics.New(prometheus.NewRegistry())
+		if err != nil {
+			schema.Close(context.Background())
+			return repotest.Repositories{}, nil, err
+		}
+
+		fields := &PostgresRepositoryFields{
+			DB:      schema.DB,
+			Metrics: m,
+			Retry:   retry.NewPolicy(3),
+			Breaker: breaker.New("postgres", breaker.DefaultSettings, log.New(io.Discard)),
+			Caches:  cache.New(cache.DefaultSettings),
+		}
+		fields.Config.Postgres.Trace = true
+
+		return repotest.Repositories{
+			Client:   CreateClientPostgreSQLRepository(fields),
+			Coach:    CreateCoachPostgreSQLRepository(fields),
+			Hall:     CreateHallPostgreSQLRepository(fields),
+			Training: CreateTrainingPostgreSQLRepository(fields),
+		}, func() { schema.Close(context.Background()) }, nil
+	}})
+}
@@ -1,19 +1,57 @@
 package postgreSQL
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/nkarakotova/lim-repo/config"
 	"github.com/nkarakotova/lim-repo/flags"
 
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
 	"github.com/charmbracelet/log"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/jmoiron/sqlx"
+	"github.com/nkarakotova/lim-core/managers"
+	transactionManager "github.com/nkarakotova/lim-core/managers/implementation"
 	"github.com/nkarakotova/lim-core/repositories"
+	"github.com/nkarakotova/lim-core/services"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+
+	"github.com/nkarakotova/lim-repo/breaker"
+	"github.com/nkarakotova/lim-repo/cache"
+	"github.com/nkarakotova/lim-repo/events"
+	"github.com/nkarakotova/lim-repo/metrics"
+	"github.com/nkarakotova/lim-repo/replica"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+	"github.com/nkarakotova/lim-repo/retry"
+	"github.com/nkarakotova/lim-repo/timeout"
+	"github.com/nkarakotova/lim-repo/tracing"
 )
 
+// PostgresRepositoryFields always carries DB. Pool is set only with the
+// flags.DriverPgxPool driver; then every repository and the transaction
+// manager run on it, so that a transaction spans all of them, and DB is
+// left to the migrations and the health checks. With Metrics the client,
+// coach, hall and training repositories record their calls in it, see
+// InstrumentPostgresRepositoryFields. With Retry they and the transaction
+// manager retry the calls failed on a transient error, with Breaker they
+// fail fast while the database is down. Their calls without a deadline are
+// bounded by Config.Postgres.Timeouts. With Router the coach, hall and
+// training repositories read the schedule from the Replicas. With Caches
+// they cache the coach, hall and schedule lookups until the Caches are
+// closed, filled from the primary when the Caches use the Router.
 type PostgresRepositoryFields struct {
-	DB     *sql.DB
-	Config config.Config
+	DB       *sql.DB
+	Pool     *pgxpool.Pool
+	Replicas []*sql.DB
+	Router   *replica.Router
+	Config   config.Config
+	Metrics  metrics.Metrics
+	Retry    *retry.Policy
+	Breaker  *breaker.Breaker
+	Caches   *cache.Caches
 }
 
 func CreatePostgresRepositoryFields(Postgres flags.PostgresFlags, logger *log.Logger) (*PostgresRepositoryFields, error) {
@@ -28,31 +66,310 @@ func CreatePostgresRepositoryFields(Postgres flags.PostgresFlags, logger *log.Lo
 		return nil, err
 	}
 
+	if Postgres.Driver == flags.DriverPgxPool {
+		fields.Pool, err = fields.Config.Postgres.InitPool(logger)
+		if err != nil {
+			logger.Error("POSTGRES! Error create pgxpool")
+			fields.DB.Close()
+			return nil, err
+		}
+	}
+
+	if len(Postgres.Replicas) > 0 {
+		fields.Replicas, err = fields.Config.Postgres.InitReplicas(logger)
+		if err != nil {
+			logger.Error("POSTGRES! Error create replicas")
+			fields.DB.Close()
+			if fields.Pool != nil {
+				fields.Pool.Close()
+			}
+			return nil, err
+		}
+		fields.Router = replica.NewRouter(fields.Replicas, replica.Settings{MaxLag: Postgres.ReplicaMaxLag}, logger)
+	}
+
+	if Postgres.MaxRetries > 0 {
+		fields.Retry = retry.NewPolicy(Postgres.MaxRetries + 1)
+	}
+	if Postgres.BreakerFailureRatio > 0 {
+		fields.Breaker = breaker.New("postgres", breaker.Settings{
+			FailureRatio: Postgres.BreakerFailureRatio,
+			OpenTimeout:  Postgres.BreakerOpenTimeout,
+			Probe:        fields.DB.PingContext,
+		}, logger)
+	}
+
+	if Postgres.CacheSize > 0 {
+		fields.Caches = cache.New(cache.Settings{Size: Postgres.CacheSize, TTL: Postgres.CacheTTL})
+		if fields.Router != nil {
+			fields.Caches.UseRouter(fields.Router)
+		}
+		fields.Caches.Listen(func(ctx context.Context) (cache.Conn, error) {
+			return fields.Config.Postgres.Connect(ctx)
+		}, logger)
+	}
+
 	logger.Info("POSTGRES! Successfully create postgres repository fields")
 
 	return fields, nil
 }
 
+// SubscribeEvents subscribes to the changes of the trainings and bookings
+// on a connection of its own, see the events package.
+func SubscribeEvents(fields *PostgresRepositoryFields, settings events.Settings, logger *log.Logger) *events.Subscription {
+	return events.Subscribe(func(ctx context.Context) (events.Conn, error) {
+		return fields.Config.Postgres.Connect(ctx)
+	}, settings, logger)
+}
+
+// InstrumentPostgresRepositoryFields makes the repositories created from
+// fields afterwards record their calls in m and reports the stats of DB as
+// the "postgres" pool and of Pool as the "pgxpool" one, the state of
+// Breaker and the stats of Caches.
+func InstrumentPostgresRepositoryFields(fields *PostgresRepositoryFields, m metrics.Metrics) error {
+	err := m.RegisterPool("postgres", fields.DB.Stats)
+	if err != nil {
+		return err
+	}
+
+	if fields.Pool != nil {
+		err = m.RegisterPool("pgxpool", func() sql.DBStats {
+			return pgxPoolStats(fields.Pool.Stat())
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if fields.Breaker != nil {
+		fields.Breaker.ReportTo(m)
+	}
+	if fields.Caches != nil {
+		err = fields.Caches.ReportTo(m)
+		if err != nil {
+			return err
+		}
+	}
+	fields.Metrics = m
+
+	return nil
+}
+
+// pgxPoolStats converts the stats of a pgxpool.Pool. The pool does not
+// count the time spent waiting for a connection, so WaitDuration stays zero.
+func pgxPoolStats(stat *pgxpool.Stat) sql.DBStats {
+	return sql.DBStats{
+		MaxOpenConnections: int(stat.MaxConns()),
+		OpenConnections:    int(stat.TotalConns()),
+		InUse:              int(stat.AcquiredConns()),
+		Idle:               int(stat.IdleConns()),
+		WaitCount:          stat.EmptyAcquireCount(),
+	}
+}
+
 func CreateClientPostgreSQLRepository(fields *PostgresRepositoryFields) repositories.ClientRepository {
-	dbx := sqlx.NewDb(fields.DB, "pgx")
+	var repository repositories.ClientRepository
+	if fields.Pool != nil {
+		repository = NewClientPgxRepository(fields.Pool)
+	} else {
+		dbx := sqlx.NewDb(fields.DB, "pgx")
+		repository = NewClientPostgreSQLRepository(dbx)
+	}
+
+	if fields.Router != nil {
+		repository = replica.NewClientRepository(repository, fields.Router)
+	}
+	if fields.Retry != nil {
+		repository = retry.NewClientRepository(repository, fields.Retry)
+	}
+	if fields.Breaker != nil {
+		repository = breaker.NewClientRepository(repository, fields.Breaker)
+	}
+	repository = timeout.NewClientRepository(repository, fields.Config.Postgres.Timeouts.Timeouts())
+	if fields.Config.Postgres.Trace {
+		repository = tracing.NewClientRepository(repository, tracing.WithDBSystem(semconv.DBSystemPostgreSQL))
+	}
+	if fields.Metrics != nil {
+		repository = metrics.NewClientRepository(repository, fields.Metrics)
+	}
 
-	return NewClientPostgreSQLRepository(dbx)
+	return repository
 }
 
 func CreateCoachPostgreSQLRepository(fields *PostgresRepositoryFields) repositories.CoachRepository {
+	var repository repositories.CoachRepository
+	if fields.Pool != nil {
+		repository = NewCoachPgxRepository(fields.Pool)
+	} else {
+		dbx := sqlx.NewDb(fields.DB, "pgx")
+		repository = NewCoachPostgreSQLRepository(dbx)
+	}
+
+	if fields.Router != nil {
+		var replicas []repositories.CoachRepository
+		for _, db := range fields.Replicas {
+			replicas = append(replicas, NewCoachPostgreSQLRepository(sqlx.NewDb(db, "pgx")))
+		}
+		repository = replica.NewCoachRepository(repository, replicas, fields.Router)
+	}
+	if fields.Retry != nil {
+		repository = retry.NewCoachRepository(repository, fields.Retry)
+	}
+	if fields.Breaker != nil {
+		repository = breaker.NewCoachRepository(repository, fields.Breaker)
+	}
+	repository = timeout.NewCoachRepository(repository, fields.Config.Postgres.Timeouts.Timeouts())
+	if fields.Caches != nil {
+		repository = cache.NewCoachRepository(repository, fields.Caches)
+	}
+	if fields.Config.Postgres.Trace {
+		repository = tracing.NewCoachRepository(repository, tracing.WithDBSystem(semconv.DBSystemPostgreSQL))
+	}
+	if fields.Metrics != nil {
+		repository = metrics.NewCoachRepository(repository, fields.Metrics)
+	}
+
+	return repository
+}
+
+func CreateHallPostgreSQLRepository(fields *PostgresRepositoryFields) extRepositories.HallRepository {
+	var repository extRepositories.HallRepository
+	if fields.Pool != nil {
+		repository = NewHallPgxRepository(fields.Pool)
+	} else {
+		dbx := sqlx.NewDb(fields.DB, "pgx")
+		repository = NewHallPostgreSQLRepository(dbx)
+	}
+
+	if fields.Router != nil {
+		var replicas []extRepositories.HallRepository
+		for _, db := range fields.Replicas {
+			replicas = append(replicas, NewHallPostgreSQLRepository(sqlx.NewDb(db, "pgx")))
+		}
+		repository = replica.NewExtHallRepository(repository, replicas, fields.Router)
+	}
+	if fields.Retry != nil {
+		repository = retry.NewExtHallRepository(repository, fields.Retry)
+	}
+	if fields.Breaker != nil {
+		repository = breaker.NewExtHallRepository(repository, fields.Breaker)
+	}
+	repository = timeout.NewExtHallRepository(repository, fields.Config.Postgres.Timeouts.Timeouts())
+	if fields.Caches != nil {
+		repository = cache.NewExtHallRepository(repository, fields.Caches)
+	}
+	if fields.Config.Postgres.Trace {
+		repository = tracing.NewExtHallRepository(repository, tracing.WithDBSystem(semconv.DBSystemPostgreSQL))
+	}
+	if fields.Metrics != nil {
+		repository = metrics.NewExtHallRepository(repository, fields.Metrics)
+	}
+
+	return repository
+}
+
+func CreateTrainingPostgreSQLRepository(fields *PostgresRepositoryFields) extRepositories.TrainingRepository {
+	var repository extRepositories.TrainingRepository
+	if fields.Pool != nil {
+		repository = NewTrainingPgxRepository(fields.Pool)
+	} else {
+		dbx := sqlx.NewDb(fields.DB, "pgx")
+		repository = NewTrainingPostgreSQLRepository(dbx)
+	}
+
+	if fields.Router != nil {
+		var replicas []extRepositories.TrainingRepository
+		for _, db := range fields.Replicas {
+			replicas = append(replicas, NewTrainingPostgreSQLRepository(sqlx.NewDb(db, "pgx")))
+		}
+		repository = replica.NewExtTrainingRepository(repository, replicas, fields.Router)
+	}
+	if fields.Retry != nil {
+		repository = retry.NewExtTrainingRepository(repository, fields.Retry)
+	}
+	if fields.Breaker != nil {
+		repository = breaker.NewExtTrainingRepository(repository, fields.Breaker)
+	}
+	repository = timeout.NewExtTrainingRepository(repository, fields.Config.Postgres.Timeouts.Timeouts())
+	if fields.Caches != nil {
+		repository = cache.NewExtTrainingRepository(repository, fields.Caches)
+	}
+	if fields.Config.Postgres.Trace {
+		repository = tracing.NewExtTrainingRepository(repository, tracing.WithDBSystem(semconv.DBSystemPostgreSQL))
+	}
+	if fields.Metrics != nil {
+		repository = metrics.NewExtTrainingRepository(repository, fields.Metrics)
+	}
+
+	return repository
+}
+
+func CreateCancellationPostgreSQLRepository(fields *PostgresRepositoryFields) extRepositories.CancellationRepository {
+	if fields.Pool != nil {
+		return NewCancellationPgxRepository(fields.Pool)
+	}
 	dbx := sqlx.NewDb(fields.DB, "pgx")
 
-	return NewCoachPostgreSQLRepository(dbx)
+	return NewCancellationPostgreSQLRepository(dbx)
 }
 
-func CreateHallPostgreSQLRepository(fields *PostgresRepositoryFields) repositories.HallRepository {
+// trainingHours returns the training hours of config, those of the services
+// when it sets no range.
+func trainingHours(config config.Config) (int, int) {
+	if config.LastTrainingTime <= config.FirstTrainingTime {
+		return services.FirstTrainingTime, services.LastTrainingTime
+	}
+
+	return config.FirstTrainingTime, config.LastTrainingTime
+}
+
+func CreateCoachSchedulePostgreSQLRepository(fields *PostgresRepositoryFields) extRepositories.CoachScheduleRepository {
+	first, last := trainingHours(fields.Config)
+	if fields.Pool != nil {
+		return NewCoachSchedulePgxRepository(fields.Pool, first, last)
+	}
 	dbx := sqlx.NewDb(fields.DB, "pgx")
 
-	return NewHallPostgreSQLRepository(dbx)
+	return NewCoachSchedulePostgreSQLRepository(dbx, first, last)
 }
 
-func CreateTrainingPostgreSQLRepository(fields *PostgresRepositoryFields) repositories.TrainingRepository {
+func CreateHallClosurePostgreSQLRepository(fields *PostgresRepositoryFields) extRepositories.HallClosureRepository {
+	if fields.Pool != nil {
+		return NewHallClosurePgxRepository(fields.Pool)
+	}
 	dbx := sqlx.NewDb(fields.DB, "pgx")
 
-	return NewTrainingPostgreSQLRepository(dbx)
+	return NewHallClosurePostgreSQLRepository(dbx)
+}
+
+func CreateSlotPostgreSQLRepository(fields *PostgresRepositoryFields) extRepositories.SlotRepository {
+	first, last := trainingHours(fields.Config)
+	if fields.Pool != nil {
+		return NewSlotPgxRepository(fields.Pool, first, last)
+	}
+	dbx := sqlx.NewDb(fields.DB, "pgx")
+
+	return NewSlotPostgreSQLRepository(dbx, first, last)
+}
+
+// CreateTransactionManager returns the transaction manager matching the
+// driver of the repositories. A transaction of one driver is not seen by the
+// repositories of the other.
+func CreateTransactionManager(fields *PostgresRepositoryFields) managers.TransactionManager {
+	var transactions managers.TransactionManager
+	if fields.Pool != nil {
+		transactions = transactionManager.NewTransactionManagerImplementation(manager.Must(trmpgx.NewDefaultFactory(fields.Pool)))
+	} else {
+		dbx := sqlx.NewDb(fields.DB, "pgx")
+		transactions = transactionManager.NewTransactionManagerImplementation(manager.Must(trmsqlx.NewDefaultFactory(dbx)))
+	}
+
+	if fields.Retry != nil {
+		transactions = retry.NewTransactionManager(transactions, fields.Retry)
+	}
+	if fields.Breaker != nil {
+		transactions = breaker.NewTransactionManager(transactions, fields.Breaker)
+	}
+
+	return transactions
 }
@@ -0,0 +1,164 @@
+package postgreSQL
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/services"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/nkarakotova/lim-repo/metrics/prometheusMetrics"
+	"github.com/nkarakotova/lim-repo/models"
+	"github.com/nkarakotova/lim-repo/timeout"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type InstrumentSuite struct {
+	suite.Suite
+	db       *sql.DB
+	mock     sqlmock.Sqlmock
+	registry *prometheus.Registry
+	fields   *PostgresRepositoryFields
+	ctx      context.Context
+}
+
+func (s *InstrumentSuite) BeforeEach(t provider.T) {
+	var err error
+	s.db, s.mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	s.registry = prometheus.NewRegistry()
+	s.fields = &PostgresRepositoryFields{DB: s.db}
+	s.ctx = context.Background()
+}
+
+func (s *InstrumentSuite) AfterEach(t provider.T) {
+	s.db.Close()
+}
+
+// value returns the value of the counter or gauge name with the label.
+func (s *InstrumentSuite) value(sCtx provider.StepCtx, name, label, labelValue string) (float64, bool) {
+	families, err := s.registry.Gather()
+	sCtx.Require().NoError(err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, pair := range metric.GetLabel() {
+				if pair.GetName() == label && pair.GetValue() == labelValue {
+					return metric.GetCounter().GetValue() + metric.GetGauge().GetValue(), true
+				}
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func (s *InstrumentSuite) TestInstrumentedRepository(t provider.T) {
+	t.Title("Instrument: The repositories of the fields report to Prometheus")
+	t.Tags("Metrics")
+	t.WithNewStep("Not found", func(sCtx provider.StepCtx) {
+		m, err := prometheusMetrics.New(s.registry)
+		sCtx.Require().NoError(err)
+		sCtx.Require().NoError(InstrumentPostgresRepositoryFields(s.fields, m))
+
+		s.mock.ExpectQuery(`select * from clients where client_id = $1;`).WithArgs(1).WillReturnError(sql.ErrNoRows)
+
+		_, err = CreateClientPostgreSQLRepository(s.fields).GetByID(s.ctx, 1)
+		sCtx.Require().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		failed, ok := s.value(sCtx, "lim_repository_errors_total", "class", "not_found")
+		sCtx.Assert().True(ok)
+		sCtx.Assert().Equal(1.0, failed)
+		_, ok = s.value(sCtx, "lim_db_connections_open", "pool", "postgres")
+		sCtx.Assert().True(ok)
+		_, ok = s.value(sCtx, "lim_db_connections_open", "pool", "pgxpool")
+		sCtx.Assert().False(ok)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *InstrumentSuite) TestUninstrumentedRepository(t provider.T) {
+	t.Title("Instrument: Without metrics the repositories are not wrapped")
+	t.Tags("Metrics")
+	t.WithNewStep("Plain", func(sCtx provider.StepCtx) {
+		repository := CreateClientPostgreSQLRepository(s.fields)
+
+		// Only the default timeouts apply.
+		sCtx.Assert().IsType(&timeout.ClientRepository{}, repository)
+	})
+}
+
+func (s *InstrumentSuite) TestCoachScheduleTrainingHours(t provider.T) {
+	t.Title("Create: The coach schedule finds the free slots within the training hours")
+	t.Tags("CoachSchedule")
+	t.WithNewStep("Default hours", func(sCtx provider.StepCtx) {
+		date := time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)
+		s.mock.ExpectQuery(coachFreeSlotsQuery).
+			WithArgs(1, date, services.FirstTrainingTime, services.LastTrainingTime).
+			WillReturnRows(sqlmock.NewRows([]string{"s"}))
+
+		_, err := CreateCoachSchedulePostgreSQLRepository(s.fields).GetFreeSlots(s.ctx, 1, date)
+		sCtx.Require().NoError(err)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+	t.WithNewStep("Configured hours", func(sCtx provider.StepCtx) {
+		date := time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)
+		s.fields.Config.FirstTrainingTime, s.fields.Config.LastTrainingTime = 8, 20
+		s.mock.ExpectQuery(coachFreeSlotsQuery).
+			WithArgs(1, date, 8, 20).
+			WillReturnRows(sqlmock.NewRows([]string{"s"}))
+
+		_, err := CreateCoachSchedulePostgreSQLRepository(s.fields).GetFreeSlots(s.ctx, 1, date)
+		sCtx.Require().NoError(err)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *InstrumentSuite) TestSlotTrainingHours(t provider.T) {
+	t.Title("Create: The slot finder looks for the slots within the training hours")
+	t.Tags("Slot")
+	t.WithNewStep("Default hours", func(sCtx provider.StepCtx) {
+		start := time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC)
+		s.mock.ExpectQuery(freeSlotsQuery).
+			WithArgs(start, end, float64(3600), services.FirstTrainingTime, services.LastTrainingTime, 0, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"hall_id", "date_time"}).
+				AddRow(1, time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)))
+
+		slots, err := CreateSlotPostgreSQLRepository(s.fields).GetFree(s.ctx, models.SlotFilter{
+			Start:    start,
+			End:      end,
+			Duration: time.Hour,
+		})
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Len(slots, 1)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestInstrumentSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(InstrumentSuite))
+}
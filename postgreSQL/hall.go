@@ -3,21 +3,31 @@ package postgreSQL
 import (
 	"context"
 	"database/sql"
-
-	"github.com/nkarakotova/lim-core/repositories"
+	"encoding/json"
 
 	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
 
 	"github.com/nkarakotova/lim-core/models"
 
+	extModels "github.com/nkarakotova/lim-repo/models"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+
 	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
 	"github.com/jinzhu/copier"
 	"github.com/jmoiron/sqlx"
 )
 
 type HallPostgreSQL struct {
-	ID       uint64 `db:"hall_id"`
-	Number   uint64 `db:"number"`
+	ID       uint64          `db:"hall_id"`
+	Number   uint64          `db:"number"`
+	Capacity sql.NullInt64   `db:"capacity"`
+	Area     sql.NullFloat64 `db:"area"`
+	Floor    int64           `db:"floor"`
+}
+
+type HallEquipmentPostgreSQL struct {
+	Equipment string `db:"equipment"`
+	Count     uint64 `db:"count"`
 }
 
 type HallPostgreSQLRepository struct {
@@ -25,7 +35,7 @@ type HallPostgreSQLRepository struct {
 	txResolver *trmsqlx.CtxGetter
 }
 
-func NewHallPostgreSQLRepository(db *sqlx.DB) repositories.HallRepository {
+func NewHallPostgreSQLRepository(db *sqlx.DB) extRepositories.HallRepository {
 	return &HallPostgreSQLRepository{db: db, txResolver: trmsqlx.DefaultCtxGetter}
 }
 
@@ -104,3 +114,99 @@ func (h *HallPostgreSQLRepository) GetAll(ctx context.Context) (map[uint64]model
 
 	return hallModels, nil
 }
+
+func (h *HallPostgreSQLRepository) GetDetails(ctx context.Context, id uint64) (*extModels.HallDetails, error) {
+	query := `select * from halls where hall_id=$1;`
+
+	hallDB := &HallPostgreSQL{}
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).GetContext(ctx, hallDB, query, id)
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	query = `select equipment, count from hall_equipment where hall_id=$1;`
+
+	equipmentDB := []HallEquipmentPostgreSQL{}
+	err = h.txResolver.DefaultTrOrDB(ctx, h.db).SelectContext(ctx, &equipmentDB, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &extModels.HallDetails{
+		HallID:    hallDB.ID,
+		Capacity:  uint64(hallDB.Capacity.Int64),
+		Area:      hallDB.Area.Float64,
+		Floor:     hallDB.Floor,
+		Equipment: make(map[extModels.Equipment]uint64),
+	}
+	for _, equipment := range equipmentDB {
+		details.Equipment[extModels.Equipment(equipment.Equipment)] = equipment.Count
+	}
+
+	return details, nil
+}
+
+// UpdateDetails stores capacity, area and floor of the hall. The equipment
+// inventory is managed with SetEquipment.
+func (h *HallPostgreSQLRepository) UpdateDetails(ctx context.Context, details *extModels.HallDetails) error {
+	query := `update halls set capacity=nullif($2::integer, 0), area=nullif($3::numeric, 0), floor=$4 where hall_id=$1 returning hall_id;`
+
+	var id uint64
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).
+		QueryRowxContext(ctx, query, details.HallID, details.Capacity, details.Area, details.Floor).
+		Scan(&id)
+	if err == sql.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *HallPostgreSQLRepository) SetEquipment(ctx context.Context, id uint64, equipment extModels.Equipment, count uint64) error {
+	query := `insert into hall_equipment(hall_id, equipment, count) values($1, $2, $3)
+on conflict (hall_id, equipment) do update set count = excluded.count returning hall_id;`
+
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).QueryRowxContext(ctx, query, id, string(equipment), count).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAllByEquipment returns the halls that have at least the required count
+// of every listed kind of equipment.
+func (h *HallPostgreSQLRepository) GetAllByEquipment(ctx context.Context, required map[extModels.Equipment]uint64) (map[uint64]models.Hall, error) {
+	query := `select h.* from halls h where not exists (select 1 from jsonb_each_text($1::jsonb) r
+where coalesce((select e.count from hall_equipment e where e.hall_id = h.hall_id and e.equipment = r.key), 0) < r.value::integer);`
+
+	requiredJSON, err := json.Marshal(required)
+	if err != nil {
+		return nil, err
+	}
+
+	hallDB := []HallPostgreSQL{}
+	err = h.txResolver.DefaultTrOrDB(ctx, h.db).SelectContext(ctx, &hallDB, query, string(requiredJSON))
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	hallModels := make(map[uint64]models.Hall)
+	for i := range hallDB {
+		hall := models.Hall{}
+		err = copier.Copy(&hall, &hallDB[i])
+		if err != nil {
+			return nil, err
+		}
+
+		hallModels[hall.ID] = hall
+	}
+
+	return hallModels, nil
+}
@@ -0,0 +1,153 @@
+package postgreSQL
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+
+	"github.com/nkarakotova/lim-core/models"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jinzhu/copier"
+	"github.com/jmoiron/sqlx"
+)
+
+// hallClosedQuery reports whether a one hour training starting at $2 overlaps
+// a closure of hall $1.
+const hallClosedQuery = `select exists(select 1 from hall_closures where hall_id=$1 and start_date_time < $2::timestamp + interval '1 hour' and end_date_time > $2::timestamp) as closed;`
+
+type HallClosurePostgreSQL struct {
+	ID     uint64    `db:"hall_closure_id"`
+	HallID uint64    `db:"hall_id"`
+	Start  time.Time `db:"start_date_time"`
+	End    time.Time `db:"end_date_time"`
+	Reason string    `db:"reason"`
+}
+
+type HallClosurePostgreSQLRepository struct {
+	db         *sqlx.DB
+	txResolver *trmsqlx.CtxGetter
+}
+
+func NewHallClosurePostgreSQLRepository(db *sqlx.DB) extRepositories.HallClosureRepository {
+	return &HallClosurePostgreSQLRepository{db: db, txResolver: trmsqlx.DefaultCtxGetter}
+}
+
+func (h *HallClosurePostgreSQLRepository) Create(ctx context.Context, closure *extModels.HallClosure) error {
+	query := `insert into hall_closures(hall_id, start_date_time, end_date_time, reason) values($1, $2, $3, $4) returning hall_closure_id;`
+
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).
+		QueryRowxContext(ctx, query, closure.HallID, closure.Start.UTC(), closure.End.UTC(), closure.Reason).
+		Scan(&closure.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *HallClosurePostgreSQLRepository) GetByID(ctx context.Context, id uint64) (*extModels.HallClosure, error) {
+	query := `select * from hall_closures where hall_closure_id=$1;`
+
+	closureDB := &HallClosurePostgreSQL{}
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).GetContext(ctx, closureDB, query, id)
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	closureModels := &extModels.HallClosure{}
+	err = copier.Copy(closureModels, closureDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return closureModels, nil
+}
+
+func (h *HallClosurePostgreSQLRepository) GetAllByHall(ctx context.Context, hallID uint64) ([]extModels.HallClosure, error) {
+	query := `select * from hall_closures where hall_id=$1 order by start_date_time;`
+
+	closureDB := []HallClosurePostgreSQL{}
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).SelectContext(ctx, &closureDB, query, hallID)
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	closureModels := []extModels.HallClosure{}
+	for i := range closureDB {
+		closure := extModels.HallClosure{}
+		err = copier.Copy(&closure, &closureDB[i])
+		if err != nil {
+			return nil, err
+		}
+
+		closureModels = append(closureModels, closure)
+	}
+
+	return closureModels, nil
+}
+
+func (h *HallClosurePostgreSQLRepository) Update(ctx context.Context, closure *extModels.HallClosure) error {
+	query := `update hall_closures set hall_id=$2, start_date_time=$3, end_date_time=$4, reason=$5 where hall_closure_id=$1 returning hall_closure_id;`
+
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).
+		QueryRowxContext(ctx, query, closure.ID, closure.HallID, closure.Start.UTC(), closure.End.UTC(), closure.Reason).
+		Scan(&closure.ID)
+	if err == sql.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *HallClosurePostgreSQLRepository) Delete(ctx context.Context, id uint64) error {
+	query := `delete from hall_closures where hall_closure_id=$1 returning hall_closure_id;`
+
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).QueryRowxContext(ctx, query, id).Scan(&id)
+	if err == sql.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetConflictingTrainings returns the trainings that are scheduled in the
+// hall during the closure and have to be moved.
+func (h *HallClosurePostgreSQLRepository) GetConflictingTrainings(ctx context.Context, id uint64) ([]models.Training, error) {
+	query := `select t.* from trainings t join hall_closures c on c.hall_id = t.hall_id
+where c.hall_closure_id=$1 and t.date_time < c.end_date_time and t.date_time + interval '1 hour' > c.start_date_time order by t.date_time;`
+
+	trainingDB := []TrainingPostgreSQL{}
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).SelectContext(ctx, &trainingDB, query, id)
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	trainingModels := []models.Training{}
+	for i := range trainingDB {
+		training := models.Training{}
+		err = copier.Copy(&training, &trainingDB[i])
+		if err != nil {
+			return nil, err
+		}
+
+		trainingModels = append(trainingModels, training)
+	}
+
+	return trainingModels, nil
+}
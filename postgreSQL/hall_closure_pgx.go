@@ -0,0 +1,120 @@
+package postgreSQL
+
+import (
+	"context"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+
+	"github.com/nkarakotova/lim-core/models"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+const hallClosureColumns = `hall_closure_id, hall_id, start_date_time, end_date_time, reason`
+
+type HallClosurePgxRepository struct {
+	db         trmpgx.Tr
+	txResolver *trmpgx.CtxGetter
+}
+
+func NewHallClosurePgxRepository(db trmpgx.Tr) extRepositories.HallClosureRepository {
+	return &HallClosurePgxRepository{db: db, txResolver: trmpgx.DefaultCtxGetter}
+}
+
+func (h *HallClosurePgxRepository) Create(ctx context.Context, closure *extModels.HallClosure) error {
+	query := `insert into hall_closures(hall_id, start_date_time, end_date_time, reason) values($1, $2, $3, $4) returning hall_closure_id;`
+
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).
+		QueryRow(ctx, query, closure.HallID, closure.Start.UTC(), closure.End.UTC(), closure.Reason).
+		Scan(&closure.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *HallClosurePgxRepository) GetByID(ctx context.Context, id uint64) (*extModels.HallClosure, error) {
+	query := `select ` + hallClosureColumns + ` from hall_closures where hall_closure_id=$1;`
+
+	closure := &extModels.HallClosure{}
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).
+		QueryRow(ctx, query, id).
+		Scan(&closure.ID, &closure.HallID, &closure.Start, &closure.End, &closure.Reason)
+	if err == pgx.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	return closure, nil
+}
+
+func (h *HallClosurePgxRepository) GetAllByHall(ctx context.Context, hallID uint64) ([]extModels.HallClosure, error) {
+	query := `select ` + hallClosureColumns + ` from hall_closures where hall_id=$1 order by start_date_time;`
+
+	rows, err := h.txResolver.DefaultTrOrDB(ctx, h.db).Query(ctx, query, hallID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	closureModels := []extModels.HallClosure{}
+	for rows.Next() {
+		closure := extModels.HallClosure{}
+		err = rows.Scan(&closure.ID, &closure.HallID, &closure.Start, &closure.End, &closure.Reason)
+		if err != nil {
+			return nil, err
+		}
+
+		closureModels = append(closureModels, closure)
+	}
+
+	return closureModels, rows.Err()
+}
+
+func (h *HallClosurePgxRepository) Update(ctx context.Context, closure *extModels.HallClosure) error {
+	query := `update hall_closures set hall_id=$2, start_date_time=$3, end_date_time=$4, reason=$5 where hall_closure_id=$1 returning hall_closure_id;`
+
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).
+		QueryRow(ctx, query, closure.ID, closure.HallID, closure.Start.UTC(), closure.End.UTC(), closure.Reason).
+		Scan(&closure.ID)
+	if err == pgx.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *HallClosurePgxRepository) Delete(ctx context.Context, id uint64) error {
+	query := `delete from hall_closures where hall_closure_id=$1 returning hall_closure_id;`
+
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).QueryRow(ctx, query, id).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetConflictingTrainings returns the trainings that are scheduled in the
+// hall during the closure and have to be moved.
+func (h *HallClosurePgxRepository) GetConflictingTrainings(ctx context.Context, id uint64) ([]models.Training, error) {
+	query := `select t.training_id, t.coach_id, t.hall_id, t.name, t.date_time, t.places_num from trainings t join hall_closures c on c.hall_id = t.hall_id
+where c.hall_closure_id=$1 and t.date_time < c.end_date_time and t.date_time + interval '1 hour' > c.start_date_time order by t.date_time;`
+
+	rows, err := h.txResolver.DefaultTrOrDB(ctx, h.db).Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTrainings(rows)
+}
@@ -0,0 +1,192 @@
+package postgreSQL
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	extModels "github.com/nkarakotova/lim-repo/models"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type HallClosureSuite struct {
+	suite.Suite
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+	repository extRepositories.HallClosureRepository
+	ctx        context.Context
+}
+
+func (s *HallClosureSuite) BeforeEach(t provider.T) {
+	var err error
+	s.db, s.mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	dbx := sqlx.NewDb(s.db, "pgx")
+	s.repository = NewHallClosurePostgreSQLRepository(dbx)
+	s.ctx = context.Background()
+}
+
+func (s *HallClosureSuite) AfterEach(t provider.T) {
+	s.db.Close()
+}
+
+func testHallClosure() *extModels.HallClosure {
+	return &extModels.HallClosure{
+		ID:     1,
+		HallID: 1,
+		Start:  time.Date(2024, 7, 7, 0, 0, 0, 0, time.UTC),
+		End:    time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC),
+		Reason: "repairs",
+	}
+}
+
+func (s *HallClosureSuite) TestHallClosureMockCreateSuccess(t provider.T) {
+	t.Title("HallClosureMockCreate: Success")
+	t.Tags("HallClosure")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		closure := testHallClosure()
+		s.mock.ExpectQuery(`insert into hall_closures(hall_id, start_date_time, end_date_time, reason) values($1, $2, $3, $4) returning hall_closure_id;`).
+			WithArgs(1, closure.Start, closure.End, "repairs").
+			WillReturnRows(sqlmock.NewRows([]string{"hall_closure_id"}).AddRow(1))
+
+		err := s.repository.Create(s.ctx, closure)
+
+		sCtx.Assert().NoError(err)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *HallClosureSuite) TestHallClosureMockGetByIDSuccess(t provider.T) {
+	t.Title("HallClosureMockGetByID: Success")
+	t.Tags("HallClosure")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		closure := testHallClosure()
+		s.mock.ExpectQuery(`select * from hall_closures where hall_closure_id=$1;`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"hall_closure_id", "hall_id", "start_date_time", "end_date_time", "reason"}).
+				AddRow(1, 1, closure.Start, closure.End, "repairs"))
+
+		result, err := s.repository.GetByID(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(closure, result)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *HallClosureSuite) TestHallClosureMockGetByIDFailure(t provider.T) {
+	t.Title("HallClosureMockGetByID: Failure")
+	t.Tags("HallClosure")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`select * from hall_closures where hall_closure_id=$1;`).WithArgs(1).WillReturnError(sql.ErrNoRows)
+
+		_, err := s.repository.GetByID(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *HallClosureSuite) TestHallClosureMockGetAllByHallSuccess(t provider.T) {
+	t.Title("HallClosureMockGetAllByHall: Success")
+	t.Tags("HallClosure")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		closure := testHallClosure()
+		s.mock.ExpectQuery(`select * from hall_closures where hall_id=$1 order by start_date_time;`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"hall_closure_id", "hall_id", "start_date_time", "end_date_time", "reason"}).
+				AddRow(1, 1, closure.Start, closure.End, "repairs"))
+
+		closures, err := s.repository.GetAllByHall(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]extModels.HallClosure{*closure}, closures)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *HallClosureSuite) TestHallClosureMockUpdateFailure(t provider.T) {
+	t.Title("HallClosureMockUpdate: Failure")
+	t.Tags("HallClosure")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		closure := testHallClosure()
+		s.mock.ExpectQuery(`update hall_closures set hall_id=$2, start_date_time=$3, end_date_time=$4, reason=$5 where hall_closure_id=$1 returning hall_closure_id;`).
+			WithArgs(1, 1, closure.Start, closure.End, "repairs").
+			WillReturnError(sql.ErrNoRows)
+
+		err := s.repository.Update(s.ctx, closure)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *HallClosureSuite) TestHallClosureMockDeleteSuccess(t provider.T) {
+	t.Title("HallClosureMockDelete: Success")
+	t.Tags("HallClosure")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`delete from hall_closures where hall_closure_id=$1 returning hall_closure_id;`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"hall_closure_id"}).AddRow(1))
+
+		err := s.repository.Delete(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *HallClosureSuite) TestHallClosureMockGetConflictingTrainingsSuccess(t provider.T) {
+	t.Title("HallClosureMockGetConflictingTrainings: Success")
+	t.Tags("HallClosure")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`select t.* from trainings t join hall_closures c on c.hall_id = t.hall_id
+where c.hall_closure_id=$1 and t.date_time < c.end_date_time and t.date_time + interval '1 hour' > c.start_date_time order by t.date_time;`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"training_id", "coach_id", "hall_id", "name", "date_time", "places_num"}).
+				AddRow(1, 1, 1, "Name", time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC), 10))
+
+		trainings, err := s.repository.GetConflictingTrainings(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Training{*postgreSQLObjectMother.CreateTestTraining()}, trainings)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestHallClosureSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(HallClosureSuite))
+}
@@ -0,0 +1,178 @@
+package postgreSQL
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+
+	"github.com/nkarakotova/lim-core/models"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+type HallPgxRepository struct {
+	db         trmpgx.Tr
+	txResolver *trmpgx.CtxGetter
+}
+
+func NewHallPgxRepository(db trmpgx.Tr) extRepositories.HallRepository {
+	return &HallPgxRepository{db: db, txResolver: trmpgx.DefaultCtxGetter}
+}
+
+func scanHalls(rows pgx.Rows) (map[uint64]models.Hall, error) {
+	defer rows.Close()
+
+	hallModels := make(map[uint64]models.Hall)
+	for rows.Next() {
+		hall := models.Hall{}
+		err := rows.Scan(&hall.ID, &hall.Number)
+		if err != nil {
+			return nil, err
+		}
+
+		hallModels[hall.ID] = hall
+	}
+
+	return hallModels, rows.Err()
+}
+
+func (h *HallPgxRepository) Create(ctx context.Context, hall *models.Hall) error {
+	query := `insert into halls(number) values($1) returning hall_id;`
+
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).QueryRow(ctx, query, hall.Number).Scan(&hall.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *HallPgxRepository) getOne(ctx context.Context, query string, arg interface{}) (*models.Hall, error) {
+	hall := &models.Hall{}
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).QueryRow(ctx, query, arg).Scan(&hall.ID, &hall.Number)
+	if err == pgx.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	return hall, nil
+}
+
+func (h *HallPgxRepository) GetByID(ctx context.Context, id uint64) (*models.Hall, error) {
+	query := `select hall_id, number from halls where hall_id=$1;`
+
+	return h.getOne(ctx, query, id)
+}
+
+func (h *HallPgxRepository) GetByNumber(ctx context.Context, number uint64) (*models.Hall, error) {
+	query := `select hall_id, number from halls where number=$1;`
+
+	return h.getOne(ctx, query, number)
+}
+
+func (h *HallPgxRepository) GetAll(ctx context.Context) (map[uint64]models.Hall, error) {
+	query := `select hall_id, number from halls;`
+
+	rows, err := h.txResolver.DefaultTrOrDB(ctx, h.db).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanHalls(rows)
+}
+
+// GetDetails reads the hall and its equipment in one batch.
+func (h *HallPgxRepository) GetDetails(ctx context.Context, id uint64) (*extModels.HallDetails, error) {
+	batch := &pgx.Batch{}
+	batch.Queue(`select hall_id, capacity, area, floor from halls where hall_id=$1;`, id)
+	batch.Queue(`select equipment, count from hall_equipment where hall_id=$1;`, id)
+
+	results := h.txResolver.DefaultTrOrDB(ctx, h.db).SendBatch(ctx, batch)
+	defer results.Close()
+
+	var capacity *int64
+	var area *float64
+	details := &extModels.HallDetails{Equipment: make(map[extModels.Equipment]uint64)}
+	err := results.QueryRow().Scan(&details.HallID, &capacity, &area, &details.Floor)
+	if err == pgx.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+	if capacity != nil {
+		details.Capacity = uint64(*capacity)
+	}
+	if area != nil {
+		details.Area = *area
+	}
+
+	rows, err := results.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var equipment string
+		var count uint64
+		err = rows.Scan(&equipment, &count)
+		if err != nil {
+			return nil, err
+		}
+
+		details.Equipment[extModels.Equipment(equipment)] = count
+	}
+
+	return details, rows.Err()
+}
+
+func (h *HallPgxRepository) UpdateDetails(ctx context.Context, details *extModels.HallDetails) error {
+	query := `update halls set capacity=nullif($2::integer, 0), area=nullif($3::numeric, 0), floor=$4 where hall_id=$1 returning hall_id;`
+
+	var id uint64
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).
+		QueryRow(ctx, query, details.HallID, details.Capacity, details.Area, details.Floor).
+		Scan(&id)
+	if err == pgx.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *HallPgxRepository) SetEquipment(ctx context.Context, id uint64, equipment extModels.Equipment, count uint64) error {
+	query := `insert into hall_equipment(hall_id, equipment, count) values($1, $2, $3)
+on conflict (hall_id, equipment) do update set count = excluded.count returning hall_id;`
+
+	err := h.txResolver.DefaultTrOrDB(ctx, h.db).QueryRow(ctx, query, id, string(equipment), count).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *HallPgxRepository) GetAllByEquipment(ctx context.Context, required map[extModels.Equipment]uint64) (map[uint64]models.Hall, error) {
+	query := `select h.hall_id, h.number from halls h where not exists (select 1 from jsonb_each_text($1::jsonb) r
+where coalesce((select e.count from hall_equipment e where e.hall_id = h.hall_id and e.equipment = r.key), 0) < r.value::integer);`
+
+	requiredJSON, err := json.Marshal(required)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := h.txResolver.DefaultTrOrDB(ctx, h.db).Query(ctx, query, string(requiredJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	return scanHalls(rows)
+}
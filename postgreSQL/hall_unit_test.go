@@ -8,10 +8,11 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
 	"github.com/nkarakotova/lim-core/models"
-	"github.com/nkarakotova/lim-core/repositories"
 
 	"github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
 	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	extModels "github.com/nkarakotova/lim-repo/models"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
 
 	"github.com/ozontech/allure-go/pkg/framework/provider"
 	"github.com/ozontech/allure-go/pkg/framework/suite"
@@ -21,7 +22,7 @@ type HallSuite struct {
 	suite.Suite
 	db         *sql.DB
 	mock       sqlmock.Sqlmock
-	repository repositories.HallRepository
+	repository extRepositories.HallRepository
 	ctx        context.Context
 }
 
@@ -188,6 +189,112 @@ func (s *HallSuite) TestHallMockGetAllFailure(t provider.T) {
 	})
 }
 
+func (s *HallSuite) TestHallMockGetDetailsSuccess(t provider.T) {
+	t.Title("HallMockGetDetails: Success")
+	t.Tags("Hall")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`select * from halls where hall_id=$1;`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"hall_id", "number", "capacity", "area", "floor"}).
+			AddRow(1, 1, 20, "64.50", 2))
+		s.mock.ExpectQuery(`select equipment, count from hall_equipment where hall_id=$1;`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"equipment", "count"}).
+			AddRow("mats", 20).
+			AddRow("reformers", 4))
+
+		details, err := s.repository.GetDetails(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(&extModels.HallDetails{
+			HallID:    1,
+			Capacity:  20,
+			Area:      64.5,
+			Floor:     2,
+			Equipment: map[extModels.Equipment]uint64{extModels.Mats: 20, extModels.Reformers: 4},
+		}, details)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *HallSuite) TestHallMockGetDetailsFailure(t provider.T) {
+	t.Title("HallMockGetDetails: Failure")
+	t.Tags("Hall")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`select * from halls where hall_id=$1;`).WithArgs(1).WillReturnError(sql.ErrNoRows)
+
+		_, err := s.repository.GetDetails(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *HallSuite) TestHallMockUpdateDetailsSuccess(t provider.T) {
+	t.Title("HallMockUpdateDetails: Success")
+	t.Tags("Hall")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`update halls set capacity=nullif($2::integer, 0), area=nullif($3::numeric, 0), floor=$4 where hall_id=$1 returning hall_id;`).
+			WithArgs(1, 20, 64.5, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"hall_id"}).AddRow(1))
+
+		err := s.repository.UpdateDetails(s.ctx, &extModels.HallDetails{HallID: 1, Capacity: 20, Area: 64.5, Floor: 2})
+
+		sCtx.Assert().NoError(err)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *HallSuite) TestHallMockSetEquipmentSuccess(t provider.T) {
+	t.Title("HallMockSetEquipment: Success")
+	t.Tags("Hall")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`insert into hall_equipment(hall_id, equipment, count) values($1, $2, $3)
+on conflict (hall_id, equipment) do update set count = excluded.count returning hall_id;`).
+			WithArgs(1, "bikes", 12).
+			WillReturnRows(sqlmock.NewRows([]string{"hall_id"}).AddRow(1))
+
+		err := s.repository.SetEquipment(s.ctx, 1, extModels.Bikes, 12)
+
+		sCtx.Assert().NoError(err)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *HallSuite) TestHallMockGetAllByEquipmentSuccess(t provider.T) {
+	t.Title("HallMockGetAllByEquipment: Success")
+	t.Tags("Hall")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`select h.* from halls h where not exists (select 1 from jsonb_each_text($1::jsonb) r
+where coalesce((select e.count from hall_equipment e where e.hall_id = h.hall_id and e.equipment = r.key), 0) < r.value::integer);`).
+			WithArgs(`{"bikes":10,"mats":5}`).
+			WillReturnRows(sqlmock.NewRows([]string{"hall_id", "number"}).
+			AddRow(1, 1))
+
+		new_halls := map[uint64]models.Hall{1: *postgreSQLObjectMother.CreateTestHall()}
+		halls, err := s.repository.GetAllByEquipment(s.ctx, map[extModels.Equipment]uint64{extModels.Mats: 5, extModels.Bikes: 10})
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(new_halls, halls)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
 func TestHallSuiteRunner(t *testing.T) {
 	suite.RunSuite(t, new(HallSuite))
 }
@@ -0,0 +1,350 @@
+package postgreSQL
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nkarakotova/lim-repo/replica"
+)
+
+type HealthStatus string
+
+const (
+	HealthOK       HealthStatus = "ok"
+	HealthDegraded HealthStatus = "degraded"
+	HealthFailed   HealthStatus = "failed"
+)
+
+// worse returns the more severe of two statuses.
+func (s HealthStatus) worse(other HealthStatus) HealthStatus {
+	rank := map[HealthStatus]int{HealthOK: 0, HealthDegraded: 1, HealthFailed: 2}
+	if rank[other] > rank[s] {
+		return other
+	}
+
+	return s
+}
+
+// HealthThresholds are the limits past which a check is degraded. A zero
+// field takes its value from DefaultHealthThresholds.
+type HealthThresholds struct {
+	PingLatency    time.Duration
+	ReplicationLag time.Duration
+	// PoolSaturation is the share of the maximum connections in use.
+	PoolSaturation float64
+}
+
+var DefaultHealthThresholds = HealthThresholds{
+	PingLatency:    100 * time.Millisecond,
+	ReplicationLag: 10 * time.Second,
+	PoolSaturation: 0.9,
+}
+
+func (t HealthThresholds) withDefaults() HealthThresholds {
+	if t.PingLatency <= 0 {
+		t.PingLatency = DefaultHealthThresholds.PingLatency
+	}
+	if t.ReplicationLag <= 0 {
+		t.ReplicationLag = DefaultHealthThresholds.ReplicationLag
+	}
+	if t.PoolSaturation <= 0 {
+		t.PoolSaturation = DefaultHealthThresholds.PoolSaturation
+	}
+
+	return t
+}
+
+type HealthCheck struct {
+	Name     string        `json:"name"`
+	Status   HealthStatus  `json:"status"`
+	Message  string        `json:"message,omitempty"`
+	Duration time.Duration `json:"-"`
+}
+
+func (c HealthCheck) MarshalJSON() ([]byte, error) {
+	type check HealthCheck
+	return json.Marshal(struct {
+		check
+		Duration string `json:"duration"`
+	}{check(c), c.Duration.String()})
+}
+
+// HealthReport is the outcome of CheckHealth. Its Status is the worst one of
+// the checks.
+type HealthReport struct {
+	Status    HealthStatus  `json:"status"`
+	CheckedAt time.Time     `json:"checked_at"`
+	Checks    []HealthCheck `json:"checks"`
+}
+
+// requiredRelations are the tables and indexes the repositories rely on,
+// including the ones backing the unique constraints of the bookings.
+var requiredRelations = []string{
+	"schema_migrations",
+	"clients",
+	"clients_telephone_key",
+	"coaches",
+	"coaches_name_key",
+	"halls",
+	"halls_number_key",
+	"trainings",
+	"clients_trainings",
+	"clients_trainings_pkey",
+	"hall_cancel_policies",
+	"training_cancel_policies",
+	"cancellations",
+	"cancellations_training_id_idx",
+	"coach_availability",
+	"coach_availability_coach_id_idx",
+	"coach_time_off",
+	"coach_time_off_coach_id_idx",
+	"hall_equipment",
+	"hall_closures",
+	"hall_closures_hall_id_idx",
+}
+
+// CheckHealth reports whether the database of fields is usable: the ping
+// latency, the pending migrations, the replication lag when DB is a replica,
+// the saturation of the pools and the presence of the required tables and
+// indexes. A failed check means the repositories cannot work, a degraded one
+// that they work slowly or on stale data.
+func (fields *PostgresRepositoryFields) CheckHealth(ctx context.Context, thresholds HealthThresholds) HealthReport {
+	thresholds = thresholds.withDefaults()
+
+	checks := []struct {
+		name  string
+		check func(context.Context, HealthThresholds) (HealthStatus, string, error)
+	}{
+		{"ping", fields.checkPing},
+		{"migrations", fields.checkMigrations},
+		{"replication", fields.checkReplication},
+		{"pool", fields.checkPool},
+		{"schema", fields.checkSchema},
+	}
+
+	report := HealthReport{Status: HealthOK, CheckedAt: time.Now()}
+	for _, c := range checks {
+		start := time.Now()
+		status, message, err := c.check(ctx, thresholds)
+		if err != nil {
+			status, message = HealthFailed, err.Error()
+		}
+
+		report.Checks = append(report.Checks, HealthCheck{
+			Name:     c.name,
+			Status:   status,
+			Message:  message,
+			Duration: time.Since(start),
+		})
+		report.Status = report.Status.worse(status)
+	}
+
+	return report
+}
+
+func (fields *PostgresRepositoryFields) checkPing(ctx context.Context, thresholds HealthThresholds) (HealthStatus, string, error) {
+	start := time.Now()
+	err := fields.DB.PingContext(ctx)
+	if err != nil {
+		return HealthFailed, "", err
+	}
+	latency := time.Since(start)
+
+	if fields.Pool != nil {
+		start = time.Now()
+		err = fields.Pool.Ping(ctx)
+		if err != nil {
+			return HealthFailed, "", fmt.Errorf("pgxpool: %w", err)
+		}
+		if poolLatency := time.Since(start); poolLatency > latency {
+			latency = poolLatency
+		}
+	}
+
+	message := fmt.Sprintf("latency %s", latency)
+	if latency > thresholds.PingLatency {
+		return HealthDegraded, message + ", over " + thresholds.PingLatency.String(), nil
+	}
+
+	return HealthOK, message, nil
+}
+
+func (fields *PostgresRepositoryFields) checkMigrations(ctx context.Context, _ HealthThresholds) (HealthStatus, string, error) {
+	names, err := Migrations()
+	if err != nil {
+		return HealthFailed, "", err
+	}
+
+	query := `select version from schema_migrations;`
+	rows, err := fields.DB.QueryContext(ctx, query)
+	if err != nil {
+		return HealthFailed, "", err
+	}
+	defer rows.Close()
+
+	applied := map[string]bool{}
+	for rows.Next() {
+		var version string
+		err = rows.Scan(&version)
+		if err != nil {
+			return HealthFailed, "", err
+		}
+		applied[version] = true
+	}
+	err = rows.Err()
+	if err != nil {
+		return HealthFailed, "", err
+	}
+
+	var pending []string
+	for _, name := range names {
+		if !applied[name] {
+			pending = append(pending, path.Base(name))
+		}
+		delete(applied, name)
+	}
+	if len(pending) > 0 {
+		return HealthFailed, "pending " + strings.Join(pending, ", "), nil
+	}
+
+	// The database was migrated by a newer version of the repositories.
+	if len(applied) > 0 {
+		unknown := make([]string, 0, len(applied))
+		for version := range applied {
+			unknown = append(unknown, path.Base(version))
+		}
+		sort.Strings(unknown)
+		return HealthDegraded, "unknown " + strings.Join(unknown, ", "), nil
+	}
+
+	return HealthOK, fmt.Sprintf("%d applied", len(names)), nil
+}
+
+func (fields *PostgresRepositoryFields) checkReplication(ctx context.Context, thresholds HealthThresholds) (HealthStatus, string, error) {
+	var recovery bool
+	query := `select pg_is_in_recovery();`
+	err := fields.DB.QueryRowContext(ctx, query).Scan(&recovery)
+	if err != nil {
+		return HealthFailed, "", err
+	}
+	if !recovery {
+		return HealthOK, "primary", nil
+	}
+
+	// The lag is measured as the replica router does, so that a caught-up
+	// replica of an idle primary is not degraded.
+	lag, err := replica.Lag(ctx, fields.DB)
+	if errors.Is(err, replica.ErrNothingReplayed) {
+		return HealthDegraded, "replica, nothing replayed yet", nil
+	}
+	if err != nil {
+		return HealthFailed, "", err
+	}
+
+	lag = lag.Round(time.Millisecond)
+	message := fmt.Sprintf("replica, lag %s", lag)
+	if lag > thresholds.ReplicationLag {
+		return HealthDegraded, message + ", over " + thresholds.ReplicationLag.String(), nil
+	}
+
+	return HealthOK, message, nil
+}
+
+func (fields *PostgresRepositoryFields) checkPool(_ context.Context, thresholds HealthThresholds) (HealthStatus, string, error) {
+	type pool struct {
+		name  string
+		stats sql.DBStats
+	}
+	pools := []pool{{"postgres", fields.DB.Stats()}}
+	if fields.Pool != nil {
+		pools = append(pools, pool{"pgxpool", pgxPoolStats(fields.Pool.Stat())})
+	}
+
+	status := HealthOK
+	messages := make([]string, 0, len(pools))
+	for _, pool := range pools {
+		// An unlimited pool cannot saturate.
+		if pool.stats.MaxOpenConnections <= 0 {
+			messages = append(messages, fmt.Sprintf("%s %d in use", pool.name, pool.stats.InUse))
+			continue
+		}
+
+		saturation := float64(pool.stats.InUse) / float64(pool.stats.MaxOpenConnections)
+		messages = append(messages, fmt.Sprintf("%s %d/%d in use", pool.name, pool.stats.InUse, pool.stats.MaxOpenConnections))
+		if saturation >= thresholds.PoolSaturation {
+			status = HealthDegraded
+		}
+	}
+
+	return status, strings.Join(messages, ", "), nil
+}
+
+func (fields *PostgresRepositoryFields) checkSchema(ctx context.Context, _ HealthThresholds) (HealthStatus, string, error) {
+	query := `select c.relname from pg_class c join pg_namespace n on n.oid = c.relnamespace
+		where n.nspname = current_schema() and c.relkind in ('r', 'p', 'i');`
+	rows, err := fields.DB.QueryContext(ctx, query)
+	if err != nil {
+		return HealthFailed, "", err
+	}
+	defer rows.Close()
+
+	present := map[string]bool{}
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			return HealthFailed, "", err
+		}
+		present[name] = true
+	}
+	err = rows.Err()
+	if err != nil {
+		return HealthFailed, "", err
+	}
+
+	var missing []string
+	for _, name := range requiredRelations {
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return HealthFailed, "missing " + strings.Join(missing, ", "), nil
+	}
+
+	return HealthOK, fmt.Sprintf("%d tables and indexes", len(requiredRelations)), nil
+}
+
+// HealthHandler serves the report of CheckHealth as JSON. It answers 503
+// Service Unavailable when a check failed, so it fits a readiness probe; a
+// degraded database is still ready. Each request is bounded by timeout when
+// it is positive.
+func (fields *PostgresRepositoryFields) HealthHandler(thresholds HealthThresholds, timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		report := fields.CheckHealth(ctx, thresholds)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Status == HealthFailed {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
@@ -0,0 +1,211 @@
+package postgreSQL
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+const (
+	migrationsQuery  = `select version from schema_migrations;`
+	recoveryQuery    = `select pg_is_in_recovery();`
+	replicationQuery = `select case when pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() then 0
+		else extract(epoch from now() - pg_last_xact_replay_timestamp()) end;`
+	relationsQuery = `select c.relname from pg_class c join pg_namespace n on n.oid = c.relnamespace
+		where n.nspname = current_schema() and c.relkind in ('r', 'p', 'i');`
+)
+
+type HealthSuite struct {
+	suite.Suite
+	db     *sql.DB
+	mock   sqlmock.Sqlmock
+	fields *PostgresRepositoryFields
+	ctx    context.Context
+}
+
+func (s *HealthSuite) BeforeEach(t provider.T) {
+	var err error
+	s.db, s.mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual), sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	s.fields = &PostgresRepositoryFields{DB: s.db}
+	s.ctx = context.Background()
+}
+
+func (s *HealthSuite) AfterEach(t provider.T) {
+	s.db.Close()
+}
+
+func (s *HealthSuite) expectMigrations(sCtx provider.StepCtx, skip int) {
+	names, err := Migrations()
+	sCtx.Require().NoError(err)
+
+	rows := sqlmock.NewRows([]string{"version"})
+	for _, name := range names[skip:] {
+		rows.AddRow(name)
+	}
+	s.mock.ExpectQuery(migrationsQuery).WillReturnRows(rows)
+}
+
+func (s *HealthSuite) expectRelations(skip int) {
+	rows := sqlmock.NewRows([]string{"relname"})
+	for _, name := range requiredRelations[skip:] {
+		rows.AddRow(name)
+	}
+	s.mock.ExpectQuery(relationsQuery).WillReturnRows(rows)
+}
+
+func checkByName(report HealthReport, name string) HealthCheck {
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+
+	return HealthCheck{}
+}
+
+func (s *HealthSuite) TestHealthy(t provider.T) {
+	t.Title("Health: A migrated primary is healthy")
+	t.Tags("Health")
+	t.WithNewStep("Healthy", func(sCtx provider.StepCtx) {
+		s.mock.ExpectPing()
+		s.expectMigrations(sCtx, 0)
+		s.mock.ExpectQuery(recoveryQuery).WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(false))
+		s.expectRelations(0)
+
+		report := s.fields.CheckHealth(s.ctx, HealthThresholds{PingLatency: time.Minute})
+
+		sCtx.Assert().Equal(HealthOK, report.Status)
+		sCtx.Assert().Len(report.Checks, 5)
+		for _, check := range report.Checks {
+			sCtx.Assert().Equal(HealthOK, check.Status, check.Name)
+		}
+		sCtx.Assert().Equal("primary", checkByName(report, "replication").Message)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *HealthSuite) TestReplicaLag(t provider.T) {
+	t.Title("Health: A lagging replica is degraded")
+	t.Tags("Health")
+	t.WithNewStep("Lag", func(sCtx provider.StepCtx) {
+		s.mock.ExpectPing()
+		s.expectMigrations(sCtx, 0)
+		s.mock.ExpectQuery(recoveryQuery).WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(true))
+		s.mock.ExpectQuery(replicationQuery).WillReturnRows(sqlmock.NewRows([]string{"extract"}).AddRow(42.5))
+		s.expectRelations(0)
+
+		report := s.fields.CheckHealth(s.ctx, HealthThresholds{PingLatency: time.Minute})
+
+		sCtx.Assert().Equal(HealthDegraded, report.Status)
+		replication := checkByName(report, "replication")
+		sCtx.Assert().Equal(HealthDegraded, replication.Status)
+		sCtx.Assert().Contains(replication.Message, "lag 42.5s")
+	})
+	t.WithNewStep("Caught up", func(sCtx provider.StepCtx) {
+		s.mock.ExpectPing()
+		s.expectMigrations(sCtx, 0)
+		s.mock.ExpectQuery(recoveryQuery).WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(true))
+		s.mock.ExpectQuery(replicationQuery).WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(0))
+		s.expectRelations(0)
+
+		report := s.fields.CheckHealth(s.ctx, HealthThresholds{PingLatency: time.Minute, ReplicationLag: time.Second})
+
+		replication := checkByName(report, "replication")
+		sCtx.Assert().Equal(HealthOK, replication.Status)
+		sCtx.Assert().Equal("replica, lag 0s", replication.Message)
+	})
+}
+
+func (s *HealthSuite) TestIncompleteSchema(t provider.T) {
+	t.Title("Health: Pending migrations and missing tables fail the database")
+	t.Tags("Health")
+	t.WithNewStep("Incomplete", func(sCtx provider.StepCtx) {
+		s.mock.ExpectPing()
+		s.expectMigrations(sCtx, 1)
+		s.mock.ExpectQuery(recoveryQuery).WillReturnRows(sqlmock.NewRows([]string{"pg_is_in_recovery"}).AddRow(false))
+		s.expectRelations(1)
+
+		report := s.fields.CheckHealth(s.ctx, HealthThresholds{PingLatency: time.Minute})
+
+		sCtx.Assert().Equal(HealthFailed, report.Status)
+		migrations := checkByName(report, "migrations")
+		sCtx.Assert().Equal(HealthFailed, migrations.Status)
+		sCtx.Assert().Equal("pending 0001_init.sql", migrations.Message)
+		schema := checkByName(report, "schema")
+		sCtx.Assert().Equal(HealthFailed, schema.Status)
+		sCtx.Assert().Equal("missing schema_migrations", schema.Message)
+	})
+}
+
+func (s *HealthSuite) TestPoolSaturation(t provider.T) {
+	t.Title("Health: A saturated pool is degraded")
+	t.Tags("Health")
+	t.WithNewStep("Saturated", func(sCtx provider.StepCtx) {
+		s.db.SetMaxOpenConns(1)
+		s.mock.ExpectQuery(migrationsQuery).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+		rows, err := s.db.QueryContext(s.ctx, migrationsQuery)
+		sCtx.Require().NoError(err)
+		defer rows.Close()
+
+		status, message, err := s.fields.checkPool(s.ctx, DefaultHealthThresholds)
+
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(HealthDegraded, status)
+		sCtx.Assert().Equal("postgres 1/1 in use", message)
+	})
+}
+
+func (s *HealthSuite) TestHandler(t provider.T) {
+	t.Title("Health: The handler answers 503 when the database is unreachable")
+	t.Tags("Health")
+	t.WithNewStep("Unavailable", func(sCtx provider.StepCtx) {
+		s.mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+		s.mock.ExpectQuery(migrationsQuery).WillReturnError(errors.New("connection refused"))
+		s.mock.ExpectQuery(recoveryQuery).WillReturnError(errors.New("connection refused"))
+		s.mock.ExpectQuery(relationsQuery).WillReturnError(errors.New("connection refused"))
+
+		recorder := httptest.NewRecorder()
+		s.fields.HealthHandler(HealthThresholds{}, time.Second).
+			ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		sCtx.Assert().Equal(http.StatusServiceUnavailable, recorder.Code)
+		sCtx.Assert().Equal("application/json", recorder.Header().Get("Content-Type"))
+
+		var report struct {
+			Status HealthStatus `json:"status"`
+			Checks []struct {
+				Name     string       `json:"name"`
+				Status   HealthStatus `json:"status"`
+				Message  string       `json:"message"`
+				Duration string       `json:"duration"`
+			} `json:"checks"`
+		}
+		sCtx.Require().NoError(json.NewDecoder(recorder.Body).Decode(&report))
+		sCtx.Assert().Equal(HealthFailed, report.Status)
+		sCtx.Require().Len(report.Checks, 5)
+		sCtx.Assert().Equal("ping", report.Checks[0].Name)
+		sCtx.Assert().Equal("connection refused", report.Checks[0].Message)
+		sCtx.Assert().NotEmpty(report.Checks[0].Duration)
+		sCtx.Assert().Equal(HealthOK, report.Checks[3].Status)
+	})
+}
+
+func TestHealthSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(HealthSuite))
+}
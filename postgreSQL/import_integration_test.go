@@ -0,0 +1,77 @@
+package postgreSQL
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nkarakotova/lim-core/models"
+
+	"github.com/nkarakotova/lim-repo/generator"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type ImportDatasetIntegrationSuite struct {
+	suite.Suite
+	schema *TestSchema
+	ctx    context.Context
+}
+
+func (s *ImportDatasetIntegrationSuite) BeforeEach(t provider.T) {
+	s.ctx = context.Background()
+
+	var err error
+	s.schema, err = testDatabase.NewSchema(s.ctx)
+	if err != nil {
+		t.Fatalf("error creating schema: %v", err)
+	}
+}
+
+func (s *ImportDatasetIntegrationSuite) AfterEach(t provider.T) {
+	s.schema.Close(s.ctx)
+}
+
+func (s *ImportDatasetIntegrationSuite) TestImportDataset(t provider.T) {
+	t.Title("ImportDataset: The dataset is copied with its IDs")
+	t.Tags("Generator", "Pgx", "Integration")
+	t.WithNewStep("Import", func(sCtx provider.StepCtx) {
+		cfg := generator.Sizes["small"]
+		cfg.Days = 7
+		dataset, err := generator.Generate(cfg)
+		sCtx.Require().NoError(err)
+
+		err = ImportDataset(s.ctx, s.schema.Pool, dataset)
+		sCtx.Require().NoError(err)
+
+		err = ImportDataset(s.ctx, s.schema.Pool, dataset)
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.DatabaseNotEmpty)
+
+		trainings, err := NewTrainingPgxRepository(s.schema.Pool).GetAllBetweenDateTime(s.ctx, cfg.From, cfg.From.AddDate(0, 0, cfg.Days))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Len(trainings, len(dataset.Trainings))
+
+		booking := dataset.Bookings[0]
+		clients, err := NewClientPgxRepository(s.schema.Pool).GetByTraining(s.ctx, booking.TrainingID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().NotEmpty(clients)
+
+		var available uint64
+		err = s.schema.Pool.QueryRow(s.ctx, `select available_places_num from trainings where training_id=$1;`, booking.TrainingID).Scan(&available)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(dataset.Trainings[booking.TrainingID-1].PlacesNum-uint64(len(clients)), available)
+
+		coach := &models.Coach{Name: "Created after import"}
+		err = NewCoachPgxRepository(s.schema.Pool).Create(s.ctx, coach)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(uint64(len(dataset.Coaches)+1), coach.ID)
+	})
+}
+
+func TestImportDatasetIntegrationSuiteRunner(t *testing.T) {
+	skipWithoutDatabase(t)
+
+	suite.RunSuite(t, new(ImportDatasetIntegrationSuite))
+}
@@ -0,0 +1,99 @@
+package postgreSQL
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nkarakotova/lim-repo/generator"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+const datasetTablesEmptyQuery = `select not (exists(select 1 from clients) or exists(select 1 from coaches)
+or exists(select 1 from halls) or exists(select 1 from trainings)) as empty;`
+
+// datasetAvailablePlacesQuery takes the places of the copied bookings, the
+// insert trigger has set every training to places_num.
+const datasetAvailablePlacesQuery = `update trainings set available_places_num = places_num - booked.num
+from (select training_id, count(*) as num from clients_trainings group by training_id) as booked
+where trainings.training_id = booked.training_id;`
+
+// datasetSequenceQuery moves the sequence of serial ID %[2]s of table %[1]s
+// past the copied IDs.
+const datasetSequenceQuery = `select setval(pg_get_serial_sequence('%[1]s', '%[2]s'), (select coalesce(max(%[2]s), 0) + 1 from %[1]s), false);`
+
+// ImportDataset copies a generated dataset into empty tables with COPY,
+// keeping the IDs of the dataset. Run it in a transaction to get all of the
+// dataset or nothing. The sequences of the IDs are moved past the copied ones,
+// so Create keeps working afterwards.
+func ImportDataset(ctx context.Context, db trmpgx.Tr, dataset *generator.Dataset) error {
+	conn := trmpgx.DefaultCtxGetter.DefaultTrOrDB(ctx, db)
+
+	var empty bool
+	err := conn.QueryRow(ctx, datasetTablesEmptyQuery).Scan(&empty)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		return extRepositoriesErrors.DatabaseNotEmpty
+	}
+
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"coaches"}, []string{"coach_id", "name"},
+		pgx.CopyFromSlice(len(dataset.Coaches), func(i int) ([]interface{}, error) {
+			return []interface{}{dataset.Coaches[i].ID, dataset.Coaches[i].Name}, nil
+		}))
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"halls"}, []string{"hall_id", "number"},
+		pgx.CopyFromSlice(len(dataset.Halls), func(i int) ([]interface{}, error) {
+			return []interface{}{dataset.Halls[i].ID, dataset.Halls[i].Number}, nil
+		}))
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"clients"}, []string{"client_id", "name", "telephone", "mail", "password"},
+		pgx.CopyFromSlice(len(dataset.Clients), func(i int) ([]interface{}, error) {
+			client := dataset.Clients[i]
+			return []interface{}{client.ID, client.Name, client.Telephone, client.Mail, client.Password}, nil
+		}))
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"trainings"}, []string{"training_id", "coach_id", "hall_id", "name", "date_time", "places_num"},
+		pgx.CopyFromSlice(len(dataset.Trainings), func(i int) ([]interface{}, error) {
+			training := dataset.Trainings[i]
+			return []interface{}{training.ID, training.CoachID, training.HallID, training.Name, training.DateTime.UTC(), training.PlacesNum}, nil
+		}))
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.CopyFrom(ctx, pgx.Identifier{"clients_trainings"}, []string{"client_id", "training_id"},
+		pgx.CopyFromSlice(len(dataset.Bookings), func(i int) ([]interface{}, error) {
+			return []interface{}{dataset.Bookings[i].ClientID, dataset.Bookings[i].TrainingID}, nil
+		}))
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Exec(ctx, datasetAvailablePlacesQuery)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range [][2]string{{"clients", "client_id"}, {"coaches", "coach_id"}, {"halls", "hall_id"}, {"trainings", "training_id"}} {
+		_, err = conn.Exec(ctx, fmt.Sprintf(datasetSequenceQuery, table[0], table[1]))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package postgreSQL
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/nkarakotova/lim-repo/generator"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+
+	"github.com/pashagolub/pgxmock"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type ImportDatasetSuite struct {
+	suite.Suite
+	mock    pgxmock.PgxPoolIface
+	dataset *generator.Dataset
+	ctx     context.Context
+}
+
+func (s *ImportDatasetSuite) BeforeEach(t provider.T) {
+	var err error
+	s.mock, err = pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error creating mock pool: %v", err)
+	}
+
+	cfg := generator.Sizes["small"]
+	cfg.Days = 1
+	s.dataset, err = generator.Generate(cfg)
+	if err != nil {
+		t.Fatalf("error generating dataset: %v", err)
+	}
+	s.ctx = context.Background()
+}
+
+func (s *ImportDatasetSuite) AfterEach(t provider.T) {
+	s.mock.Close()
+}
+
+func (s *ImportDatasetSuite) TestImportDatasetSuccess(t provider.T) {
+	t.Title("ImportDataset: Success")
+	t.Tags("Generator", "Pgx")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(datasetTablesEmptyQuery).
+			WillReturnRows(pgxmock.NewRows([]string{"empty"}).AddRow(true))
+		s.mock.ExpectCopyFrom(`"coaches"`, []string{"coach_id", "name"}).
+			WillReturnResult(int64(len(s.dataset.Coaches)))
+		s.mock.ExpectCopyFrom(`"halls"`, []string{"hall_id", "number"}).
+			WillReturnResult(int64(len(s.dataset.Halls)))
+		s.mock.ExpectCopyFrom(`"clients"`, []string{"client_id", "name", "telephone", "mail", "password"}).
+			WillReturnResult(int64(len(s.dataset.Clients)))
+		s.mock.ExpectCopyFrom(`"trainings"`, []string{"training_id", "coach_id", "hall_id", "name", "date_time", "places_num"}).
+			WillReturnResult(int64(len(s.dataset.Trainings)))
+		s.mock.ExpectCopyFrom(`"clients_trainings"`, []string{"client_id", "training_id"}).
+			WillReturnResult(int64(len(s.dataset.Bookings)))
+		s.mock.ExpectExec(datasetAvailablePlacesQuery).
+			WillReturnResult(pgxmock.NewResult("UPDATE", int64(len(s.dataset.Trainings))))
+		for _, table := range [][2]string{{"clients", "client_id"}, {"coaches", "coach_id"}, {"halls", "hall_id"}, {"trainings", "training_id"}} {
+			s.mock.ExpectExec(fmt.Sprintf(datasetSequenceQuery, table[0], table[1])).
+				WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		}
+
+		err := ImportDataset(s.ctx, s.mock, s.dataset)
+
+		sCtx.Assert().NoError(err)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *ImportDatasetSuite) TestImportDatasetNotEmpty(t provider.T) {
+	t.Title("ImportDataset: Not empty")
+	t.Tags("Generator", "Pgx")
+	t.WithNewStep("Not empty", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(datasetTablesEmptyQuery).
+			WillReturnRows(pgxmock.NewRows([]string{"empty"}).AddRow(false))
+
+		err := ImportDataset(s.ctx, s.mock, s.dataset)
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.DatabaseNotEmpty)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestImportDatasetSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(ImportDatasetSuite))
+}
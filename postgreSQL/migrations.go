@@ -0,0 +1,29 @@
+package postgreSQL
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+
+	"github.com/nkarakotova/lim-repo/internal/migrate"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// Migrations returns the names of the embedded migration files in the order
+// they are applied.
+func Migrations() ([]string, error) {
+	return migrate.Names(migrationsFS)
+}
+
+// liftTimeouts lifts the session timeouts of the repository pools for the
+// transaction of a migration, which may rewrite whole tables.
+const liftTimeouts = `set local statement_timeout = 0; set local idle_in_transaction_session_timeout = 0;`
+
+// Migrate applies every embedded migration that is not yet recorded in
+// schema_migrations. Each migration runs in its own transaction, without
+// the session timeouts of db.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	return migrate.Run(ctx, db, migrationsFS, "now()", liftTimeouts)
+}
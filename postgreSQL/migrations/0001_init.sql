@@ -0,0 +1,45 @@
+create table if not exists clients (
+	client_id serial primary key,
+	name      text not null,
+	telephone text not null unique,
+	mail      text not null,
+	password  text not null
+);
+
+create table if not exists coaches (
+	coach_id serial primary key,
+	name     text not null unique
+);
+
+create table if not exists halls (
+	hall_id serial primary key,
+	number  integer not null unique
+);
+
+create table if not exists trainings (
+	training_id          serial primary key,
+	coach_id             integer not null references coaches(coach_id) on delete cascade,
+	hall_id              integer not null references halls(hall_id) on delete cascade,
+	name                 text not null,
+	date_time            timestamp not null,
+	places_num           integer not null check (places_num >= 0),
+	available_places_num integer not null default 0 check (available_places_num >= 0)
+);
+
+create or replace function trainings_set_available_places_num() returns trigger as $$
+begin
+	new.available_places_num := new.places_num;
+	return new;
+end;
+$$ language plpgsql;
+
+drop trigger if exists trainings_set_available_places_num on trainings;
+create trigger trainings_set_available_places_num
+	before insert on trainings
+	for each row execute function trainings_set_available_places_num();
+
+create table if not exists clients_trainings (
+	client_id   integer not null references clients(client_id) on delete cascade,
+	training_id integer not null references trainings(training_id) on delete cascade,
+	primary key (client_id, training_id)
+);
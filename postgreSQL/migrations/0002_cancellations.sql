@@ -0,0 +1,19 @@
+create table if not exists hall_cancel_policies (
+	hall_id            integer primary key references halls(hall_id) on delete cascade,
+	late_cancel_window interval not null check (late_cancel_window >= interval '0')
+);
+
+create table if not exists training_cancel_policies (
+	training_id        integer primary key references trainings(training_id) on delete cascade,
+	late_cancel_window interval not null check (late_cancel_window >= interval '0')
+);
+
+create table if not exists cancellations (
+	cancellation_id serial primary key,
+	client_id       integer not null references clients(client_id) on delete cascade,
+	training_id     integer not null references trainings(training_id) on delete cascade,
+	cancelled_at    timestamp not null,
+	kind            text not null check (kind in ('free', 'late'))
+);
+
+create index if not exists cancellations_training_id_idx on cancellations(training_id);
@@ -0,0 +1,21 @@
+create table if not exists coach_availability (
+	coach_availability_id serial primary key,
+	coach_id              integer not null references coaches(coach_id) on delete cascade,
+	weekday               smallint not null check (weekday between 0 and 6),
+	start_hour            smallint not null check (start_hour between 0 and 23),
+	end_hour              smallint not null check (end_hour between 1 and 24),
+	check (start_hour < end_hour)
+);
+
+create index if not exists coach_availability_coach_id_idx on coach_availability(coach_id, weekday);
+
+create table if not exists coach_time_off (
+	coach_time_off_id serial primary key,
+	coach_id          integer not null references coaches(coach_id) on delete cascade,
+	start_date_time   timestamp not null,
+	end_date_time     timestamp not null,
+	reason            text not null default '',
+	check (start_date_time < end_date_time)
+);
+
+create index if not exists coach_time_off_coach_id_idx on coach_time_off(coach_id, start_date_time);
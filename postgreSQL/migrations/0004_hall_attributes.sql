@@ -0,0 +1,10 @@
+alter table halls add column if not exists capacity integer check (capacity > 0);
+alter table halls add column if not exists area numeric(8, 2) check (area > 0);
+alter table halls add column if not exists floor integer not null default 0;
+
+create table if not exists hall_equipment (
+	hall_id   integer not null references halls(hall_id) on delete cascade,
+	equipment text not null,
+	count     integer not null check (count >= 0),
+	primary key (hall_id, equipment)
+);
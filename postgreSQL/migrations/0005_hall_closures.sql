@@ -0,0 +1,10 @@
+create table if not exists hall_closures (
+	hall_closure_id serial primary key,
+	hall_id         integer not null references halls(hall_id) on delete cascade,
+	start_date_time timestamp not null,
+	end_date_time   timestamp not null,
+	reason          text not null default '',
+	check (start_date_time < end_date_time)
+);
+
+create index if not exists hall_closures_hall_id_idx on hall_closures(hall_id, start_date_time);
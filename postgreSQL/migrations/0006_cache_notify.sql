@@ -0,0 +1,22 @@
+create or replace function notify_cache() returns trigger as $$
+begin
+	perform pg_notify('lim_cache', tg_table_name);
+	return null;
+end;
+$$ language plpgsql;
+
+drop trigger if exists coaches_notify_cache on coaches;
+create trigger coaches_notify_cache after insert or update or delete or truncate on coaches
+	for each statement execute function notify_cache();
+
+drop trigger if exists halls_notify_cache on halls;
+create trigger halls_notify_cache after insert or update or delete or truncate on halls
+	for each statement execute function notify_cache();
+
+drop trigger if exists hall_equipment_notify_cache on hall_equipment;
+create trigger hall_equipment_notify_cache after insert or update or delete or truncate on hall_equipment
+	for each statement execute function notify_cache();
+
+drop trigger if exists trainings_notify_cache on trainings;
+create trigger trainings_notify_cache after insert or update or delete or truncate on trainings
+	for each statement execute function notify_cache();
@@ -0,0 +1,31 @@
+create or replace function notify_training_event() returns trigger as $$
+begin
+	if tg_op = 'INSERT' then
+		perform pg_notify('lim_events', json_build_object('type', 'training_created', 'training_id', new.training_id)::text);
+	else
+		perform pg_notify('lim_events', json_build_object('type', 'training_deleted', 'training_id', old.training_id)::text);
+	end if;
+	return null;
+end;
+$$ language plpgsql;
+
+drop trigger if exists trainings_notify_event on trainings;
+create trigger trainings_notify_event after insert or delete on trainings
+	for each row execute function notify_training_event();
+
+create or replace function notify_booking_event() returns trigger as $$
+begin
+	if tg_op = 'INSERT' then
+		perform pg_notify('lim_events', json_build_object('type', 'booking_created',
+			'client_id', new.client_id, 'training_id', new.training_id)::text);
+	else
+		perform pg_notify('lim_events', json_build_object('type', 'booking_cancelled',
+			'client_id', old.client_id, 'training_id', old.training_id)::text);
+	end if;
+	return null;
+end;
+$$ language plpgsql;
+
+drop trigger if exists clients_trainings_notify_event on clients_trainings;
+create trigger clients_trainings_notify_event after insert or delete on clients_trainings
+	for each row execute function notify_booking_event();
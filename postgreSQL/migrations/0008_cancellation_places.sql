@@ -0,0 +1 @@
+alter table cancellations add column if not exists holds_place boolean not null default false;
@@ -0,0 +1,6 @@
+update trainings set date_time = date_time::timestamptz at time zone 'UTC';
+update cancellations set cancelled_at = cancelled_at::timestamptz at time zone 'UTC';
+update coach_time_off set start_date_time = start_date_time::timestamptz at time zone 'UTC',
+	end_date_time = end_date_time::timestamptz at time zone 'UTC';
+update hall_closures set start_date_time = start_date_time::timestamptz at time zone 'UTC',
+	end_date_time = end_date_time::timestamptz at time zone 'UTC';
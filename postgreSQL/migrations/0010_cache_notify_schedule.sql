@@ -0,0 +1,4 @@
+drop trigger if exists trainings_notify_cache on trainings;
+create trigger trainings_notify_cache
+	after insert or delete or truncate or update of coach_id, hall_id, name, date_time, places_num on trainings
+	for each statement execute function notify_cache();
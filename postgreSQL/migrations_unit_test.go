@@ -0,0 +1,63 @@
+package postgreSQL
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type MigrationsSuite struct {
+	suite.Suite
+	db   *sql.DB
+	mock sqlmock.Sqlmock
+	ctx  context.Context
+}
+
+func (s *MigrationsSuite) BeforeEach(t provider.T) {
+	var err error
+	s.db, s.mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	s.ctx = context.Background()
+}
+
+func (s *MigrationsSuite) AfterEach(t provider.T) {
+	s.db.Close()
+}
+
+func (s *MigrationsSuite) TestMigrateLiftsTimeouts(t provider.T) {
+	t.Title("Migrate: Every migration runs without the session timeouts")
+	t.Tags("Migrations")
+	t.WithNewStep("Applied", func(sCtx provider.StepCtx) {
+		names, err := Migrations()
+		sCtx.Require().NoError(err)
+
+		s.mock.ExpectExec(`create table if not exists schema_migrations(version text primary key, applied_at timestamp not null default now());`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		for _, name := range names {
+			s.mock.ExpectBegin()
+			s.mock.ExpectExec(liftTimeouts).WillReturnResult(sqlmock.NewResult(0, 0))
+			s.mock.ExpectExec(`insert into schema_migrations(version) values($1) on conflict do nothing;`).
+				WithArgs(name).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			s.mock.ExpectRollback()
+		}
+
+		err = Migrate(s.ctx, s.db)
+
+		sCtx.Assert().NoError(err)
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestMigrationsSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(MigrationsSuite))
+}
@@ -1,15 +1,67 @@
 package postgreSQLObjectMother
 
 import (
+	"fmt"
+
 	"github.com/nkarakotova/lim-core/models"
 )
 
 func CreateTestClient() *models.Client {
-	return &models.Client{
-		ID: 1,
-		Name: "Name",
-		Telephone: "1234567890",
-		Mail: "mail@mail.ru",
-		Password: "123",
-	}
+	return NewClient().
+		WithID(1).
+		WithName("Name").
+		WithTelephone("1234567890").
+		WithMail("mail@mail.ru").
+		WithPassword("123").
+		Build()
+}
+
+// ClientBuilder builds a client with a unique ID, name, telephone and mail
+// unless they are overridden.
+type ClientBuilder struct {
+	client models.Client
+}
+
+func NewClient() *ClientBuilder {
+	n := next()
+
+	return &ClientBuilder{client: models.Client{
+		ID:        n,
+		Name:      fmt.Sprintf("Client %d", n),
+		Telephone: fmt.Sprintf("+7900%07d", n),
+		Mail:      fmt.Sprintf("client%d@mail.ru", n),
+		Password:  "123",
+	}}
+}
+
+func (b *ClientBuilder) WithID(id uint64) *ClientBuilder {
+	b.client.ID = id
+	return b
+}
+
+func (b *ClientBuilder) WithName(name string) *ClientBuilder {
+	b.client.Name = name
+	return b
+}
+
+func (b *ClientBuilder) WithTelephone(telephone string) *ClientBuilder {
+	b.client.Telephone = telephone
+	return b
+}
+
+func (b *ClientBuilder) WithMail(mail string) *ClientBuilder {
+	b.client.Mail = mail
+	return b
+}
+
+func (b *ClientBuilder) WithPassword(password string) *ClientBuilder {
+	b.client.Password = password
+	return b
+}
+
+// Build returns a new client on every call, so a builder can serve as a
+// template.
+func (b *ClientBuilder) Build() *models.Client {
+	client := b.client
+	return &client
 }
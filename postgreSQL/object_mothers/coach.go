@@ -1,12 +1,41 @@
 package postgreSQLObjectMother
 
 import (
+	"fmt"
+
 	"github.com/nkarakotova/lim-core/models"
 )
 
 func CreateTestCoach() *models.Coach {
-	return &models.Coach{
-		ID: 1,
-		Name: "Name",
-	}
+	return NewCoach().WithID(1).WithName("Name").Build()
+}
+
+// CoachBuilder builds a coach with a unique ID and name unless they are
+// overridden.
+type CoachBuilder struct {
+	coach models.Coach
+}
+
+func NewCoach() *CoachBuilder {
+	n := next()
+
+	return &CoachBuilder{coach: models.Coach{
+		ID:   n,
+		Name: fmt.Sprintf("Coach %d", n),
+	}}
+}
+
+func (b *CoachBuilder) WithID(id uint64) *CoachBuilder {
+	b.coach.ID = id
+	return b
+}
+
+func (b *CoachBuilder) WithName(name string) *CoachBuilder {
+	b.coach.Name = name
+	return b
+}
+
+func (b *CoachBuilder) Build() *models.Coach {
+	coach := b.coach
+	return &coach
 }
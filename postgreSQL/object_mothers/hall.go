@@ -5,8 +5,35 @@ import (
 )
 
 func CreateTestHall() *models.Hall {
-	return &models.Hall{
-		ID: 1,
-		Number: 1,
-	}
+	return NewHall().WithID(1).WithNumber(1).Build()
+}
+
+// HallBuilder builds a hall with a unique ID and number unless they are
+// overridden.
+type HallBuilder struct {
+	hall models.Hall
+}
+
+func NewHall() *HallBuilder {
+	n := next()
+
+	return &HallBuilder{hall: models.Hall{
+		ID:     n,
+		Number: n,
+	}}
+}
+
+func (b *HallBuilder) WithID(id uint64) *HallBuilder {
+	b.hall.ID = id
+	return b
+}
+
+func (b *HallBuilder) WithNumber(number uint64) *HallBuilder {
+	b.hall.Number = number
+	return b
+}
+
+func (b *HallBuilder) Build() *models.Hall {
+	hall := b.hall
+	return &hall
 }
@@ -0,0 +1,218 @@
+package postgreSQLObjectMother
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+	"github.com/nkarakotova/lim-core/services"
+)
+
+// ScenarioBuilder describes a schedule of trainings of coaches in halls over a
+// number of days, with a share of the places booked by clients:
+//
+//	schedule := postgreSQLObjectMother.NewScenario().
+//		Week(time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)).
+//		Halls(3).
+//		Coaches(5).
+//		Occupancy(0.4).
+//		Build()
+//
+// Every hall runs TrainingsPerDay trainings a day from
+// services.FirstTrainingTime up to services.LastTrainingTime, which is
+// excluded. The halls run at the same hours, and the coaches rotate so that
+// no coach is in two halls at once; halls beyond the number of coaches stay
+// empty. A client never has two trainings at the same time.
+type ScenarioBuilder struct {
+	start           time.Time
+	days            int
+	halls           int
+	coaches         int
+	trainingsPerDay int
+	placesNum       uint64
+	occupancy       float64
+}
+
+// NewScenario starts with one day, 2024-07-08 in UTC, one hall, one coach
+// and four empty trainings of 10 places.
+func NewScenario() *ScenarioBuilder {
+	return &ScenarioBuilder{
+		start:           time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC),
+		days:            1,
+		halls:           1,
+		coaches:         1,
+		trainingsPerDay: 4,
+		placesNum:       10,
+	}
+}
+
+// From sets the first day. Only the date of start is used.
+func (b *ScenarioBuilder) From(start time.Time) *ScenarioBuilder {
+	year, month, day := start.Date()
+	b.start = time.Date(year, month, day, 0, 0, 0, 0, start.Location())
+	return b
+}
+
+func (b *ScenarioBuilder) Days(days int) *ScenarioBuilder {
+	b.days = days
+	return b
+}
+
+// Week is From(start).Days(7).
+func (b *ScenarioBuilder) Week(start time.Time) *ScenarioBuilder {
+	return b.From(start).Days(7)
+}
+
+func (b *ScenarioBuilder) Halls(halls int) *ScenarioBuilder {
+	b.halls = halls
+	return b
+}
+
+func (b *ScenarioBuilder) Coaches(coaches int) *ScenarioBuilder {
+	b.coaches = coaches
+	return b
+}
+
+// TrainingsPerDay is the number of trainings of every hall a day. It is
+// capped by the number of hours from services.FirstTrainingTime up to
+// services.LastTrainingTime, which is excluded.
+func (b *ScenarioBuilder) TrainingsPerDay(trainings int) *ScenarioBuilder {
+	b.trainingsPerDay = trainings
+	return b
+}
+
+func (b *ScenarioBuilder) PlacesNum(placesNum uint64) *ScenarioBuilder {
+	b.placesNum = placesNum
+	return b
+}
+
+// Occupancy is the share of the places of every training that is booked,
+// from 0 to 1.
+func (b *ScenarioBuilder) Occupancy(occupancy float64) *ScenarioBuilder {
+	b.occupancy = math.Max(0, math.Min(1, occupancy))
+	return b
+}
+
+// Booking is a client booked on a training.
+type Booking struct {
+	Client   *models.Client
+	Training *models.Training
+}
+
+// Scenario is the built schedule. Trainings and bookings refer to the coaches,
+// halls and clients of the scenario, Persist keeps them in sync with the IDs
+// the repositories assign.
+type Scenario struct {
+	Coaches   []*models.Coach
+	Halls     []*models.Hall
+	Clients   []*models.Client
+	Trainings []*models.Training
+	Bookings  []Booking
+
+	trainingCoaches []*models.Coach
+	trainingHalls   []*models.Hall
+}
+
+func (b *ScenarioBuilder) Build() *Scenario {
+	scenario := &Scenario{}
+
+	for i := 0; i < b.coaches; i++ {
+		scenario.Coaches = append(scenario.Coaches, NewCoach().Build())
+	}
+	for i := 0; i < b.halls; i++ {
+		scenario.Halls = append(scenario.Halls, NewHall().Build())
+	}
+
+	activeHalls := min(b.halls, b.coaches)
+	bookingsPerTraining := int(math.Round(float64(b.placesNum) * b.occupancy))
+	for i := 0; i < activeHalls*bookingsPerTraining; i++ {
+		scenario.Clients = append(scenario.Clients, NewClient().Build())
+	}
+
+	hours := services.LastTrainingTime - services.FirstTrainingTime
+	trainingsPerDay := min(b.trainingsPerDay, hours)
+	if trainingsPerDay <= 0 {
+		return scenario
+	}
+	step := hours / trainingsPerDay
+
+	for day := 0; day < b.days; day++ {
+		for slot := 0; slot < trainingsPerDay; slot++ {
+			dateTime := b.start.AddDate(0, 0, day).Add(time.Duration(services.FirstTrainingTime+slot*step) * time.Hour)
+
+			for hall := 0; hall < activeHalls; hall++ {
+				coach := scenario.Coaches[(day+slot+hall)%b.coaches]
+				training := NewTraining().
+					WithCoach(coach).
+					WithHall(scenario.Halls[hall]).
+					At(dateTime).
+					WithPlacesNum(b.placesNum).
+					Build()
+
+				scenario.Trainings = append(scenario.Trainings, training)
+				scenario.trainingCoaches = append(scenario.trainingCoaches, coach)
+				scenario.trainingHalls = append(scenario.trainingHalls, scenario.Halls[hall])
+
+				for booking := 0; booking < bookingsPerTraining; booking++ {
+					client := scenario.Clients[hall*bookingsPerTraining+booking]
+					scenario.Bookings = append(scenario.Bookings, Booking{Client: client, Training: training})
+				}
+			}
+		}
+	}
+
+	return scenario
+}
+
+// Persist creates the scenario in the repositories, which must share their
+// storage. Every booking takes a place of its training, as booking through
+// the services does.
+func (s *Scenario) Persist(ctx context.Context, clientRepository repositories.ClientRepository, coachRepository repositories.CoachRepository,
+	hallRepository repositories.HallRepository, trainingRepository repositories.TrainingRepository) error {
+	for _, coach := range s.Coaches {
+		err := coachRepository.Create(ctx, coach)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, hall := range s.Halls {
+		err := hallRepository.Create(ctx, hall)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, training := range s.Trainings {
+		training.CoachID = s.trainingCoaches[i].ID
+		training.HallID = s.trainingHalls[i].ID
+
+		err := trainingRepository.Create(ctx, training)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, client := range s.Clients {
+		err := clientRepository.Create(ctx, client)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, booking := range s.Bookings {
+		err := clientRepository.CreateAssignment(ctx, booking.Client.ID, booking.Training.ID)
+		if err != nil {
+			return err
+		}
+
+		err = trainingRepository.ReduceAvailablePlacesNum(ctx, booking.Training.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
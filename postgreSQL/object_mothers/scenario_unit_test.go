@@ -0,0 +1,121 @@
+package postgreSQLObjectMother_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nkarakotova/lim-core/services"
+
+	"github.com/nkarakotova/lim-repo/inmemory"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type ScenarioSuite struct {
+	suite.Suite
+}
+
+var monday = time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)
+
+func (s *ScenarioSuite) TestBuild(t provider.T) {
+	t.Title("[Build] A week in 3 halls with 5 coaches and 40% occupancy")
+	t.Tags("Scenario")
+	t.WithNewStep("Build", func(sCtx provider.StepCtx) {
+		scenario := postgreSQLObjectMother.NewScenario().Week(monday).Halls(3).Coaches(5).Occupancy(0.4).Build()
+
+		sCtx.Assert().Len(scenario.Coaches, 5)
+		sCtx.Assert().Len(scenario.Halls, 3)
+		sCtx.Assert().Len(scenario.Trainings, 7*3*4)
+		sCtx.Assert().Len(scenario.Bookings, 7*3*4*4)
+		sCtx.Assert().Len(scenario.Clients, 3*4)
+
+		type slot struct {
+			kind     string
+			id       uint64
+			dateTime time.Time
+		}
+		busy := map[slot]bool{}
+		for _, training := range scenario.Trainings {
+			coach := slot{"coach", training.CoachID, training.DateTime}
+			hall := slot{"hall", training.HallID, training.DateTime}
+			sCtx.Assert().False(busy[coach], "coach %d has two trainings at %v", training.CoachID, training.DateTime)
+			sCtx.Assert().False(busy[hall], "hall %d has two trainings at %v", training.HallID, training.DateTime)
+			busy[coach], busy[hall] = true, true
+
+			sCtx.Assert().GreaterOrEqual(training.DateTime, monday)
+			sCtx.Assert().Less(training.DateTime, monday.AddDate(0, 0, 7))
+		}
+		for _, booking := range scenario.Bookings {
+			client := slot{"client", booking.Client.ID, booking.Training.DateTime}
+			sCtx.Assert().False(busy[client], "client %d has two trainings at %v", booking.Client.ID, booking.Training.DateTime)
+			busy[client] = true
+		}
+	})
+}
+
+func (s *ScenarioSuite) TestBuildMoreHallsThanCoaches(t provider.T) {
+	t.Title("[Build] Halls beyond the number of coaches stay empty")
+	t.Tags("Scenario")
+	t.WithNewStep("Build", func(sCtx provider.StepCtx) {
+		scenario := postgreSQLObjectMother.NewScenario().Halls(3).Coaches(2).Build()
+
+		sCtx.Assert().Len(scenario.Halls, 3)
+		sCtx.Assert().Len(scenario.Trainings, 2*4)
+		sCtx.Assert().Empty(scenario.Bookings)
+	})
+}
+
+func (s *ScenarioSuite) TestBuildTrainingHours(t provider.T) {
+	t.Title("[Build] Trainings fill the training hours, the last one excluded")
+	t.Tags("Scenario")
+	t.WithNewStep("Build", func(sCtx provider.StepCtx) {
+		scenario := postgreSQLObjectMother.NewScenario().Halls(1).Coaches(1).TrainingsPerDay(24).Build()
+
+		hours := services.LastTrainingTime - services.FirstTrainingTime
+		sCtx.Assert().Len(scenario.Trainings, hours)
+		for _, training := range scenario.Trainings {
+			sCtx.Assert().GreaterOrEqual(training.DateTime.Hour(), services.FirstTrainingTime)
+			sCtx.Assert().Less(training.DateTime.Hour(), services.LastTrainingTime)
+		}
+	})
+}
+
+func (s *ScenarioSuite) TestPersist(t provider.T) {
+	t.Title("[Persist] The scenario is created in the repositories")
+	t.Tags("Scenario")
+	t.WithNewStep("Persist", func(sCtx provider.StepCtx) {
+		ctx := context.TODO()
+		storage := inmemory.NewStorage()
+		clientRepository := inmemory.NewClientInMemoryRepository(storage)
+		trainingRepository := inmemory.NewTrainingInMemoryRepository(storage)
+
+		scenario := postgreSQLObjectMother.NewScenario().Week(monday).Halls(3).Coaches(5).Occupancy(0.4).Build()
+		err := scenario.Persist(ctx, clientRepository, inmemory.NewCoachInMemoryRepository(storage),
+			inmemory.NewHallInMemoryRepository(storage), trainingRepository)
+		sCtx.Assert().NoError(err)
+
+		trainings, err := trainingRepository.GetAllBetweenDateTime(ctx, monday, monday.AddDate(0, 0, 7))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Len(trainings, len(scenario.Trainings))
+
+		for _, training := range trainings {
+			clients, err := clientRepository.GetByTraining(ctx, training.ID)
+			sCtx.Assert().NoError(err)
+			sCtx.Assert().Len(clients, 4)
+		}
+
+		coachTrainings, err := trainingRepository.GetAllByCoachOnDate(ctx, scenario.Coaches[0].ID, monday)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().NotEmpty(coachTrainings)
+		for _, training := range coachTrainings {
+			sCtx.Assert().Equal(scenario.Coaches[0].ID, training.CoachID)
+		}
+	})
+}
+
+func TestScenarioSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(ScenarioSuite))
+}
@@ -0,0 +1,11 @@
+package postgreSQLObjectMother
+
+import "sync/atomic"
+
+// sequence makes the defaults of the builders unique within a test binary, so
+// builders without overrides never collide on unique columns.
+var sequence atomic.Uint64
+
+func next() uint64 {
+	return sequence.Add(1)
+}
@@ -1,17 +1,82 @@
 package postgreSQLObjectMother
 
 import (
+	"fmt"
 	"time"
+
 	"github.com/nkarakotova/lim-core/models"
 )
 
 func CreateTestTraining() *models.Training {
-	return &models.Training{
-		ID: 1,
-		CoachID: 1,
-		HallID: 1,
-		Name: "Name",
-		DateTime: time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC),
+	return NewTraining().
+		WithID(1).
+		WithCoachID(1).
+		WithHallID(1).
+		WithName("Name").
+		At(time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC)).
+		WithPlacesNum(10).
+		Build()
+}
+
+// TrainingBuilder builds a training with a unique ID and name. It starts at
+// 12:00 UTC on 2024-07-07 with 10 places in hall 1 with coach 1 unless that
+// is overridden.
+type TrainingBuilder struct {
+	training models.Training
+}
+
+func NewTraining() *TrainingBuilder {
+	n := next()
+
+	return &TrainingBuilder{training: models.Training{
+		ID:        n,
+		CoachID:   1,
+		HallID:    1,
+		Name:      fmt.Sprintf("Training %d", n),
+		DateTime:  time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC),
 		PlacesNum: 10,
-	}
+	}}
+}
+
+func (b *TrainingBuilder) WithID(id uint64) *TrainingBuilder {
+	b.training.ID = id
+	return b
+}
+
+func (b *TrainingBuilder) WithCoachID(id uint64) *TrainingBuilder {
+	b.training.CoachID = id
+	return b
+}
+
+func (b *TrainingBuilder) WithCoach(coach *models.Coach) *TrainingBuilder {
+	return b.WithCoachID(coach.ID)
+}
+
+func (b *TrainingBuilder) WithHallID(id uint64) *TrainingBuilder {
+	b.training.HallID = id
+	return b
+}
+
+func (b *TrainingBuilder) WithHall(hall *models.Hall) *TrainingBuilder {
+	return b.WithHallID(hall.ID)
+}
+
+func (b *TrainingBuilder) WithName(name string) *TrainingBuilder {
+	b.training.Name = name
+	return b
+}
+
+func (b *TrainingBuilder) At(dateTime time.Time) *TrainingBuilder {
+	b.training.DateTime = dateTime
+	return b
+}
+
+func (b *TrainingBuilder) WithPlacesNum(placesNum uint64) *TrainingBuilder {
+	b.training.PlacesNum = placesNum
+	return b
+}
+
+func (b *TrainingBuilder) Build() *models.Training {
+	training := b.training
+	return &training
 }
@@ -0,0 +1,59 @@
+package postgreSQL
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// testDatabase is nil when neither TestDSNEnv nor Docker is available or the
+// tests run with -short. The integration tests are skipped then.
+var testDatabase *TestDatabase
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	ctx := context.Background()
+
+	if !testing.Short() {
+		var err error
+		testDatabase, err = SetupTestDatabase(ctx)
+		if err != nil && os.Getenv(TestDSNEnv) != "" {
+			fmt.Fprintf(os.Stderr, "error connecting to %s: %v\n", TestDSNEnv, err)
+			os.Exit(1)
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "integration tests are skipped, set %s or start Docker: %v\n", TestDSNEnv, err)
+		}
+	}
+
+	code := m.Run()
+
+	ok, err := finishBenchReport(os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error reporting benchmarks: %v\n", err)
+		code = 1
+	} else if !ok && code == 0 {
+		code = 1
+	}
+
+	if testDatabase != nil {
+		err := closeBenchmarkFixture(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error dropping benchmark schema: %v\n", err)
+		}
+
+		err = testDatabase.Close(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error closing test database: %v\n", err)
+		}
+	}
+
+	os.Exit(code)
+}
+
+func skipWithoutDatabase(t testing.TB) {
+	if testDatabase == nil {
+		t.Skipf("no test database, set %s or start Docker", TestDSNEnv)
+	}
+}
@@ -0,0 +1,98 @@
+package postgreSQL
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-repo/models"
+	"github.com/nkarakotova/lim-repo/repositories"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jinzhu/copier"
+	"github.com/jmoiron/sqlx"
+)
+
+// freeSlotsQuery builds the hourly grid of the days from $1 to $2 with
+// generate_series and keeps the (hall, start time) pairs that satisfy the
+// filter: $3 is the duration in seconds, $4 and $5 the first and the last
+// training time, $6 the minimal capacity and $7 the coach or zero. $1 and $2
+// stay timestamps, truncated to the day only for the grid, so the bounds
+// keep their hours. The days, the grid l and the coach availability are
+// those of the TimeZone of the session, the studio's; the bounds, the start
+// times s and the stored date times are in UTC.
+const freeSlotsQuery = `select h.hall_id, s.date_time
+from generate_series(date_trunc('day', ($1::timestamp at time zone 'UTC')::timestamp), date_trunc('day', ($2::timestamp at time zone 'UTC')::timestamp), interval '1 day') d(day)
+cross join generate_series(d.day + make_interval(hours => $4), d.day + make_interval(hours => $5 - 1), interval '1 hour') l(date_time)
+cross join lateral (select l.date_time::timestamptz at time zone 'UTC') s(date_time)
+cross join halls h
+where s.date_time >= $1::timestamp and s.date_time + $3 * interval '1 second' <= $2::timestamp
+and l.date_time + $3 * interval '1 second' <= d.day + make_interval(hours => $5)
+and (h.capacity is null or h.capacity >= $6)
+and not exists (select 1 from trainings t where t.hall_id = h.hall_id
+and t.date_time < s.date_time + $3 * interval '1 second' and t.date_time + interval '1 hour' > s.date_time)
+and not exists (select 1 from hall_closures c where c.hall_id = h.hall_id
+and c.start_date_time < s.date_time + $3 * interval '1 second' and c.end_date_time > s.date_time)
+and ($7 = 0 or ((not exists (select 1 from coach_availability where coach_id = $7)
+or exists (select 1 from coach_availability a where a.coach_id = $7 and a.weekday = extract(dow from l.date_time)
+and l.date_time >= d.day + make_interval(hours => a.start_hour) and l.date_time + $3 * interval '1 second' <= d.day + make_interval(hours => a.end_hour)))
+and not exists (select 1 from coach_time_off o where o.coach_id = $7
+and o.start_date_time < s.date_time + $3 * interval '1 second' and o.end_date_time > s.date_time)
+and not exists (select 1 from trainings t where t.coach_id = $7
+and t.date_time < s.date_time + $3 * interval '1 second' and t.date_time + interval '1 hour' > s.date_time)))
+order by s.date_time, h.number;`
+
+type SlotPostgreSQL struct {
+	HallID   uint64    `db:"hall_id"`
+	DateTime time.Time `db:"date_time"`
+}
+
+type SlotPostgreSQLRepository struct {
+	db                *sqlx.DB
+	txResolver        *trmsqlx.CtxGetter
+	firstTrainingTime int
+	lastTrainingTime  int
+}
+
+// NewSlotPostgreSQLRepository returns the repository offering the slots from
+// the firstTrainingTime hour until the lastTrainingTime hour, as
+// config.Config sets them.
+func NewSlotPostgreSQLRepository(db *sqlx.DB, firstTrainingTime, lastTrainingTime int) repositories.SlotRepository {
+	return &SlotPostgreSQLRepository{
+		db:                db,
+		txResolver:        trmsqlx.DefaultCtxGetter,
+		firstTrainingTime: firstTrainingTime,
+		lastTrainingTime:  lastTrainingTime,
+	}
+}
+
+// GetFree returns every (hall, start time) pair on the hourly grid of the
+// training times where the hall is big enough, not closed and not busy for
+// the whole duration, and the coach, if given, is available and has no
+// other training. The slots end by the last training time.
+func (s *SlotPostgreSQLRepository) GetFree(ctx context.Context, filter models.SlotFilter) ([]models.Slot, error) {
+	duration := filter.Duration
+	if duration == 0 {
+		duration = time.Hour
+	}
+
+	slotDB := []SlotPostgreSQL{}
+	err := s.txResolver.DefaultTrOrDB(ctx, s.db).SelectContext(ctx, &slotDB, freeSlotsQuery,
+		filter.Start.UTC(), filter.End.UTC(), duration.Seconds(), s.firstTrainingTime, s.lastTrainingTime,
+		filter.MinCapacity, filter.CoachID)
+	if err != nil {
+		return nil, err
+	}
+
+	slotModels := []models.Slot{}
+	for i := range slotDB {
+		slot := models.Slot{}
+		err = copier.Copy(&slot, &slotDB[i])
+		if err != nil {
+			return nil, err
+		}
+
+		slotModels = append(slotModels, slot)
+	}
+
+	return slotModels, nil
+}
@@ -0,0 +1,59 @@
+package postgreSQL
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-repo/models"
+	"github.com/nkarakotova/lim-repo/repositories"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+)
+
+type SlotPgxRepository struct {
+	db                trmpgx.Tr
+	txResolver        *trmpgx.CtxGetter
+	firstTrainingTime int
+	lastTrainingTime  int
+}
+
+// NewSlotPgxRepository returns the repository offering the slots from the
+// firstTrainingTime hour until the lastTrainingTime hour, as config.Config
+// sets them.
+func NewSlotPgxRepository(db trmpgx.Tr, firstTrainingTime, lastTrainingTime int) repositories.SlotRepository {
+	return &SlotPgxRepository{
+		db:                db,
+		txResolver:        trmpgx.DefaultCtxGetter,
+		firstTrainingTime: firstTrainingTime,
+		lastTrainingTime:  lastTrainingTime,
+	}
+}
+
+// GetFree returns the slots of SlotPostgreSQLRepository.GetFree.
+func (s *SlotPgxRepository) GetFree(ctx context.Context, filter models.SlotFilter) ([]models.Slot, error) {
+	duration := filter.Duration
+	if duration == 0 {
+		duration = time.Hour
+	}
+
+	rows, err := s.txResolver.DefaultTrOrDB(ctx, s.db).Query(ctx, freeSlotsQuery,
+		filter.Start.UTC(), filter.End.UTC(), duration.Seconds(), s.firstTrainingTime, s.lastTrainingTime,
+		filter.MinCapacity, filter.CoachID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slotModels := []models.Slot{}
+	for rows.Next() {
+		slot := models.Slot{}
+		err = rows.Scan(&slot.HallID, &slot.DateTime)
+		if err != nil {
+			return nil, err
+		}
+
+		slotModels = append(slotModels, slot)
+	}
+
+	return slotModels, rows.Err()
+}
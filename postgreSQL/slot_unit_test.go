@@ -0,0 +1,149 @@
+package postgreSQL
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/nkarakotova/lim-repo/models"
+	"github.com/nkarakotova/lim-repo/repositories"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type SlotSuite struct {
+	suite.Suite
+	db         *sql.DB
+	mock       sqlmock.Sqlmock
+	repository repositories.SlotRepository
+	ctx        context.Context
+}
+
+func (s *SlotSuite) BeforeEach(t provider.T) {
+	var err error
+	s.db, s.mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error creating mock database: %v", err)
+	}
+	dbx := sqlx.NewDb(s.db, "pgx")
+	s.repository = NewSlotPostgreSQLRepository(dbx, 10, 22)
+	s.ctx = context.Background()
+}
+
+func (s *SlotSuite) AfterEach(t provider.T) {
+	s.db.Close()
+}
+
+func (s *SlotSuite) TestSlotMockGetFreeSuccess(t provider.T) {
+	t.Title("SlotMockGetFree: Success")
+	t.Tags("Slot")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		start := time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC)
+		s.mock.ExpectQuery(freeSlotsQuery).
+			WithArgs(start, end, float64(5400), 10, 22, 12, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"hall_id", "date_time"}).
+				AddRow(1, time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)).
+				AddRow(2, time.Date(2024, 7, 9, 18, 0, 0, 0, time.UTC)))
+
+		slots, err := s.repository.GetFree(s.ctx, models.SlotFilter{
+			Start:       start,
+			End:         end,
+			Duration:    90 * time.Minute,
+			CoachID:     3,
+			MinCapacity: 12,
+		})
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Slot{
+			{HallID: 1, DateTime: time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)},
+			{HallID: 2, DateTime: time.Date(2024, 7, 9, 18, 0, 0, 0, time.UTC)},
+		}, slots)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *SlotSuite) TestSlotMockGetFreeWithinHours(t provider.T) {
+	t.Title("SlotMockGetFree: Bounds within the day")
+	t.Tags("Slot")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		location := time.FixedZone("MSK", 3*60*60)
+		start := time.Date(2024, 7, 8, 17, 30, 0, 0, location)
+		end := time.Date(2024, 7, 9, 14, 0, 0, 0, location)
+		s.mock.ExpectQuery(freeSlotsQuery).
+			WithArgs(time.Date(2024, 7, 8, 14, 30, 0, 0, time.UTC), time.Date(2024, 7, 9, 11, 0, 0, 0, time.UTC),
+				float64(3600), 10, 22, 0, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"hall_id", "date_time"}).
+				AddRow(1, time.Date(2024, 7, 8, 15, 0, 0, 0, time.UTC)).
+				AddRow(1, time.Date(2024, 7, 9, 10, 0, 0, 0, time.UTC)))
+
+		slots, err := s.repository.GetFree(s.ctx, models.SlotFilter{Start: start, End: end})
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Slot{
+			{HallID: 1, DateTime: time.Date(2024, 7, 8, 15, 0, 0, 0, time.UTC)},
+			{HallID: 1, DateTime: time.Date(2024, 7, 9, 10, 0, 0, 0, time.UTC)},
+		}, slots)
+		sCtx.Assert().Contains(freeSlotsQuery, "date_trunc('day', ($1::timestamp at time zone 'UTC')::timestamp)")
+		sCtx.Assert().NotContains(freeSlotsQuery, "$1::date")
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *SlotSuite) TestSlotMockGetFreeDefaultDuration(t provider.T) {
+	t.Title("SlotMockGetFree: Default duration")
+	t.Tags("Slot")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		start := time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC)
+		s.mock.ExpectQuery(freeSlotsQuery).
+			WithArgs(start, end, float64(3600), 10, 22, 0, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"hall_id", "date_time"}))
+
+		slots, err := s.repository.GetFree(s.ctx, models.SlotFilter{Start: start, End: end})
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(slots)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *SlotSuite) TestSlotMockGetFreeFailure(t provider.T) {
+	t.Title("SlotMockGetFree: Failure")
+	t.Tags("Slot")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		start := time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2024, 7, 9, 0, 0, 0, 0, time.UTC)
+		queryErr := errors.New("connection reset")
+		s.mock.ExpectQuery(freeSlotsQuery).
+			WithArgs(start, end, float64(3600), 10, 22, 0, 0).
+			WillReturnError(queryErr)
+
+		_, err := s.repository.GetFree(s.ctx, models.SlotFilter{Start: start, End: end})
+
+		sCtx.Assert().ErrorIs(err, queryErr)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestSlotSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(SlotSuite))
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/jackc/pgx/v4/stdlib"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
-	"os"
-	_ "github.com/jackc/pgx/v4/stdlib"
 )
 
 const (
@@ -16,11 +21,57 @@ const (
 	DBNAME   = "postgres"
 )
 
-func SetupTestDatabase() (testcontainers.Container, *sql.DB) {
+// TestDSNEnv names the environment variable with the DSN of a Postgres to
+// run the integration tests against. A container is started when it is not
+// set, which needs Docker.
+const TestDSNEnv = "LIM_TEST_POSTGRES_DSN"
+
+// TestDatabase is the Postgres server of the integration tests of a package.
+// Every test works in a schema of its own, see NewSchema.
+type TestDatabase struct {
+	dsn       string
+	db        *sql.DB
+	container testcontainers.Container
+	schemas   atomic.Uint64
+}
+
+// SetupTestDatabase connects to the Postgres named by TestDSNEnv or starts a
+// container with one. Call it once per package, from TestMain.
+func SetupTestDatabase(ctx context.Context) (*TestDatabase, error) {
+	database := &TestDatabase{dsn: os.Getenv(TestDSNEnv)}
+
+	if database.dsn == "" {
+		err := database.startContainer(ctx)
+		if err != nil {
+			database.Close(ctx)
+			return nil, err
+		}
+	}
+
+	db, err := sql.Open("pgx", database.dsn)
+	if err != nil {
+		database.Close(ctx)
+		return nil, err
+	}
+	database.db = db
+
+	err = db.PingContext(ctx)
+	if err != nil {
+		database.Close(ctx)
+		return nil, err
+	}
+
+	return database, nil
+}
+
+func (d *TestDatabase) startContainer(ctx context.Context) error {
 	containerReq := testcontainers.ContainerRequest{
 		Image:        "postgres:13.3",
 		ExposedPorts: []string{"5432/tcp"},
-		WaitingFor:   wait.ForListeningPort("5432/tcp"),
+		// The server is restarted once the init scripts are done.
+		WaitingFor: wait.ForLog("database system is ready to accept connections").
+			WithOccurrence(2).
+			WithStartupTimeout(time.Minute),
 		Env: map[string]string{
 			"POSTGRES_DB":       DBNAME,
 			"POSTGRES_PASSWORD": PASSWORD,
@@ -28,40 +79,117 @@ func SetupTestDatabase() (testcontainers.Container, *sql.DB) {
 		},
 	}
 
-	dbContainer, _ := testcontainers.GenericContainer(
-		context.Background(),
-		testcontainers.GenericContainerRequest{
-			ContainerRequest: containerReq,
-			Started:          true,
-		})
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: containerReq,
+		Started:          true,
+	})
+	if container != nil {
+		d.container = container
+	}
+	if err != nil {
+		return err
+	}
 
-	host, _ := dbContainer.Host(context.Background())
-	port, _ := dbContainer.MappedPort(context.Background(), "5432")
+	host, err := container.Host(ctx)
+	if err != nil {
+		return err
+	}
 
-	dsnPGConn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", host, port.Int(), USER, PASSWORD, DBNAME)
-	db, err := sql.Open("pgx", dsnPGConn)
+	port, err := container.MappedPort(ctx, "5432")
 	if err != nil {
-		fmt.Println(err)
-		return dbContainer, nil
+		return err
 	}
 
-	err = db.Ping()
+	d.dsn = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", host, port.Port(), USER, PASSWORD, DBNAME)
+
+	return nil
+}
+
+// Close stops the container, if there is one.
+func (d *TestDatabase) Close(ctx context.Context) error {
+	if d.db != nil {
+		d.db.Close()
+	}
+	if d.container != nil {
+		return d.container.Terminate(ctx)
+	}
+
+	return nil
+}
+
+// TestSchema is an empty schema with all migrations applied. DB and Pool only
+// see its tables.
+type TestSchema struct {
+	DB   *sql.DB
+	Pool *pgxpool.Pool
+
+	name     string
+	database *TestDatabase
+}
+
+// NewSchema creates a schema, applies the migrations to it and connects to it
+// with search_path. Close drops the schema.
+func (d *TestDatabase) NewSchema(ctx context.Context) (*TestSchema, error) {
+	return d.NewSchemaInTimeZone(ctx, "")
+}
+
+// NewSchemaInTimeZone is NewSchema with the sessions in timeZone, the time
+// zone of the server when empty.
+func (d *TestDatabase) NewSchemaInTimeZone(ctx context.Context, timeZone string) (*TestSchema, error) {
+	name := fmt.Sprintf("test_%d_%d", os.Getpid(), d.schemas.Add(1))
+
+	_, err := d.db.ExecContext(ctx, `create schema `+pgx.Identifier{name}.Sanitize()+`;`)
 	if err != nil {
-		fmt.Println(err)
-		return dbContainer, nil
+		return nil, err
 	}
-	db.SetMaxOpenConns(10)
+	schema := &TestSchema{name: name, database: d}
 
-	text, err := os.ReadFile("../../database/postgreSQL/init.sql")
+	connConfig, err := pgx.ParseConfig(d.dsn)
 	if err != nil {
-		fmt.Println(err)
-		return dbContainer, nil
+		schema.Close(ctx)
+		return nil, err
 	}
+	connConfig.RuntimeParams["search_path"] = name
+	if timeZone != "" {
+		connConfig.RuntimeParams["timezone"] = timeZone
+	}
+	schema.DB = stdlib.OpenDB(*connConfig)
 
-	if _, err := db.Exec(string(text)); err != nil {
-		fmt.Println(err)
-		return dbContainer, nil
+	err = Migrate(ctx, schema.DB)
+	if err != nil {
+		schema.Close(ctx)
+		return nil, err
 	}
 
-	return dbContainer, db
+	poolConfig, err := pgxpool.ParseConfig(d.dsn)
+	if err != nil {
+		schema.Close(ctx)
+		return nil, err
+	}
+	poolConfig.ConnConfig.RuntimeParams["search_path"] = name
+	if timeZone != "" {
+		poolConfig.ConnConfig.RuntimeParams["timezone"] = timeZone
+	}
+
+	schema.Pool, err = pgxpool.ConnectConfig(ctx, poolConfig)
+	if err != nil {
+		schema.Close(ctx)
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// Close disconnects from the schema and drops it with everything in it.
+func (s *TestSchema) Close(ctx context.Context) error {
+	if s.Pool != nil {
+		s.Pool.Close()
+	}
+	if s.DB != nil {
+		s.DB.Close()
+	}
+
+	_, err := s.database.db.ExecContext(ctx, `drop schema `+pgx.Identifier{s.name}.Sanitize()+` cascade;`)
+
+	return err
 }
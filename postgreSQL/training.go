@@ -5,17 +5,21 @@ import (
 	"database/sql"
 	"time"
 
-	"github.com/nkarakotova/lim-core/repositories"
-
 	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
 
 	"github.com/nkarakotova/lim-core/models"
 
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+
 	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
 	"github.com/jinzhu/copier"
 	"github.com/jmoiron/sqlx"
 )
 
+// TrainingPostgreSQL keeps DateTime in UTC. pgx writes the wall clock of a
+// time into a timestamp column and drops the location, so the repositories
+// turn every date time into UTC before it reaches a query.
 type TrainingPostgreSQL struct {
 	ID                 uint64    `db:"training_id"`
 	CoachID            uint64    `db:"coach_id"`
@@ -23,6 +27,7 @@ type TrainingPostgreSQL struct {
 	Name               string    `db:"name"`
 	DateTime           time.Time `db:"date_time"`
 	PlacesNum          uint64    `db:"places_num"`
+	AvailablePlacesNum uint64    `db:"available_places_num"`
 }
 
 type TrainingPostgreSQLRepository struct {
@@ -30,14 +35,67 @@ type TrainingPostgreSQLRepository struct {
 	txResolver *trmsqlx.CtxGetter
 }
 
-func NewTrainingPostgreSQLRepository(db *sqlx.DB) repositories.TrainingRepository {
+func NewTrainingPostgreSQLRepository(db *sqlx.DB) extRepositories.TrainingRepository {
 	return &TrainingPostgreSQLRepository{db: db, txResolver: trmsqlx.DefaultCtxGetter}
 }
 
+// dayRange returns the start of the calendar day of date in the location of
+// date and the start of the next one, both in UTC.
+func dayRange(date time.Time) (time.Time, time.Time) {
+	year, month, day := date.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+	end := time.Date(year, month, day+1, 0, 0, 0, 0, date.Location())
+
+	return start.UTC(), end.UTC()
+}
+
+// hallCapacityExceededQuery reports whether $2 places do not fit into hall $1.
+// Halls without a configured capacity accept any number of places.
+const hallCapacityExceededQuery = `select exists(select 1 from halls where hall_id=$1 and capacity < $2) as exceeded;`
+
+func (t *TrainingPostgreSQLRepository) validate(ctx context.Context, training *models.Training) error {
+	var exceeded bool
+
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).GetContext(ctx, &exceeded, hallCapacityExceededQuery, training.HallID, training.PlacesNum)
+	if err != nil {
+		return err
+	}
+	if exceeded {
+		return extRepositoriesErrors.PlacesNumMoreThenCapacity
+	}
+
+	var closed bool
+
+	err = t.txResolver.DefaultTrOrDB(ctx, t.db).GetContext(ctx, &closed, hallClosedQuery, training.HallID, training.DateTime.UTC())
+	if err != nil {
+		return err
+	}
+	if closed {
+		return extRepositoriesErrors.HallClosed
+	}
+
+	var available bool
+
+	err = t.txResolver.DefaultTrOrDB(ctx, t.db).GetContext(ctx, &available, coachAvailableQuery, training.CoachID, training.DateTime.UTC())
+	if err != nil {
+		return err
+	}
+	if !available {
+		return extRepositoriesErrors.CoachNotAvailable
+	}
+
+	return nil
+}
+
 func (t *TrainingPostgreSQLRepository) Create(ctx context.Context, training *models.Training) error {
 	query := `insert into trainings(coach_id, hall_id, name, date_time, places_num) values($1, $2, $3, $4, $5) returning training_id;`
 
-	err := t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRowxContext(ctx, query, training.CoachID, training.HallID, training.Name, training.DateTime, training.PlacesNum).Scan(&training.ID)
+	err := t.validate(ctx, training)
+	if err != nil {
+		return err
+	}
+
+	err = t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRowxContext(ctx, query, training.CoachID, training.HallID, training.Name, training.DateTime.UTC(), training.PlacesNum).Scan(&training.ID)
 	if err != nil {
 		return err
 	}
@@ -45,6 +103,30 @@ func (t *TrainingPostgreSQLRepository) Create(ctx context.Context, training *mod
 	return nil
 }
 
+func (t *TrainingPostgreSQLRepository) Reschedule(ctx context.Context, id uint64, dateTime time.Time) error {
+	query := `update trainings set date_time=$2 where training_id=$1 returning training_id;`
+
+	training, err := t.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	training.DateTime = dateTime
+	err = t.validate(ctx, training)
+	if err != nil {
+		return err
+	}
+
+	err = t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRowxContext(ctx, query, id, dateTime.UTC()).Scan(&id)
+	if err == sql.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (t *TrainingPostgreSQLRepository) Delete(ctx context.Context, id uint64) error {
 	query := `delete from trainings where training_id=$1 returning training_id;`
 
@@ -103,11 +185,15 @@ func (t *TrainingPostgreSQLRepository) GetAllByClient(ctx context.Context, id ui
 	return trainingModels, nil
 }
 
+// GetAllByCoachOnDate returns the trainings starting on the calendar day of
+// date in the location of date.
 func (t *TrainingPostgreSQLRepository) GetAllByCoachOnDate(ctx context.Context, id uint64, date time.Time) ([]models.Training, error) {
-	query := `select * from trainings where coach_id=$1 and date_time::date=$2::date;`
+	query := `select * from trainings where coach_id=$1 and date_time >= $2 and date_time < $3;`
+
+	start, end := dayRange(date)
 
 	trainingDB := []TrainingPostgreSQL{}
-	err := t.txResolver.DefaultTrOrDB(ctx, t.db).SelectContext(ctx, &trainingDB, query, id, date)
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).SelectContext(ctx, &trainingDB, query, id, start, end)
 	if err == sql.ErrNoRows {
 		return nil, repositoriesErrors.EntityDoesNotExists
 	} else if err != nil {
@@ -132,7 +218,7 @@ func (t *TrainingPostgreSQLRepository) GetAllByDateTime(ctx context.Context, dat
 	query := `select * from trainings where date_time=$1;`
 
 	trainingDB := []TrainingPostgreSQL{}
-	err := t.txResolver.DefaultTrOrDB(ctx, t.db).SelectContext(ctx, &trainingDB, query, dateTime)
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).SelectContext(ctx, &trainingDB, query, dateTime.UTC())
 	if err == sql.ErrNoRows {
 		return nil, repositoriesErrors.EntityDoesNotExists
 	} else if err != nil {
@@ -157,7 +243,7 @@ func (t *TrainingPostgreSQLRepository) GetAllBetweenDateTime(ctx context.Context
 	query := `select * from trainings where date_time between $1 and $2;`
 
 	trainingDB := []TrainingPostgreSQL{}
-	err := t.txResolver.DefaultTrOrDB(ctx, t.db).SelectContext(ctx, &trainingDB, query, start, end)
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).SelectContext(ctx, &trainingDB, query, start.UTC(), end.UTC())
 	if err == sql.ErrNoRows {
 		return nil, repositoriesErrors.EntityDoesNotExists
 	} else if err != nil {
@@ -189,13 +275,35 @@ func (t *TrainingPostgreSQLRepository) ReduceAvailablePlacesNum(ctx context.Cont
 	return nil
 }
 
-func (t *TrainingPostgreSQLRepository) IncreaseAvailablePlacesNum(ctx context.Context, id uint64) error {
-	query := `update trainings set available_places_num = available_places_num + 1 where training_id=$1 returning training_id;`
+// increasePlacesQuery gives a place back to the training, unless a late
+// cancellation holds it: then it releases the hold and the place stays
+// taken.
+const increasePlacesQuery = `with held as (update cancellations set holds_place = false
+where cancellation_id = (select cancellation_id from cancellations where training_id=$1 and holds_place order by cancellation_id limit 1) and holds_place
+returning cancellation_id)
+update trainings set available_places_num = available_places_num + case when exists (select 1 from held) then 0 else 1 end
+where training_id=$1 returning training_id;`
 
-	err := t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRowxContext(ctx, query, id).Scan(&id)
+func (t *TrainingPostgreSQLRepository) IncreaseAvailablePlacesNum(ctx context.Context, id uint64) error {
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRowxContext(ctx, increasePlacesQuery, id).Scan(&id)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// AvailablePlacesNum returns the number of free places left on the training.
+func (t *TrainingPostgreSQLRepository) AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error) {
+	query := `select available_places_num from trainings where training_id=$1;`
+
+	var available uint64
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).GetContext(ctx, &available, query, id)
+	if err == sql.ErrNoRows {
+		return 0, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return 0, err
+	}
+
+	return available, nil
+}
@@ -0,0 +1,233 @@
+package postgreSQL
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+
+	"github.com/nkarakotova/lim-core/models"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+
+	trmpgx "github.com/avito-tech/go-transaction-manager/drivers/pgxv4/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+const trainingColumns = `training_id, coach_id, hall_id, name, date_time, places_num`
+
+type TrainingPgxRepository struct {
+	db         trmpgx.Tr
+	txResolver *trmpgx.CtxGetter
+}
+
+func NewTrainingPgxRepository(db trmpgx.Tr) extRepositories.TrainingRepository {
+	return &TrainingPgxRepository{db: db, txResolver: trmpgx.DefaultCtxGetter}
+}
+
+func scanTrainings(rows pgx.Rows) ([]models.Training, error) {
+	defer rows.Close()
+
+	trainingModels := []models.Training{}
+	for rows.Next() {
+		training := models.Training{}
+		err := rows.Scan(&training.ID, &training.CoachID, &training.HallID, &training.Name, &training.DateTime, &training.PlacesNum)
+		if err != nil {
+			return nil, err
+		}
+
+		trainingModels = append(trainingModels, training)
+	}
+
+	return trainingModels, rows.Err()
+}
+
+// validate runs the checks of TrainingPostgreSQLRepository in a single round
+// trip.
+func (t *TrainingPgxRepository) validate(ctx context.Context, training *models.Training) error {
+	batch := &pgx.Batch{}
+	batch.Queue(hallCapacityExceededQuery, training.HallID, training.PlacesNum)
+	batch.Queue(hallClosedQuery, training.HallID, training.DateTime.UTC())
+	batch.Queue(coachAvailableQuery, training.CoachID, training.DateTime.UTC())
+
+	results := t.txResolver.DefaultTrOrDB(ctx, t.db).SendBatch(ctx, batch)
+	defer results.Close()
+
+	var exceeded, closed, available bool
+	err := results.QueryRow().Scan(&exceeded)
+	if err != nil {
+		return err
+	}
+	err = results.QueryRow().Scan(&closed)
+	if err != nil {
+		return err
+	}
+	err = results.QueryRow().Scan(&available)
+	if err != nil {
+		return err
+	}
+
+	if exceeded {
+		return extRepositoriesErrors.PlacesNumMoreThenCapacity
+	}
+	if closed {
+		return extRepositoriesErrors.HallClosed
+	}
+	if !available {
+		return extRepositoriesErrors.CoachNotAvailable
+	}
+
+	return nil
+}
+
+func (t *TrainingPgxRepository) Create(ctx context.Context, training *models.Training) error {
+	query := `insert into trainings(coach_id, hall_id, name, date_time, places_num) values($1, $2, $3, $4, $5) returning training_id;`
+
+	err := t.validate(ctx, training)
+	if err != nil {
+		return err
+	}
+
+	err = t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRow(ctx, query, training.CoachID, training.HallID, training.Name, training.DateTime.UTC(), training.PlacesNum).Scan(&training.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Import loads trainings with COPY without validating them. The generated
+// IDs are not written back to the trainings.
+func (t *TrainingPgxRepository) Import(ctx context.Context, trainings []models.Training) (int64, error) {
+	return t.txResolver.DefaultTrOrDB(ctx, t.db).CopyFrom(ctx,
+		pgx.Identifier{"trainings"},
+		[]string{"coach_id", "hall_id", "name", "date_time", "places_num"},
+		pgx.CopyFromSlice(len(trainings), func(i int) ([]interface{}, error) {
+			training := trainings[i]
+			return []interface{}{training.CoachID, training.HallID, training.Name, training.DateTime.UTC(), training.PlacesNum}, nil
+		}))
+}
+
+func (t *TrainingPgxRepository) Reschedule(ctx context.Context, id uint64, dateTime time.Time) error {
+	query := `update trainings set date_time=$2 where training_id=$1 returning training_id;`
+
+	training, err := t.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	training.DateTime = dateTime
+	err = t.validate(ctx, training)
+	if err != nil {
+		return err
+	}
+
+	err = t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRow(ctx, query, id, dateTime.UTC()).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *TrainingPgxRepository) Delete(ctx context.Context, id uint64) error {
+	query := `delete from trainings where training_id=$1 returning training_id;`
+
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRow(ctx, query, id).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *TrainingPgxRepository) GetByID(ctx context.Context, id uint64) (*models.Training, error) {
+	query := `select ` + trainingColumns + ` from trainings where training_id=$1;`
+
+	training := &models.Training{}
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).
+		QueryRow(ctx, query, id).
+		Scan(&training.ID, &training.CoachID, &training.HallID, &training.Name, &training.DateTime, &training.PlacesNum)
+	if err == pgx.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	return training, nil
+}
+
+func (t *TrainingPgxRepository) getAll(ctx context.Context, query string, args ...interface{}) ([]models.Training, error) {
+	rows, err := t.txResolver.DefaultTrOrDB(ctx, t.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanTrainings(rows)
+}
+
+func (t *TrainingPgxRepository) GetAllByClient(ctx context.Context, id uint64) ([]models.Training, error) {
+	query := `select ` + trainingColumns + ` from trainings where training_id in (select training_id from clients_trainings where client_id=$1);`
+
+	return t.getAll(ctx, query, id)
+}
+
+func (t *TrainingPgxRepository) GetAllByCoachOnDate(ctx context.Context, id uint64, date time.Time) ([]models.Training, error) {
+	query := `select ` + trainingColumns + ` from trainings where coach_id=$1 and date_time >= $2 and date_time < $3;`
+
+	start, end := dayRange(date)
+
+	return t.getAll(ctx, query, id, start, end)
+}
+
+func (t *TrainingPgxRepository) GetAllByDateTime(ctx context.Context, dateTime time.Time) ([]models.Training, error) {
+	query := `select ` + trainingColumns + ` from trainings where date_time=$1;`
+
+	return t.getAll(ctx, query, dateTime.UTC())
+}
+
+func (t *TrainingPgxRepository) GetAllBetweenDateTime(ctx context.Context, start time.Time, end time.Time) ([]models.Training, error) {
+	query := `select ` + trainingColumns + ` from trainings where date_time between $1 and $2;`
+
+	return t.getAll(ctx, query, start.UTC(), end.UTC())
+}
+
+func (t *TrainingPgxRepository) ReduceAvailablePlacesNum(ctx context.Context, id uint64) error {
+	query := `update trainings set available_places_num = available_places_num - 1 where training_id=$1 returning training_id;`
+
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRow(ctx, query, id).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *TrainingPgxRepository) IncreaseAvailablePlacesNum(ctx context.Context, id uint64) error {
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRow(ctx, increasePlacesQuery, id).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AvailablePlacesNum returns the number of free places left on the training.
+func (t *TrainingPgxRepository) AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error) {
+	query := `select available_places_num from trainings where training_id=$1;`
+
+	var available uint64
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRow(ctx, query, id).Scan(&available)
+	if err == pgx.ErrNoRows {
+		return 0, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return 0, err
+	}
+
+	return available, nil
+}
@@ -0,0 +1,260 @@
+package postgreSQL
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+// batchPool answers batches with the queued results, as pgxmock v1 does not
+// support pgx.Batch.
+type batchPool struct {
+	pgxmock.PgxPoolIface
+	results []interface{}
+	queued  int
+}
+
+func (p *batchPool) SendBatch(_ context.Context, b *pgx.Batch) pgx.BatchResults {
+	p.queued += b.Len()
+
+	return &batchResults{pool: p}
+}
+
+type batchResults struct {
+	pool *batchPool
+}
+
+func (r *batchResults) Exec() (pgconn.CommandTag, error) {
+	return nil, nil
+}
+
+func (r *batchResults) Query() (pgx.Rows, error) {
+	return nil, pgx.ErrNoRows
+}
+
+func (r *batchResults) QueryRow() pgx.Row {
+	if len(r.pool.results) == 0 {
+		return batchRow{err: pgx.ErrNoRows}
+	}
+
+	result := r.pool.results[0]
+	r.pool.results = r.pool.results[1:]
+
+	return batchRow{value: result}
+}
+
+func (r *batchResults) QueryFunc(_ []interface{}, _ func(pgx.QueryFuncRow) error) (pgconn.CommandTag, error) {
+	return nil, nil
+}
+
+func (r *batchResults) Close() error {
+	return nil
+}
+
+type batchRow struct {
+	value interface{}
+	err   error
+}
+
+func (r batchRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	*dest[0].(*bool) = r.value.(bool)
+
+	return nil
+}
+
+type TrainingPgxSuite struct {
+	suite.Suite
+	pool       *batchPool
+	repository extRepositories.TrainingRepository
+	ctx        context.Context
+}
+
+func (s *TrainingPgxSuite) BeforeEach(t provider.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error creating mock pool: %v", err)
+	}
+	s.pool = &batchPool{PgxPoolIface: mock}
+	s.repository = NewTrainingPgxRepository(s.pool)
+	s.ctx = context.Background()
+}
+
+func (s *TrainingPgxSuite) AfterEach(t provider.T) {
+	s.pool.Close()
+}
+
+func (s *TrainingPgxSuite) TestTrainingPgxCreateSuccess(t provider.T) {
+	t.Title("TrainingPgxCreate: Success")
+	t.Tags("Training", "Pgx")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.pool.results = []interface{}{false, false, true}
+		s.pool.ExpectQuery(`insert into trainings(coach_id, hall_id, name, date_time, places_num) values($1, $2, $3, $4, $5) returning training_id;`).
+			WithArgs(uint64(1), uint64(1), "Name", time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC), uint64(10)).
+			WillReturnRows(pgxmock.NewRows([]string{"training_id"}).AddRow(uint64(3)))
+
+		training := postgreSQLObjectMother.CreateTestTraining()
+		err := s.repository.Create(s.ctx, training)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(uint64(3), training.ID)
+		sCtx.Assert().Equal(3, s.pool.queued)
+
+		if err := s.pool.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *TrainingPgxSuite) TestTrainingPgxCreateCapacityExceeded(t provider.T) {
+	t.Title("TrainingPgxCreate: Capacity exceeded")
+	t.Tags("Training", "Pgx")
+	t.WithNewStep("Capacity exceeded", func(sCtx provider.StepCtx) {
+		s.pool.results = []interface{}{true, false, true}
+
+		err := s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestTraining())
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.PlacesNumMoreThenCapacity)
+	})
+}
+
+func (s *TrainingPgxSuite) TestTrainingPgxCreateHallClosed(t provider.T) {
+	t.Title("TrainingPgxCreate: Hall closed")
+	t.Tags("Training", "Pgx")
+	t.WithNewStep("Hall closed", func(sCtx provider.StepCtx) {
+		s.pool.results = []interface{}{false, true, true}
+
+		err := s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestTraining())
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.HallClosed)
+	})
+}
+
+func (s *TrainingPgxSuite) TestTrainingPgxCreateCoachNotAvailable(t provider.T) {
+	t.Title("TrainingPgxCreate: Coach not available")
+	t.Tags("Training", "Pgx")
+	t.WithNewStep("Coach not available", func(sCtx provider.StepCtx) {
+		s.pool.results = []interface{}{false, false, false}
+
+		err := s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestTraining())
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.CoachNotAvailable)
+	})
+}
+
+func (s *TrainingPgxSuite) TestTrainingPgxGetByIDNotFound(t provider.T) {
+	t.Title("TrainingPgxGetByID: Not found")
+	t.Tags("Training", "Pgx")
+	t.WithNewStep("Not found", func(sCtx provider.StepCtx) {
+		s.pool.ExpectQuery(`select training_id, coach_id, hall_id, name, date_time, places_num from trainings where training_id=$1;`).
+			WithArgs(uint64(1)).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := s.repository.GetByID(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		if err := s.pool.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *TrainingPgxSuite) TestTrainingPgxAvailablePlacesNumSuccess(t provider.T) {
+	t.Title("TrainingPgxAvailablePlacesNum: Success")
+	t.Tags("Training", "Pgx")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.pool.ExpectQuery(`select available_places_num from trainings where training_id=$1;`).
+			WithArgs(uint64(1)).
+			WillReturnRows(pgxmock.NewRows([]string{"available_places_num"}).AddRow(uint64(3)))
+
+		available, err := s.repository.(*TrainingPgxRepository).AvailablePlacesNum(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(uint64(3), available)
+
+		if err := s.pool.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *TrainingPgxSuite) TestTrainingPgxAvailablePlacesNumNotFound(t provider.T) {
+	t.Title("TrainingPgxAvailablePlacesNum: Not found")
+	t.Tags("Training", "Pgx")
+	t.WithNewStep("Not found", func(sCtx provider.StepCtx) {
+		s.pool.ExpectQuery(`select available_places_num from trainings where training_id=$1;`).
+			WithArgs(uint64(1)).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := s.repository.(*TrainingPgxRepository).AvailablePlacesNum(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		if err := s.pool.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *TrainingPgxSuite) TestTrainingPgxGetAllByCoachOnDateSuccess(t provider.T) {
+	t.Title("TrainingPgxGetAllByCoachOnDate: Success")
+	t.Tags("Training", "Pgx")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		date := time.Date(2024, 7, 7, 0, 0, 0, 0, time.UTC)
+		s.pool.ExpectQuery(`select training_id, coach_id, hall_id, name, date_time, places_num from trainings where coach_id=$1 and date_time >= $2 and date_time < $3;`).
+			WithArgs(uint64(1), date, date.AddDate(0, 0, 1)).
+			WillReturnRows(pgxmock.NewRows([]string{"training_id", "coach_id", "hall_id", "name", "date_time", "places_num"}).
+				AddRow(uint64(1), uint64(1), uint64(1), "Name", time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC), uint64(10)))
+
+		trainings, err := s.repository.GetAllByCoachOnDate(s.ctx, 1, date)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Len(trainings, 1)
+		sCtx.Assert().Equal(*postgreSQLObjectMother.CreateTestTraining(), trainings[0])
+
+		if err := s.pool.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *TrainingPgxSuite) TestTrainingPgxImportSuccess(t provider.T) {
+	t.Title("TrainingPgxImport: Success")
+	t.Tags("Training", "Pgx")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.pool.ExpectCopyFrom(`"trainings"`, []string{"coach_id", "hall_id", "name", "date_time", "places_num"}).
+			WillReturnResult(1)
+
+		count, err := s.repository.(*TrainingPgxRepository).Import(s.ctx, []models.Training{*postgreSQLObjectMother.CreateTestTraining()})
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(int64(1), count)
+
+		if err := s.pool.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestTrainingPgxSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(TrainingPgxSuite))
+}
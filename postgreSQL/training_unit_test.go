@@ -12,7 +12,8 @@ import (
 	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
 	"github.com/nkarakotova/lim-core/models"
 	"github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
-	"github.com/nkarakotova/lim-core/repositories"
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
 
 	"github.com/ozontech/allure-go/pkg/framework/provider"
 	"github.com/ozontech/allure-go/pkg/framework/suite"
@@ -22,7 +23,7 @@ type TrainingSuite struct {
 	suite.Suite
 	db         *sql.DB
 	mock       sqlmock.Sqlmock
-	repository repositories.TrainingRepository
+	repository extRepositories.TrainingRepository
 	ctx        context.Context
 }
 
@@ -45,6 +46,15 @@ func (s *TrainingSuite) TestTrainingMockCreateSuccess(t provider.T) {
 	t.Title("TrainingMockCreate: Success")
 	t.Tags("Training")
 	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(hallCapacityExceededQuery).
+			WithArgs(1, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"exceeded"}).AddRow(false))
+		s.mock.ExpectQuery(hallClosedQuery).
+			WithArgs(1, time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC)).
+			WillReturnRows(sqlmock.NewRows([]string{"closed"}).AddRow(false))
+		s.mock.ExpectQuery(coachAvailableQuery).
+			WithArgs(1, time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC)).
+			WillReturnRows(sqlmock.NewRows([]string{"available"}).AddRow(true))
 		s.mock.ExpectQuery(`insert into trainings(coach_id, hall_id, name, date_time, places_num) values($1, $2, $3, $4, $5) returning training_id;`).
 			WithArgs(1, 1, "Name", time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC), 10).
 			WillReturnRows(sqlmock.NewRows([]string{"training_id"}).AddRow(1))
@@ -64,6 +74,15 @@ func (s *TrainingSuite) TestTrainingMockCreateFailure(t provider.T) {
 	t.Title("TrainingMockCreate: Failure")
 	t.Tags("Training")
 	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(hallCapacityExceededQuery).
+			WithArgs(1, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"exceeded"}).AddRow(false))
+		s.mock.ExpectQuery(hallClosedQuery).
+			WithArgs(1, time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC)).
+			WillReturnRows(sqlmock.NewRows([]string{"closed"}).AddRow(false))
+		s.mock.ExpectQuery(coachAvailableQuery).
+			WithArgs(1, time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC)).
+			WillReturnRows(sqlmock.NewRows([]string{"available"}).AddRow(true))
 		s.mock.ExpectQuery(`insert into trainings(coach_id, hall_id, name, date_time, places_num) values($1, $2, $3, $4, $5) returning training_id;`).
 			WithArgs(1, 1, "Name", time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC), 10)	
 
@@ -79,6 +98,131 @@ func (s *TrainingSuite) TestTrainingMockCreateFailure(t provider.T) {
 	})
 }
 
+func (s *TrainingSuite) TestTrainingMockCreateCoachNotAvailable(t provider.T) {
+	t.Title("TrainingMockCreate: Coach not available")
+	t.Tags("Training")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(hallCapacityExceededQuery).
+			WithArgs(1, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"exceeded"}).AddRow(false))
+		s.mock.ExpectQuery(hallClosedQuery).
+			WithArgs(1, time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC)).
+			WillReturnRows(sqlmock.NewRows([]string{"closed"}).AddRow(false))
+		s.mock.ExpectQuery(coachAvailableQuery).
+			WithArgs(1, time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC)).
+			WillReturnRows(sqlmock.NewRows([]string{"available"}).AddRow(false))
+
+		training := postgreSQLObjectMother.CreateTestTraining()
+		err := s.repository.Create(s.ctx, training)
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.CoachNotAvailable)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *TrainingSuite) TestTrainingMockCreatePlacesNumMoreThenCapacity(t provider.T) {
+	t.Title("TrainingMockCreate: Places num more then capacity")
+	t.Tags("Training")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(hallCapacityExceededQuery).
+			WithArgs(1, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"exceeded"}).AddRow(true))
+
+		training := postgreSQLObjectMother.CreateTestTraining()
+		err := s.repository.Create(s.ctx, training)
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.PlacesNumMoreThenCapacity)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *TrainingSuite) TestTrainingMockCreateHallClosed(t provider.T) {
+	t.Title("TrainingMockCreate: Hall closed")
+	t.Tags("Training")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(hallCapacityExceededQuery).
+			WithArgs(1, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"exceeded"}).AddRow(false))
+		s.mock.ExpectQuery(hallClosedQuery).
+			WithArgs(1, time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC)).
+			WillReturnRows(sqlmock.NewRows([]string{"closed"}).AddRow(true))
+
+		training := postgreSQLObjectMother.CreateTestTraining()
+		err := s.repository.Create(s.ctx, training)
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.HallClosed)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *TrainingSuite) TestTrainingMockRescheduleSuccess(t provider.T) {
+	t.Title("TrainingMockReschedule: Success")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`select * from trainings where training_id=$1;`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"training_id", "coach_id", "hall_id", "name", "date_time", "places_num"}).
+			AddRow(1, 1, 1, "Name", time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC), 10))
+		s.mock.ExpectQuery(hallCapacityExceededQuery).
+			WithArgs(1, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"exceeded"}).AddRow(false))
+		s.mock.ExpectQuery(hallClosedQuery).
+			WithArgs(1, time.Date(2024, 7, 8, 15, 0, 0, 0, time.UTC)).
+			WillReturnRows(sqlmock.NewRows([]string{"closed"}).AddRow(false))
+		s.mock.ExpectQuery(coachAvailableQuery).
+			WithArgs(1, time.Date(2024, 7, 8, 15, 0, 0, 0, time.UTC)).
+			WillReturnRows(sqlmock.NewRows([]string{"available"}).AddRow(true))
+		s.mock.ExpectQuery(`update trainings set date_time=$2 where training_id=$1 returning training_id;`).
+			WithArgs(1, time.Date(2024, 7, 8, 15, 0, 0, 0, time.UTC)).
+			WillReturnRows(sqlmock.NewRows([]string{"training_id"}).AddRow(1))
+
+		err := s.repository.Reschedule(s.ctx, 1, time.Date(2024, 7, 8, 15, 0, 0, 0, time.UTC))
+
+		sCtx.Assert().NoError(err)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *TrainingSuite) TestTrainingMockRescheduleCoachNotAvailable(t provider.T) {
+	t.Title("TrainingMockReschedule: Coach not available")
+	t.Tags("Training")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`select * from trainings where training_id=$1;`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"training_id", "coach_id", "hall_id", "name", "date_time", "places_num"}).
+			AddRow(1, 1, 1, "Name", time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC), 10))
+		s.mock.ExpectQuery(hallCapacityExceededQuery).
+			WithArgs(1, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"exceeded"}).AddRow(false))
+		s.mock.ExpectQuery(hallClosedQuery).
+			WithArgs(1, time.Date(2024, 7, 8, 23, 0, 0, 0, time.UTC)).
+			WillReturnRows(sqlmock.NewRows([]string{"closed"}).AddRow(false))
+		s.mock.ExpectQuery(coachAvailableQuery).
+			WithArgs(1, time.Date(2024, 7, 8, 23, 0, 0, 0, time.UTC)).
+			WillReturnRows(sqlmock.NewRows([]string{"available"}).AddRow(false))
+
+		err := s.repository.Reschedule(s.ctx, 1, time.Date(2024, 7, 8, 23, 0, 0, 0, time.UTC))
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.CoachNotAvailable)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
 func (s *TrainingSuite) TestTrainingMockDeleteSuccess(t provider.T) {
 	t.Title("TrainingMockDelete: Success")
 	t.Tags("Training")
@@ -194,8 +338,8 @@ func (s *TrainingSuite) TestTrainingMockGetAllByCoachOnDateSuccess(t provider.T)
 	t.Title("TrainingMockGetAllByCoachOnDate: Success")
 	t.Tags("Training")
 	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
-		s.mock.ExpectQuery(`select * from trainings where coach_id=$1 and date_time::date=$2::date;`).
-			WithArgs(1, time.Date(2024, 7, 7, 0, 0, 0, 0, time.UTC)).
+		s.mock.ExpectQuery(`select * from trainings where coach_id=$1 and date_time >= $2 and date_time < $3;`).
+			WithArgs(1, time.Date(2024, 7, 7, 0, 0, 0, 0, time.UTC), time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)).
 			WillReturnRows(sqlmock.NewRows([]string{"training_id", "coach_id", "hall_id", "name", "date_time", "places_num"}).
 			AddRow(1, 1, 1, "Name", time.Date(2024, 7, 7, 12, 0, 0, 0, time.UTC), 10))
 
@@ -216,8 +360,8 @@ func (s *TrainingSuite) TestTrainingMockGetAllByCoachOnDateFailure(t provider.T)
 	t.Title("TrainingMockGetAllByCoachOnDate: Failure")
 	t.Tags("Training")
 	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
-		s.mock.ExpectQuery(`select * from trainings where coach_id=$1 and date_time::date=$2::date;`).
-			WithArgs(1, time.Date(2024, 7, 7, 0, 0, 0, 0, time.UTC)).WillReturnError(sql.ErrNoRows)
+		s.mock.ExpectQuery(`select * from trainings where coach_id=$1 and date_time >= $2 and date_time < $3;`).
+			WithArgs(1, time.Date(2024, 7, 7, 0, 0, 0, 0, time.UTC), time.Date(2024, 7, 8, 0, 0, 0, 0, time.UTC)).WillReturnError(sql.ErrNoRows)
 
 		_, err := s.repository.GetAllByCoachOnDate(s.ctx, 1, time.Date(2024, 7, 7, 0, 0, 0, 0, time.UTC))
 
@@ -346,7 +490,7 @@ func (s *TrainingSuite) TestTrainingMockIncreaseAvailablePlacesNumSuccess(t prov
 	t.Title("TrainingMockIncreaseAvailablePlacesNum: Success")
 	t.Tags("Training")
 	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
-		s.mock.ExpectQuery(`update trainings set available_places_num = available_places_num + 1 where training_id=$1 returning training_id;`).
+		s.mock.ExpectQuery(increasePlacesQuery).
 			WithArgs(1).
 			WillReturnRows(sqlmock.NewRows([]string{"training_id"}).
 			AddRow(1))
@@ -365,7 +509,7 @@ func (s *TrainingSuite) TestTrainingMockIncreaseAvailablePlacesNumFailure(t prov
 	t.Title("TrainingMockIncreaseAvailablePlacesNum: Failure")
 	t.Tags("Training")
 	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
-		s.mock.ExpectQuery(`update trainings set available_places_num = available_places_num + 1 where training_id=$1 returning training_id;`).WithArgs(1)
+		s.mock.ExpectQuery(increasePlacesQuery).WithArgs(1)
 		
 		err := s.repository.IncreaseAvailablePlacesNum(s.ctx, 1)
 
@@ -377,6 +521,42 @@ func (s *TrainingSuite) TestTrainingMockIncreaseAvailablePlacesNumFailure(t prov
 	})
 }
 
+func (s *TrainingSuite) TestTrainingMockAvailablePlacesNumSuccess(t provider.T) {
+	t.Title("TrainingMockAvailablePlacesNum: Success")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`select available_places_num from trainings where training_id=$1;`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"available_places_num"}).
+			AddRow(3))
+
+		available, err := s.repository.(*TrainingPostgreSQLRepository).AvailablePlacesNum(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(uint64(3), available)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func (s *TrainingSuite) TestTrainingMockAvailablePlacesNumFailure(t provider.T) {
+	t.Title("TrainingMockAvailablePlacesNum: Failure")
+	t.Tags("Training")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		s.mock.ExpectQuery(`select available_places_num from trainings where training_id=$1;`).WithArgs(1).WillReturnError(sql.ErrNoRows)
+
+		_, err := s.repository.(*TrainingPostgreSQLRepository).AvailablePlacesNum(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		if err := s.mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
 func TestTrainingSuiteRunner(t *testing.T) {
 	suite.RunSuite(t, new(TrainingSuite))
 }
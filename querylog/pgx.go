@@ -0,0 +1,79 @@
+package querylog
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jackc/pgx/v4"
+)
+
+// statementMessages are the messages pgx logs a statement with.
+var statementMessages = map[string]bool{
+	"Query":     true,
+	"Exec":      true,
+	"CopyFrom":  true,
+	"SendBatch": true,
+}
+
+type PgxLogger struct {
+	logger    *log.Logger
+	config    Config
+	sensitive sensitiveParams
+}
+
+// NewPgxLogger returns the logger of a pgxpool.Pool, set as
+// ConnConfig.Logger with ConnConfig.LogLevel pgx.LogLevelInfo. The
+// statements are logged like the ones of NewHook, the other messages of pgx
+// at debug level unless they are warnings or errors.
+func NewPgxLogger(logger *log.Logger, config Config) *PgxLogger {
+	return &PgxLogger{logger: logger, config: config}
+}
+
+func (l *PgxLogger) Log(_ context.Context, level pgx.LogLevel, msg string, data map[string]interface{}) {
+	if !statementMessages[msg] {
+		l.logOther(level, msg, data)
+		return
+	}
+
+	duration, _ := data["time"].(time.Duration)
+	slow := l.config.SlowThreshold > 0 && duration >= l.config.SlowThreshold
+	if !slow && l.logger.GetLevel() > log.DebugLevel {
+		return
+	}
+
+	keyvals := []interface{}{"duration", duration}
+	if query, ok := data["sql"].(string); ok {
+		args, _ := data["args"].([]interface{})
+		keyvals = append(keyvals, "statement", oneLine(query), "args", l.sensitive.redact(query, args))
+	}
+	if err, ok := data["err"]; ok {
+		keyvals = append(keyvals, "err", err)
+	} else if rows, ok := data["rowCount"]; ok {
+		keyvals = append(keyvals, "rows", rows)
+	} else if tag, ok := data["commandTag"]; ok {
+		keyvals = append(keyvals, "command_tag", tag)
+	}
+
+	if slow {
+		l.logger.Warn("QUERY! Slow "+msg, keyvals...)
+	} else {
+		l.logger.Debug("QUERY! "+msg, keyvals...)
+	}
+}
+
+func (l *PgxLogger) logOther(level pgx.LogLevel, msg string, data map[string]interface{}) {
+	keyvals := make([]interface{}, 0, 2*len(data))
+	for key, value := range data {
+		keyvals = append(keyvals, key, value)
+	}
+
+	switch {
+	case level == pgx.LogLevelError:
+		l.logger.Error("PGX! "+msg, keyvals...)
+	case level == pgx.LogLevelWarn:
+		l.logger.Warn("PGX! "+msg, keyvals...)
+	default:
+		l.logger.Debug("PGX! "+msg, keyvals...)
+	}
+}
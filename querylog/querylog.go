@@ -0,0 +1,105 @@
+// Package querylog logs the statements of the repositories with the
+// charmbracelet logger. Every statement is logged at debug level with its
+// duration and arguments, passwords and mails redacted. A statement slower
+// than Config.SlowThreshold is logged at warn level, with its plan when
+// asked for.
+//
+// NewHook is the sqlhook of the sqlx repositories, NewPgxLogger the logger
+// of a pgxpool.Pool; the flags package sets them up from the configuration.
+package querylog
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/nkarakotova/lim-repo/sqlhook"
+)
+
+type Config struct {
+	// SlowThreshold is the duration from which a statement is logged at
+	// warn level, zero logs none.
+	SlowThreshold time.Duration
+	// Explain logs the plan of every slow statement, see WithExplain for a
+	// single context. The statements within a transaction and those of a
+	// pgxpool.Pool are not explained.
+	Explain bool
+}
+
+// Enabled reports whether statements would be logged at all, so the
+// statements of the database need not be hooked otherwise.
+func (c Config) Enabled(logger *log.Logger) bool {
+	return c.SlowThreshold > 0 || logger.GetLevel() <= log.DebugLevel
+}
+
+type explainKey struct{}
+
+// WithExplain logs the plan of the slow statements run with ctx, whatever
+// Config.Explain is.
+func WithExplain(ctx context.Context) context.Context {
+	return context.WithValue(ctx, explainKey{}, true)
+}
+
+func explain(ctx context.Context, config Config) bool {
+	requested, _ := ctx.Value(explainKey{}).(bool)
+
+	return config.Explain || requested
+}
+
+type Hook struct {
+	logger    *log.Logger
+	config    Config
+	sensitive sensitiveParams
+}
+
+// NewHook returns the hook that logs the statements of a database.
+func NewHook(logger *log.Logger, config Config) *Hook {
+	return &Hook{logger: logger, config: config}
+}
+
+func (h *Hook) After(ctx context.Context, event sqlhook.Event) {
+	statement := event.Kind == sqlhook.Query || event.Kind == sqlhook.Exec
+	slow := statement && h.config.SlowThreshold > 0 && event.Duration() >= h.config.SlowThreshold
+	if !slow && h.logger.GetLevel() > log.DebugLevel {
+		return
+	}
+
+	keyvals := []interface{}{"duration", event.Duration()}
+	if statement {
+		args := make([]interface{}, len(event.Args))
+		for i, arg := range event.Args {
+			args[i] = arg.Value
+		}
+		keyvals = append(keyvals, "statement", oneLine(event.Query), "args", h.sensitive.redact(event.Query, args))
+	}
+	switch {
+	case event.Err != nil:
+		keyvals = append(keyvals, "err", event.Err)
+	case event.Kind == sqlhook.Query:
+		keyvals = append(keyvals, "rows", event.Rows)
+	case event.Kind == sqlhook.Exec && event.RowsAffected >= 0:
+		keyvals = append(keyvals, "rows_affected", event.RowsAffected)
+	}
+
+	if !slow {
+		h.logger.Debug("QUERY! "+event.Kind.String(), keyvals...)
+		return
+	}
+
+	if event.Err == nil && explain(ctx, h.config) {
+		plan, err := event.Explain(ctx)
+		if err != nil {
+			keyvals = append(keyvals, "explain_err", err)
+		} else {
+			keyvals = append(keyvals, "plan", plan)
+		}
+	}
+	h.logger.Warn("QUERY! Slow "+event.Kind.String(), keyvals...)
+}
+
+// oneLine collapses the whitespace of a query.
+func oneLine(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
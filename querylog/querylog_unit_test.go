@@ -0,0 +1,170 @@
+package querylog_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/nkarakotova/lim-repo/querylog"
+	"github.com/nkarakotova/lim-repo/sqlhook"
+	"github.com/nkarakotova/lim-repo/sqlite"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+const insertClient = `insert into clients(name, telephone, mail, password) values($1, $2, $3, $4);`
+
+type QueryLogSuite struct {
+	suite.Suite
+	output bytes.Buffer
+	ctx    context.Context
+}
+
+func (s *QueryLogSuite) BeforeEach(t provider.T) {
+	s.output.Reset()
+	s.ctx = context.Background()
+}
+
+func (s *QueryLogSuite) openDB(t provider.T, level log.Level, config querylog.Config) *sql.DB {
+	logger := log.NewWithOptions(&s.output, log.Options{Level: level})
+	db, err := sqlhook.OpenDB("sqlite", "file::memory:?_pragma=foreign_keys(1)", querylog.NewHook(logger, config))
+	if err != nil {
+		t.Fatalf("error opening logged database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	err = sqlite.Migrate(s.ctx, db)
+	if err != nil {
+		t.Fatalf("error applying migrations: %v", err)
+	}
+	s.output.Reset()
+
+	return db
+}
+
+func (s *QueryLogSuite) TestDebug(t provider.T) {
+	t.Title("QueryLog: Statements are logged at debug level, passwords and mails redacted")
+	t.Tags("QueryLog")
+	t.WithNewStep("Debug", func(sCtx provider.StepCtx) {
+		db := s.openDB(t, log.DebugLevel, querylog.Config{})
+		defer db.Close()
+
+		_, err := db.ExecContext(s.ctx, insertClient, "Ivan", "+79990001122", "ivan@mail.ru", "secret")
+		sCtx.Require().NoError(err)
+
+		output := s.output.String()
+		sCtx.Assert().Contains(output, "QUERY! EXEC")
+		sCtx.Assert().Contains(output, "insert into clients")
+		sCtx.Assert().Contains(output, "Ivan")
+		sCtx.Assert().Contains(output, "+79990001122")
+		sCtx.Assert().Contains(output, "rows_affected=1")
+		sCtx.Assert().NotContains(output, "ivan@mail.ru")
+		sCtx.Assert().NotContains(output, "secret")
+		sCtx.Assert().NotContains(output, "WARN")
+	})
+}
+
+func (s *QueryLogSuite) TestLevel(t provider.T) {
+	t.Title("QueryLog: Nothing is logged above debug level without a threshold")
+	t.Tags("QueryLog")
+	t.WithNewStep("Info", func(sCtx provider.StepCtx) {
+		logger := log.NewWithOptions(&s.output, log.Options{Level: log.InfoLevel})
+		sCtx.Assert().False(querylog.Config{}.Enabled(logger))
+		sCtx.Assert().True(querylog.Config{SlowThreshold: time.Second}.Enabled(logger))
+
+		db := s.openDB(t, log.InfoLevel, querylog.Config{SlowThreshold: time.Hour})
+		defer db.Close()
+
+		_, err := db.ExecContext(s.ctx, insertClient, "Ivan", "+79990001122", "ivan@mail.ru", "secret")
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Empty(s.output.String())
+	})
+}
+
+func (s *QueryLogSuite) TestSlow(t provider.T) {
+	t.Title("QueryLog: Slow statements are logged at warn level with their plan")
+	t.Tags("QueryLog")
+	t.WithNewStep("Explain", func(sCtx provider.StepCtx) {
+		db := s.openDB(t, log.WarnLevel, querylog.Config{SlowThreshold: time.Nanosecond, Explain: true})
+		defer db.Close()
+
+		rows, err := db.QueryContext(s.ctx, `select name from coaches where coach_id = $1;`, 1)
+		sCtx.Require().NoError(err)
+		sCtx.Require().NoError(rows.Close())
+
+		output := s.output.String()
+		sCtx.Assert().Contains(output, "WARN")
+		sCtx.Assert().Contains(output, "QUERY! Slow QUERY")
+		sCtx.Assert().Contains(output, "plan=")
+		sCtx.Assert().Contains(output, "coaches")
+	})
+	t.WithNewStep("Explain on demand", func(sCtx provider.StepCtx) {
+		s.output.Reset()
+		db := s.openDB(t, log.WarnLevel, querylog.Config{SlowThreshold: time.Nanosecond})
+		defer db.Close()
+
+		_, err := db.ExecContext(s.ctx, `delete from coaches where coach_id = $1;`, 1)
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Contains(s.output.String(), "QUERY! Slow EXEC")
+		sCtx.Assert().NotContains(s.output.String(), "plan=")
+
+		s.output.Reset()
+		_, err = db.ExecContext(querylog.WithExplain(s.ctx), `delete from coaches where coach_id = $1;`, 1)
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Contains(s.output.String(), "plan=")
+	})
+}
+
+func (s *QueryLogSuite) TestPgxLogger(t provider.T) {
+	t.Title("QueryLog: The statements of pgx are logged like the hooked ones")
+	t.Tags("QueryLog")
+	t.WithNewStep("Debug", func(sCtx provider.StepCtx) {
+		logger := log.NewWithOptions(&s.output, log.Options{Level: log.DebugLevel})
+		pgxLogger := querylog.NewPgxLogger(logger, querylog.Config{})
+
+		pgxLogger.Log(s.ctx, pgx.LogLevelInfo, "Exec", map[string]interface{}{
+			"sql":        insertClient,
+			"args":       []interface{}{"Ivan", "+79990001122", "ivan@mail.ru", "secret"},
+			"time":       time.Millisecond,
+			"commandTag": "INSERT 0 1",
+		})
+
+		output := s.output.String()
+		sCtx.Assert().Contains(output, "QUERY! Exec")
+		sCtx.Assert().Contains(output, "Ivan")
+		sCtx.Assert().NotContains(output, "ivan@mail.ru")
+		sCtx.Assert().NotContains(output, "secret")
+	})
+	t.WithNewStep("Slow", func(sCtx provider.StepCtx) {
+		s.output.Reset()
+		logger := log.NewWithOptions(&s.output, log.Options{Level: log.InfoLevel})
+		pgxLogger := querylog.NewPgxLogger(logger, querylog.Config{SlowThreshold: 10 * time.Millisecond})
+
+		pgxLogger.Log(s.ctx, pgx.LogLevelInfo, "Query", map[string]interface{}{
+			"sql":      `select * from clients where mail = $1;`,
+			"args":     []interface{}{"ivan"},
+			"time":     time.Millisecond,
+			"rowCount": 1,
+		})
+		sCtx.Assert().Empty(s.output.String())
+
+		pgxLogger.Log(s.ctx, pgx.LogLevelInfo, "Query", map[string]interface{}{
+			"sql":      `select * from clients where mail = $1;`,
+			"args":     []interface{}{"ivan"},
+			"time":     time.Second,
+			"rowCount": 1,
+		})
+		sCtx.Assert().Contains(s.output.String(), "QUERY! Slow Query")
+		sCtx.Assert().NotContains(s.output.String(), "ivan")
+	})
+}
+
+func TestQueryLogSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(QueryLogSuite))
+}
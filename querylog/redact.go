@@ -0,0 +1,103 @@
+package querylog
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const redacted = "[REDACTED]"
+
+var sensitiveColumns = map[string]bool{
+	"password": true,
+	"mail":     true,
+	"email":    true,
+}
+
+var (
+	// comparisonPattern matches a column compared with or set to a bind
+	// parameter: mail = $1, c.password=$2.
+	comparisonPattern = regexp.MustCompile(`(?i)([a-z_][a-z0-9_.]*)\s*(?:=|<>|!=|\blike\b|\bilike\b)\s*\$(\d+)`)
+	// insertPattern matches the columns and values of an insert.
+	insertPattern = regexp.MustCompile(`(?is)insert\s+into\s+[a-z0-9_."]+\s*\(([^)]*)\)\s*values\s*(.*)`)
+	paramPattern  = regexp.MustCompile(`\$(\d+)`)
+	// mailPattern catches mails bound to a column it did not recognize.
+	mailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// sensitiveParams caches the parameters of a query bound to a sensitive
+// column, by 1-based position.
+type sensitiveParams struct {
+	byQuery sync.Map
+}
+
+func (s *sensitiveParams) of(query string) map[int]bool {
+	if cached, ok := s.byQuery.Load(query); ok {
+		return cached.(map[int]bool)
+	}
+
+	params := map[int]bool{}
+	for _, match := range comparisonPattern.FindAllStringSubmatch(query, -1) {
+		if sensitiveColumns[column(match[1])] {
+			params[atoi(match[2])] = true
+		}
+	}
+
+	if match := insertPattern.FindStringSubmatch(query); match != nil {
+		columns := strings.Split(match[1], ",")
+		// A value is a single parameter in the inserts of the
+		// repositories, one row after another.
+		for i, param := range paramPattern.FindAllStringSubmatch(match[2], -1) {
+			if sensitiveColumns[column(columns[i%len(columns)])] {
+				params[atoi(param[1])] = true
+			}
+		}
+	}
+
+	s.byQuery.Store(query, params)
+
+	return params
+}
+
+// redact returns args with the values of the sensitive parameters and the
+// mails replaced.
+func (s *sensitiveParams) redact(query string, args []interface{}) []interface{} {
+	params := s.of(query)
+
+	redactedArgs := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch value := arg.(type) {
+		case string:
+			if mailPattern.MatchString(value) {
+				arg = redacted
+			}
+		case []byte:
+			if mailPattern.Match(value) {
+				arg = redacted
+			}
+		}
+		if params[i+1] {
+			arg = redacted
+		}
+		redactedArgs[i] = arg
+	}
+
+	return redactedArgs
+}
+
+// column is the unquoted name of a column without its table.
+func column(name string) string {
+	name = strings.ToLower(strings.Trim(strings.TrimSpace(name), `"`))
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+
+	return n
+}
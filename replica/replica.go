@@ -0,0 +1,278 @@
+// Package replica sends the schedule browsing reads of the repositories to
+// read replicas of the database.
+//
+// A Router is shared by the repositories of a database. It picks a replica,
+// in turn, whose replication lag is at most Settings.MaxLag. A read within a
+// transaction stays on the primary, and so do the reads of a client for
+// Settings.StickyWindow after it booked or changed anything, so that it sees
+// its own writes; the client is named with WithClient. A read failed on a
+// replica is run again on the primary and the replica is left out for
+// Settings.FailureBackoff. WithPrimary sends any read to the primary:
+//
+//	router := replica.NewRouter(replicaDBs, replica.Settings{MaxLag: time.Second}, logger)
+//	trainings := replica.NewTrainingRepository(primary, replicas, router)
+//	trainings.GetAllByDateTime(replica.WithClient(ctx, clientID), dateTime)
+package replica
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/nkarakotova/lim-repo/internal/txctx"
+)
+
+// ErrNothingReplayed is the lag error of a replica that has not replayed a
+// transaction yet.
+var ErrNothingReplayed = errors.New("replica has not replayed a transaction yet")
+
+type Settings struct {
+	// MaxLag is the replication lag from which a replica is not read.
+	MaxLag time.Duration
+	// CheckInterval is how long the lag of a replica is trusted.
+	CheckInterval time.Duration
+	// StickyWindow is how long the reads of a client stay on the primary
+	// after its write, MaxLag and CheckInterval by default: the most a
+	// replica may lag behind it.
+	StickyWindow time.Duration
+	// FailureBackoff is how long a failed replica is not read.
+	FailureBackoff time.Duration
+	// Lag measures the replication lag of a replica, Lag by default.
+	Lag func(ctx context.Context, db *sql.DB) (time.Duration, error)
+}
+
+var DefaultSettings = Settings{
+	MaxLag:         time.Second,
+	CheckInterval:  time.Second,
+	FailureBackoff: 5 * time.Second,
+	Lag:            Lag,
+}
+
+func (s Settings) withDefaults() Settings {
+	if s.MaxLag <= 0 {
+		s.MaxLag = DefaultSettings.MaxLag
+	}
+	if s.CheckInterval <= 0 {
+		s.CheckInterval = DefaultSettings.CheckInterval
+	}
+	if s.StickyWindow <= 0 {
+		s.StickyWindow = s.MaxLag + s.CheckInterval
+	}
+	if s.FailureBackoff <= 0 {
+		s.FailureBackoff = DefaultSettings.FailureBackoff
+	}
+	if s.Lag == nil {
+		s.Lag = DefaultSettings.Lag
+	}
+
+	return s
+}
+
+// Lag returns the replication lag of a Postgres replica: zero once it
+// replayed all it received, the age of the last replayed transaction
+// otherwise.
+func Lag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	var seconds sql.NullFloat64
+	query := `select case when pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() then 0
+		else extract(epoch from now() - pg_last_xact_replay_timestamp()) end;`
+	err := db.QueryRowContext(ctx, query).Scan(&seconds)
+	if err != nil {
+		return 0, err
+	}
+	if !seconds.Valid {
+		return 0, ErrNothingReplayed
+	}
+
+	return time.Duration(seconds.Float64 * float64(time.Second)), nil
+}
+
+type replica struct {
+	name string
+	db   *sql.DB
+
+	mutex       sync.Mutex
+	lag         time.Duration
+	checkedAt   time.Time
+	failedUntil time.Time
+	// checking tells that a caller measures the lag, the others go by the
+	// last lag measured meanwhile.
+	checking bool
+}
+
+type Router struct {
+	settings Settings
+	logger   *log.Logger
+	replicas []*replica
+	turn     atomic.Uint32
+
+	mutex  sync.Mutex
+	writes map[uint64]time.Time
+}
+
+// NewRouter returns the router of the replicas dbs, in the order of the
+// replica repositories.
+func NewRouter(dbs []*sql.DB, settings Settings, logger *log.Logger) *Router {
+	r := &Router{
+		settings: settings.withDefaults(),
+		logger:   logger,
+		writes:   map[uint64]time.Time{},
+	}
+	for i, db := range dbs {
+		r.replicas = append(r.replicas, &replica{name: fmt.Sprintf("replica%d", i), db: db})
+	}
+
+	return r
+}
+
+type clientKey struct{}
+
+// WithClient names the client the calls with ctx are made for.
+func WithClient(ctx context.Context, clientID uint64) context.Context {
+	return context.WithValue(ctx, clientKey{}, clientID)
+}
+
+func clientOf(ctx context.Context) (uint64, bool) {
+	clientID, ok := ctx.Value(clientKey{}).(uint64)
+
+	return clientID, ok
+}
+
+type primaryKey struct{}
+
+// WithPrimary sends the reads with ctx to the primary.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// wrote makes the reads of the clients and of the client of ctx stick to
+// the primary.
+func (r *Router) wrote(ctx context.Context, clientIDs ...uint64) {
+	if clientID, ok := clientOf(ctx); ok {
+		clientIDs = append(clientIDs, clientID)
+	}
+	if len(clientIDs) == 0 {
+		return
+	}
+
+	now := time.Now()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for clientID, at := range r.writes {
+		if now.Sub(at) >= r.settings.StickyWindow {
+			delete(r.writes, clientID)
+		}
+	}
+	for _, clientID := range clientIDs {
+		r.writes[clientID] = now
+	}
+}
+
+// Sticky reports whether the reads with ctx stay on the primary to see the
+// writes of their client.
+func (r *Router) Sticky(ctx context.Context) bool {
+	clientID, ok := clientOf(ctx)
+	if !ok {
+		return false
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	at, ok := r.writes[clientID]
+
+	return ok && time.Since(at) < r.settings.StickyWindow
+}
+
+// pick returns the index of the replica to read from, -1 for the primary.
+func (r *Router) pick(ctx context.Context) int {
+	if len(r.replicas) == 0 || txctx.InTransaction(ctx) || ctx.Value(primaryKey{}) != nil || r.Sticky(ctx) {
+		return -1
+	}
+
+	start := int(r.turn.Add(1))
+	for k := range r.replicas {
+		i := (start + k) % len(r.replicas)
+		if r.usable(ctx, r.replicas[i]) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// usable measures the lag of replica once CheckInterval passed, without
+// holding its lock over the query. A replica never measured is not read
+// until its lag is known.
+func (r *Router) usable(ctx context.Context, replica *replica) bool {
+	replica.mutex.Lock()
+	now := time.Now()
+	if now.Before(replica.failedUntil) {
+		replica.mutex.Unlock()
+		return false
+	}
+	check := now.Sub(replica.checkedAt) >= r.settings.CheckInterval && !replica.checking
+	if !check {
+		usable := !replica.checkedAt.IsZero() && replica.lag <= r.settings.MaxLag
+		replica.mutex.Unlock()
+		return usable
+	}
+	replica.checking = true
+	replica.mutex.Unlock()
+
+	lag, err := r.settings.Lag(ctx, replica.db)
+
+	replica.mutex.Lock()
+	defer replica.mutex.Unlock()
+
+	replica.checking = false
+	if err != nil {
+		if ctx.Err() == nil {
+			r.fail(replica, err)
+		}
+		return false
+	}
+	replica.lag, replica.checkedAt = lag, time.Now()
+
+	return lag <= r.settings.MaxLag
+}
+
+// fail is called with the lock of replica held.
+func (r *Router) fail(replica *replica, err error) {
+	replica.failedUntil = time.Now().Add(r.settings.FailureBackoff)
+	replica.checkedAt = time.Time{}
+	r.logger.Warn("REPLICA! Fall back to the primary", "replica", replica.name, "err", err)
+}
+
+func (r *Router) failed(ctx context.Context, i int, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	replica := r.replicas[i]
+	replica.mutex.Lock()
+	defer replica.mutex.Unlock()
+
+	r.fail(replica, err)
+}
+
+// read runs fromReplica on a replica and fromPrimary when no replica can or
+// could serve the read.
+func read[T any](ctx context.Context, r *Router, fromPrimary func() (T, error), fromReplica func(i int) (T, error)) (T, error) {
+	if i := r.pick(ctx); i >= 0 {
+		result, err := fromReplica(i)
+		if err == nil || ctx.Err() != nil {
+			return result, err
+		}
+		r.failed(ctx, i, err)
+	}
+
+	return fromPrimary()
+}
@@ -0,0 +1,297 @@
+package replica_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/charmbracelet/log"
+	"github.com/nkarakotova/lim-core/managers"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	"github.com/nkarakotova/lim-repo/replica"
+	"github.com/nkarakotova/lim-repo/sqlite"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+// schedule answers GetAllByDateTime with a training of its id, or fails it
+// with err.
+type schedule struct {
+	repositories.TrainingRepository
+	id    uint64
+	err   error
+	calls int
+}
+
+func (s *schedule) GetAllByDateTime(ctx context.Context, dateTime time.Time) ([]models.Training, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return []models.Training{{ID: s.id, DateTime: dateTime}}, nil
+}
+
+// assignments accepts every assignment.
+type assignments struct {
+	repositories.ClientRepository
+}
+
+func (a *assignments) CreateAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	return nil
+}
+
+// lags reports lag for every replica, or fails with err, holding the
+// measure until release when it is set.
+type lags struct {
+	mutex   sync.Mutex
+	lag     time.Duration
+	err     error
+	calls   int
+	release chan struct{}
+}
+
+func (l *lags) measure(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	l.mutex.Lock()
+	l.calls++
+	release := l.release
+	l.mutex.Unlock()
+
+	if release != nil {
+		<-release
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.lag, l.err
+}
+
+func (l *lags) count() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.calls
+}
+
+type ReplicaSuite struct {
+	suite.Suite
+	output    bytes.Buffer
+	lags      *lags
+	primary   *schedule
+	replicas  []*schedule
+	router    *replica.Router
+	trainings repositories.TrainingRepository
+	clients   repositories.ClientRepository
+	dateTime  time.Time
+	ctx       context.Context
+}
+
+func (s *ReplicaSuite) BeforeEach(t provider.T) {
+	s.output.Reset()
+	s.lags = &lags{}
+	s.primary = &schedule{id: 1}
+	s.replicas = []*schedule{{id: 2}, {id: 3}}
+	s.router = replica.NewRouter([]*sql.DB{nil, nil}, replica.Settings{
+		MaxLag:         time.Second,
+		CheckInterval:  time.Hour,
+		StickyWindow:   time.Hour,
+		FailureBackoff: time.Hour,
+		Lag:            s.lags.measure,
+	}, log.NewWithOptions(&s.output, log.Options{Level: log.InfoLevel}))
+	s.trainings = replica.NewTrainingRepository(s.primary, []repositories.TrainingRepository{s.replicas[0], s.replicas[1]}, s.router)
+	s.clients = replica.NewClientRepository(&assignments{}, s.router)
+	s.dateTime = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	s.ctx = context.Background()
+}
+
+// from returns the id of the repository that answered a read with ctx.
+func (s *ReplicaSuite) from(sCtx provider.StepCtx, ctx context.Context) uint64 {
+	trainings, err := s.trainings.GetAllByDateTime(ctx, s.dateTime)
+	sCtx.Require().NoError(err)
+	sCtx.Require().Len(trainings, 1)
+
+	return trainings[0].ID
+}
+
+func (s *ReplicaSuite) TestRoute(t provider.T) {
+	t.Title("Replica: Schedule reads go to the replicas in turn")
+	t.Tags("Replica")
+	t.WithNewStep("Replicas", func(sCtx provider.StepCtx) {
+		first, second := s.from(sCtx, s.ctx), s.from(sCtx, s.ctx)
+
+		sCtx.Assert().ElementsMatch([]uint64{2, 3}, []uint64{first, second})
+		sCtx.Assert().Equal(0, s.primary.calls)
+		sCtx.Assert().Equal(2, s.lags.calls)
+
+		s.from(sCtx, s.ctx)
+		sCtx.Assert().Equal(2, s.lags.calls)
+	})
+}
+
+func (s *ReplicaSuite) TestLag(t provider.T) {
+	t.Title("Replica: Replicas lagging more than the maximum are not read")
+	t.Tags("Replica")
+	t.WithNewStep("Lagging", func(sCtx provider.StepCtx) {
+		s.lags.lag = 2 * time.Second
+
+		sCtx.Assert().Equal(uint64(1), s.from(sCtx, s.ctx))
+		sCtx.Assert().Equal(0, s.replicas[0].calls+s.replicas[1].calls)
+	})
+	t.WithNewStep("Nothing replayed", func(sCtx provider.StepCtx) {
+		s.BeforeEach(t)
+		s.lags.err = replica.ErrNothingReplayed
+
+		sCtx.Assert().Equal(uint64(1), s.from(sCtx, s.ctx))
+		sCtx.Assert().Contains(s.output.String(), "REPLICA! Fall back")
+	})
+}
+
+func (s *ReplicaSuite) TestFallback(t provider.T) {
+	t.Title("Replica: A read failed on a replica is run on the primary")
+	t.Tags("Replica")
+	t.WithNewStep("Fallback", func(sCtx provider.StepCtx) {
+		for _, replica := range s.replicas {
+			replica.err = errors.New("connection refused")
+		}
+
+		sCtx.Assert().Equal(uint64(1), s.from(sCtx, s.ctx))
+		sCtx.Assert().Contains(s.output.String(), "REPLICA! Fall back")
+
+		// The failed replica is left out, the other one is tried next.
+		sCtx.Assert().Equal(uint64(1), s.from(sCtx, s.ctx))
+		sCtx.Assert().Equal(1, s.replicas[0].calls)
+		sCtx.Assert().Equal(1, s.replicas[1].calls)
+
+		sCtx.Assert().Equal(uint64(1), s.from(sCtx, s.ctx))
+		sCtx.Assert().Equal(2, s.replicas[0].calls+s.replicas[1].calls)
+	})
+	t.WithNewStep("Canceled", func(sCtx provider.StepCtx) {
+		s.BeforeEach(t)
+		ctx, cancel := context.WithCancel(s.ctx)
+		cancel()
+		s.replicas[0].err, s.replicas[1].err = context.Canceled, context.Canceled
+
+		_, err := s.trainings.GetAllByDateTime(ctx, s.dateTime)
+
+		sCtx.Assert().ErrorIs(err, context.Canceled)
+		sCtx.Assert().Equal(0, s.primary.calls)
+		sCtx.Assert().NotContains(s.output.String(), "REPLICA! Fall back")
+	})
+}
+
+func (s *ReplicaSuite) TestSticky(t provider.T) {
+	t.Title("Replica: A client reads from the primary after its booking")
+	t.Tags("Replica")
+	t.WithNewStep("Booking", func(sCtx provider.StepCtx) {
+		sCtx.Require().NoError(s.clients.CreateAssignment(s.ctx, 7, 1))
+
+		sCtx.Assert().Equal(uint64(1), s.from(sCtx, replica.WithClient(s.ctx, 7)))
+		sCtx.Assert().NotEqual(uint64(1), s.from(sCtx, replica.WithClient(s.ctx, 8)))
+		sCtx.Assert().NotEqual(uint64(1), s.from(sCtx, s.ctx))
+	})
+	t.WithNewStep("Window", func(sCtx provider.StepCtx) {
+		router := replica.NewRouter([]*sql.DB{nil}, replica.Settings{StickyWindow: 10 * time.Millisecond, Lag: s.lags.measure}, log.New(&s.output))
+		trainings := replica.NewTrainingRepository(s.primary, []repositories.TrainingRepository{s.replicas[0]}, router)
+		clients := replica.NewClientRepository(&assignments{}, router)
+		ctx := replica.WithClient(s.ctx, 7)
+
+		sCtx.Require().NoError(clients.CreateAssignment(s.ctx, 7, 1))
+		found, err := trainings.GetAllByDateTime(ctx, s.dateTime)
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(uint64(1), found[0].ID)
+
+		time.Sleep(20 * time.Millisecond)
+		found, err = trainings.GetAllByDateTime(ctx, s.dateTime)
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(uint64(2), found[0].ID)
+	})
+}
+
+func (s *ReplicaSuite) TestConcurrentCheck(t provider.T) {
+	t.Title("Replica: A single caller measures the lag while the others read on")
+	t.Tags("Replica")
+	t.WithNewStep("Check", func(sCtx provider.StepCtx) {
+		s.lags.release = make(chan struct{})
+		router := replica.NewRouter([]*sql.DB{nil}, replica.Settings{CheckInterval: time.Hour, Lag: s.lags.measure}, log.New(&s.output))
+		trainings := replica.NewTrainingRepository(s.primary, []repositories.TrainingRepository{s.replicas[0]}, router)
+
+		checked := make(chan uint64)
+		go func() {
+			found, err := trainings.GetAllByDateTime(s.ctx, s.dateTime)
+			if err != nil {
+				checked <- 0
+				return
+			}
+			checked <- found[0].ID
+		}()
+		for s.lags.count() < 1 {
+			time.Sleep(time.Millisecond)
+		}
+
+		// The lag is not known yet: the read goes to the primary without
+		// waiting for the check.
+		found, err := trainings.GetAllByDateTime(s.ctx, s.dateTime)
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(uint64(1), found[0].ID)
+		sCtx.Assert().Equal(1, s.lags.count())
+
+		close(s.lags.release)
+		sCtx.Assert().Equal(uint64(2), <-checked)
+		found, err = trainings.GetAllByDateTime(s.ctx, s.dateTime)
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(uint64(2), found[0].ID)
+		sCtx.Assert().Equal(1, s.lags.count())
+	})
+}
+
+func (s *ReplicaSuite) TestTransaction(t provider.T) {
+	t.Title("Replica: Reads within a transaction stay on the primary")
+	t.Tags("Replica")
+	t.WithNewStep("Transaction", func(sCtx provider.StepCtx) {
+		db, err := sqlite.SetupTestDatabase()
+		sCtx.Require().NoError(err)
+		defer db.Close()
+		var transactions managers.TransactionManager = sqlite.CreateTransactionManager(&sqlite.SQLiteRepositoryFields{DB: db})
+
+		err = transactions.WithinTransaction(s.ctx, func(ctx context.Context) error {
+			sCtx.Assert().Equal(uint64(1), s.from(sCtx, ctx))
+			return nil
+		})
+
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(0, s.lags.calls)
+	})
+}
+
+func (s *ReplicaSuite) TestLagQuery(t provider.T) {
+	t.Title("Replica: Lag reads the replay lag of a Postgres replica")
+	t.Tags("Replica")
+	t.WithNewStep("Lag", func(sCtx provider.StepCtx) {
+		db, mock, err := sqlmock.New()
+		sCtx.Require().NoError(err)
+		defer db.Close()
+		mock.ExpectQuery("pg_last_xact_replay_timestamp").WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(1.5))
+		mock.ExpectQuery("pg_last_xact_replay_timestamp").WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(nil))
+
+		lag, err := replica.Lag(s.ctx, db)
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(1500*time.Millisecond, lag)
+
+		_, err = replica.Lag(s.ctx, db)
+		sCtx.Assert().ErrorIs(err, replica.ErrNothingReplayed)
+		sCtx.Assert().NoError(mock.ExpectationsWereMet())
+	})
+}
+
+func TestReplicaSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(ReplicaSuite))
+}
@@ -0,0 +1,299 @@
+package replica
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+)
+
+// ClientRepository keeps the reads of a client on the primary after it
+// booked or cancelled a training. Its own calls all go to the primary.
+type ClientRepository struct {
+	next   repositories.ClientRepository
+	router *Router
+}
+
+// NewClientRepository makes the writes of next sticky with router.
+func NewClientRepository(next repositories.ClientRepository, router *Router) repositories.ClientRepository {
+	return &ClientRepository{next: next, router: router}
+}
+
+func (c *ClientRepository) Create(ctx context.Context, client *models.Client) error {
+	err := c.next.Create(ctx, client)
+	if err == nil {
+		c.router.wrote(ctx, client.ID)
+	}
+
+	return err
+}
+
+func (c *ClientRepository) GetByID(ctx context.Context, id uint64) (*models.Client, error) {
+	return c.next.GetByID(ctx, id)
+}
+
+func (c *ClientRepository) GetByTelephone(ctx context.Context, telephone string) (*models.Client, error) {
+	return c.next.GetByTelephone(ctx, telephone)
+}
+
+func (c *ClientRepository) GetByTraining(ctx context.Context, id uint64) ([]models.Client, error) {
+	return c.next.GetByTraining(ctx, id)
+}
+
+func (c *ClientRepository) CreateAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	err := c.next.CreateAssignment(ctx, clientID, trainingID)
+	if err == nil {
+		c.router.wrote(ctx, clientID)
+	}
+
+	return err
+}
+
+func (c *ClientRepository) DeleteAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	err := c.next.DeleteAssignment(ctx, clientID, trainingID)
+	if err == nil {
+		c.router.wrote(ctx, clientID)
+	}
+
+	return err
+}
+
+type CoachRepository struct {
+	primary  repositories.CoachRepository
+	replicas []repositories.CoachRepository
+	router   *Router
+}
+
+// NewCoachRepository sends GetAll to replicas, one repository per replica
+// of router, and the other calls to primary.
+func NewCoachRepository(primary repositories.CoachRepository, replicas []repositories.CoachRepository, router *Router) repositories.CoachRepository {
+	return &CoachRepository{primary: primary, replicas: replicas, router: router}
+}
+
+func (c *CoachRepository) Create(ctx context.Context, coach *models.Coach) error {
+	err := c.primary.Create(ctx, coach)
+	if err == nil {
+		c.router.wrote(ctx)
+	}
+
+	return err
+}
+
+func (c *CoachRepository) GetByID(ctx context.Context, id uint64) (*models.Coach, error) {
+	return c.primary.GetByID(ctx, id)
+}
+
+func (c *CoachRepository) GetByName(ctx context.Context, name string) (*models.Coach, error) {
+	return c.primary.GetByName(ctx, name)
+}
+
+func (c *CoachRepository) GetAll(ctx context.Context) ([]models.Coach, error) {
+	return read(ctx, c.router, func() ([]models.Coach, error) {
+		return c.primary.GetAll(ctx)
+	}, func(i int) ([]models.Coach, error) {
+		return c.replicas[i].GetAll(ctx)
+	})
+}
+
+type HallRepository struct {
+	primary  repositories.HallRepository
+	replicas []repositories.HallRepository
+	router   *Router
+}
+
+// NewHallRepository sends GetAll to replicas, one repository per replica of
+// router, and the other calls to primary.
+func NewHallRepository(primary repositories.HallRepository, replicas []repositories.HallRepository, router *Router) repositories.HallRepository {
+	return newHallRepository(primary, replicas, router)
+}
+
+func newHallRepository(primary repositories.HallRepository, replicas []repositories.HallRepository, router *Router) *HallRepository {
+	return &HallRepository{primary: primary, replicas: replicas, router: router}
+}
+
+func (h *HallRepository) Create(ctx context.Context, hall *models.Hall) error {
+	err := h.primary.Create(ctx, hall)
+	if err == nil {
+		h.router.wrote(ctx)
+	}
+
+	return err
+}
+
+func (h *HallRepository) GetByID(ctx context.Context, id uint64) (*models.Hall, error) {
+	return h.primary.GetByID(ctx, id)
+}
+
+func (h *HallRepository) GetByNumber(ctx context.Context, number uint64) (*models.Hall, error) {
+	return h.primary.GetByNumber(ctx, number)
+}
+
+func (h *HallRepository) GetAll(ctx context.Context) (map[uint64]models.Hall, error) {
+	return read(ctx, h.router, func() (map[uint64]models.Hall, error) {
+		return h.primary.GetAll(ctx)
+	}, func(i int) (map[uint64]models.Hall, error) {
+		return h.replicas[i].GetAll(ctx)
+	})
+}
+
+// ExtHallRepository also routes the methods of the extended hall
+// repository, all to the primary.
+type ExtHallRepository struct {
+	*HallRepository
+	primary extRepositories.HallRepository
+}
+
+// NewExtHallRepository is NewHallRepository for the extended hall
+// repository.
+func NewExtHallRepository(primary extRepositories.HallRepository, replicas []extRepositories.HallRepository, router *Router) extRepositories.HallRepository {
+	base := make([]repositories.HallRepository, len(replicas))
+	for i, replica := range replicas {
+		base[i] = replica
+	}
+
+	return &ExtHallRepository{HallRepository: newHallRepository(primary, base, router), primary: primary}
+}
+
+func (h *ExtHallRepository) GetDetails(ctx context.Context, id uint64) (*extModels.HallDetails, error) {
+	return h.primary.GetDetails(ctx, id)
+}
+
+func (h *ExtHallRepository) UpdateDetails(ctx context.Context, details *extModels.HallDetails) error {
+	err := h.primary.UpdateDetails(ctx, details)
+	if err == nil {
+		h.router.wrote(ctx)
+	}
+
+	return err
+}
+
+func (h *ExtHallRepository) SetEquipment(ctx context.Context, id uint64, equipment extModels.Equipment, count uint64) error {
+	err := h.primary.SetEquipment(ctx, id, equipment, count)
+	if err == nil {
+		h.router.wrote(ctx)
+	}
+
+	return err
+}
+
+func (h *ExtHallRepository) GetAllByEquipment(ctx context.Context, required map[extModels.Equipment]uint64) (map[uint64]models.Hall, error) {
+	return h.primary.GetAllByEquipment(ctx, required)
+}
+
+type TrainingRepository struct {
+	primary  repositories.TrainingRepository
+	replicas []repositories.TrainingRepository
+	router   *Router
+}
+
+// NewTrainingRepository sends GetAllByDateTime and GetAllBetweenDateTime to
+// replicas, one repository per replica of router, and the other calls to
+// primary.
+func NewTrainingRepository(primary repositories.TrainingRepository, replicas []repositories.TrainingRepository, router *Router) repositories.TrainingRepository {
+	return newTrainingRepository(primary, replicas, router)
+}
+
+func newTrainingRepository(primary repositories.TrainingRepository, replicas []repositories.TrainingRepository, router *Router) *TrainingRepository {
+	return &TrainingRepository{primary: primary, replicas: replicas, router: router}
+}
+
+func (t *TrainingRepository) Create(ctx context.Context, training *models.Training) error {
+	err := t.primary.Create(ctx, training)
+	if err == nil {
+		t.router.wrote(ctx)
+	}
+
+	return err
+}
+
+func (t *TrainingRepository) Delete(ctx context.Context, id uint64) error {
+	err := t.primary.Delete(ctx, id)
+	if err == nil {
+		t.router.wrote(ctx)
+	}
+
+	return err
+}
+
+func (t *TrainingRepository) GetByID(ctx context.Context, id uint64) (*models.Training, error) {
+	return t.primary.GetByID(ctx, id)
+}
+
+func (t *TrainingRepository) GetAllByClient(ctx context.Context, id uint64) ([]models.Training, error) {
+	return t.primary.GetAllByClient(ctx, id)
+}
+
+func (t *TrainingRepository) GetAllByCoachOnDate(ctx context.Context, id uint64, date time.Time) ([]models.Training, error) {
+	return t.primary.GetAllByCoachOnDate(ctx, id, date)
+}
+
+func (t *TrainingRepository) GetAllByDateTime(ctx context.Context, dateTime time.Time) ([]models.Training, error) {
+	return read(ctx, t.router, func() ([]models.Training, error) {
+		return t.primary.GetAllByDateTime(ctx, dateTime)
+	}, func(i int) ([]models.Training, error) {
+		return t.replicas[i].GetAllByDateTime(ctx, dateTime)
+	})
+}
+
+func (t *TrainingRepository) GetAllBetweenDateTime(ctx context.Context, start time.Time, end time.Time) ([]models.Training, error) {
+	return read(ctx, t.router, func() ([]models.Training, error) {
+		return t.primary.GetAllBetweenDateTime(ctx, start, end)
+	}, func(i int) ([]models.Training, error) {
+		return t.replicas[i].GetAllBetweenDateTime(ctx, start, end)
+	})
+}
+
+func (t *TrainingRepository) ReduceAvailablePlacesNum(ctx context.Context, id uint64) error {
+	err := t.primary.ReduceAvailablePlacesNum(ctx, id)
+	if err == nil {
+		t.router.wrote(ctx)
+	}
+
+	return err
+}
+
+func (t *TrainingRepository) IncreaseAvailablePlacesNum(ctx context.Context, id uint64) error {
+	err := t.primary.IncreaseAvailablePlacesNum(ctx, id)
+	if err == nil {
+		t.router.wrote(ctx)
+	}
+
+	return err
+}
+
+// ExtTrainingRepository also routes the methods of the extended training
+// repository, all to the primary.
+type ExtTrainingRepository struct {
+	*TrainingRepository
+	primary extRepositories.TrainingRepository
+}
+
+// NewExtTrainingRepository is NewTrainingRepository for the extended
+// training repository.
+func NewExtTrainingRepository(primary extRepositories.TrainingRepository, replicas []extRepositories.TrainingRepository, router *Router) extRepositories.TrainingRepository {
+	base := make([]repositories.TrainingRepository, len(replicas))
+	for i, replica := range replicas {
+		base[i] = replica
+	}
+
+	return &ExtTrainingRepository{TrainingRepository: newTrainingRepository(primary, base, router), primary: primary}
+}
+
+func (t *ExtTrainingRepository) Reschedule(ctx context.Context, id uint64, dateTime time.Time) error {
+	err := t.primary.Reschedule(ctx, id, dateTime)
+	if err == nil {
+		t.router.wrote(ctx)
+	}
+
+	return err
+}
+
+// AvailablePlacesNum reads the primary, the places change with every
+// booking.
+func (t *ExtTrainingRepository) AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error) {
+	return t.primary.AvailablePlacesNum(ctx, id)
+}
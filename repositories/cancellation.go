@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-repo/models"
+)
+
+type CancellationRepository interface {
+	Cancel(ctx context.Context, clientID, trainingID uint64, cancelledAt time.Time) (*models.Cancellation, error)
+	GetAllByTraining(ctx context.Context, trainingID uint64) ([]models.Cancellation, error)
+	SetHallLateCancelWindow(ctx context.Context, hallID uint64, window time.Duration) error
+	SetTrainingLateCancelWindow(ctx context.Context, trainingID uint64, window time.Duration) error
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-repo/models"
+)
+
+type CoachScheduleRepository interface {
+	CreateAvailability(ctx context.Context, availability *models.CoachAvailability) error
+	GetAvailabilityByCoach(ctx context.Context, coachID uint64) ([]models.CoachAvailability, error)
+	DeleteAvailability(ctx context.Context, id uint64) error
+	CreateTimeOff(ctx context.Context, timeOff *models.CoachTimeOff) error
+	GetTimeOffByCoach(ctx context.Context, coachID uint64) ([]models.CoachTimeOff, error)
+	DeleteTimeOff(ctx context.Context, id uint64) error
+	IsAvailable(ctx context.Context, coachID uint64, dateTime time.Time) (bool, error)
+	GetFreeSlots(ctx context.Context, coachID uint64, date time.Time) ([]time.Time, error)
+}
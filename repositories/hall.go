@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+)
+
+type HallRepository interface {
+	repositories.HallRepository
+	GetDetails(ctx context.Context, id uint64) (*extModels.HallDetails, error)
+	UpdateDetails(ctx context.Context, details *extModels.HallDetails) error
+	SetEquipment(ctx context.Context, id uint64, equipment extModels.Equipment, count uint64) error
+	GetAllByEquipment(ctx context.Context, required map[extModels.Equipment]uint64) (map[uint64]models.Hall, error)
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nkarakotova/lim-core/models"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+)
+
+type HallClosureRepository interface {
+	Create(ctx context.Context, closure *extModels.HallClosure) error
+	GetByID(ctx context.Context, id uint64) (*extModels.HallClosure, error)
+	GetAllByHall(ctx context.Context, hallID uint64) ([]extModels.HallClosure, error)
+	Update(ctx context.Context, closure *extModels.HallClosure) error
+	Delete(ctx context.Context, id uint64) error
+	GetConflictingTrainings(ctx context.Context, id uint64) ([]models.Training, error)
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nkarakotova/lim-repo/models"
+)
+
+type SlotRepository interface {
+	GetFree(ctx context.Context, filter models.SlotFilter) ([]models.Slot, error)
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-core/repositories"
+)
+
+type TrainingRepository interface {
+	repositories.TrainingRepository
+	Reschedule(ctx context.Context, id uint64, dateTime time.Time) error
+	// AvailablePlacesNum returns the number of free places left on the
+	// training.
+	AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error)
+}
@@ -0,0 +1,143 @@
+package repotest
+
+import (
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestClientCreateGetRoundTrip(t provider.T) {
+	t.Title("Client: Create and get round trip")
+	t.Tags("Client", "Conformance")
+	t.WithNewStep("Round trip", func(sCtx provider.StepCtx) {
+		client := &models.Client{Name: "Name", Telephone: "1234567890", Mail: "mail@mail.ru", Password: "123"}
+		err := s.repositories.Client.Create(s.ctx, client)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().NotZero(client.ID)
+
+		byID, err := s.repositories.Client.GetByID(s.ctx, client.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(client, byID)
+
+		byTelephone, err := s.repositories.Client.GetByTelephone(s.ctx, client.Telephone)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(client, byTelephone)
+	})
+}
+
+func (s *Suite) TestClientCreateDistinctIDs(t provider.T) {
+	t.Title("Client: Create assigns distinct IDs")
+	t.Tags("Client", "Conformance")
+	t.WithNewStep("Distinct IDs", func(sCtx provider.StepCtx) {
+		first := s.createClient(t, "1234567890")
+		second := s.createClient(t, "0987654321")
+
+		sCtx.Assert().NotEqual(first.ID, second.ID)
+	})
+}
+
+func (s *Suite) TestClientCreateDuplicateTelephone(t provider.T) {
+	t.Title("Client: Create with a taken telephone")
+	t.Tags("Client", "Conformance")
+	t.WithNewStep("Duplicate telephone", func(sCtx provider.StepCtx) {
+		s.createClient(t, "1234567890")
+
+		err := s.repositories.Client.Create(s.ctx, &models.Client{Name: "Other", Telephone: "1234567890"})
+
+		sCtx.Assert().Error(err)
+	})
+}
+
+func (s *Suite) TestClientGetNotFound(t provider.T) {
+	t.Title("Client: Get a missing client")
+	t.Tags("Client", "Conformance")
+	t.WithNewStep("Not found", func(sCtx provider.StepCtx) {
+		_, err := s.repositories.Client.GetByID(s.ctx, 1)
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		_, err = s.repositories.Client.GetByTelephone(s.ctx, "1234567890")
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *Suite) TestClientBookings(t provider.T) {
+	t.Title("Client: Bookings are counted per training and per client")
+	t.Tags("Client", "Training", "Conformance")
+	t.WithNewStep("Bookings", func(sCtx provider.StepCtx) {
+		trainings := s.createTrainings(t, s.createCoach(t, "Coach"), 10, day.Add(10*time.Hour), day.Add(12*time.Hour))
+		first := s.createClient(t, "1234567890")
+		second := s.createClient(t, "0987654321")
+
+		sCtx.Assert().NoError(s.repositories.Client.CreateAssignment(s.ctx, first.ID, trainings[0].ID))
+		sCtx.Assert().NoError(s.repositories.Client.CreateAssignment(s.ctx, second.ID, trainings[0].ID))
+		sCtx.Assert().NoError(s.repositories.Client.CreateAssignment(s.ctx, first.ID, trainings[1].ID))
+
+		clients, err := s.repositories.Client.GetByTraining(s.ctx, trainings[0].ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch([]models.Client{*first, *second}, clients)
+
+		clients, err = s.repositories.Client.GetByTraining(s.ctx, trainings[1].ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch([]models.Client{*first}, clients)
+
+		byClient, err := s.repositories.Training.GetAllByClient(s.ctx, first.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch(ids(trainings), ids(byClient))
+
+		sCtx.Assert().NoError(s.repositories.Client.DeleteAssignment(s.ctx, first.ID, trainings[0].ID))
+
+		clients, err = s.repositories.Client.GetByTraining(s.ctx, trainings[0].ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch([]models.Client{*second}, clients)
+
+		byClient, err = s.repositories.Training.GetAllByClient(s.ctx, first.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch([]uint64{trainings[1].ID}, ids(byClient))
+	})
+}
+
+func (s *Suite) TestClientBookingTwice(t provider.T) {
+	t.Title("Client: Book the same training twice")
+	t.Tags("Client", "Conformance")
+	t.WithNewStep("Booking twice", func(sCtx provider.StepCtx) {
+		trainings := s.createTrainings(t, s.createCoach(t, "Coach"), 10, day.Add(10*time.Hour))
+		client := s.createClient(t, "1234567890")
+		sCtx.Assert().NoError(s.repositories.Client.CreateAssignment(s.ctx, client.ID, trainings[0].ID))
+
+		err := s.repositories.Client.CreateAssignment(s.ctx, client.ID, trainings[0].ID)
+		sCtx.Assert().Error(err)
+
+		clients, err := s.repositories.Client.GetByTraining(s.ctx, trainings[0].ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Len(clients, 1)
+	})
+}
+
+func (s *Suite) TestClientDeleteMissingBooking(t provider.T) {
+	t.Title("Client: Cancel a missing booking")
+	t.Tags("Client", "Conformance")
+	t.WithNewStep("Not found", func(sCtx provider.StepCtx) {
+		trainings := s.createTrainings(t, s.createCoach(t, "Coach"), 10, day.Add(10*time.Hour))
+		client := s.createClient(t, "1234567890")
+
+		err := s.repositories.Client.DeleteAssignment(s.ctx, client.ID, trainings[0].ID)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *Suite) TestClientGetByTrainingEmpty(t provider.T) {
+	t.Title("Client: No bookings on a training")
+	t.Tags("Client", "Conformance")
+	t.WithNewStep("Empty", func(sCtx provider.StepCtx) {
+		trainings := s.createTrainings(t, s.createCoach(t, "Coach"), 10, day.Add(10*time.Hour))
+
+		clients, err := s.repositories.Client.GetByTraining(s.ctx, trainings[0].ID)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(clients)
+	})
+}
@@ -0,0 +1,68 @@
+package repotest
+
+import (
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestCoachCreateGetRoundTrip(t provider.T) {
+	t.Title("Coach: Create and get round trip")
+	t.Tags("Coach", "Conformance")
+	t.WithNewStep("Round trip", func(sCtx provider.StepCtx) {
+		coach := &models.Coach{Name: "Name"}
+		err := s.repositories.Coach.Create(s.ctx, coach)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().NotZero(coach.ID)
+
+		byID, err := s.repositories.Coach.GetByID(s.ctx, coach.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(coach, byID)
+
+		byName, err := s.repositories.Coach.GetByName(s.ctx, coach.Name)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(coach, byName)
+	})
+}
+
+func (s *Suite) TestCoachCreateDuplicateName(t provider.T) {
+	t.Title("Coach: Create with a taken name")
+	t.Tags("Coach", "Conformance")
+	t.WithNewStep("Duplicate name", func(sCtx provider.StepCtx) {
+		s.createCoach(t, "Name")
+
+		err := s.repositories.Coach.Create(s.ctx, &models.Coach{Name: "Name"})
+
+		sCtx.Assert().Error(err)
+	})
+}
+
+func (s *Suite) TestCoachGetNotFound(t provider.T) {
+	t.Title("Coach: Get a missing coach")
+	t.Tags("Coach", "Conformance")
+	t.WithNewStep("Not found", func(sCtx provider.StepCtx) {
+		_, err := s.repositories.Coach.GetByID(s.ctx, 1)
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		_, err = s.repositories.Coach.GetByName(s.ctx, "Name")
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *Suite) TestCoachGetAll(t provider.T) {
+	t.Title("Coach: Get all coaches")
+	t.Tags("Coach", "Conformance")
+	t.WithNewStep("Get all", func(sCtx provider.StepCtx) {
+		coaches, err := s.repositories.Coach.GetAll(s.ctx)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(coaches)
+
+		first := s.createCoach(t, "First")
+		second := s.createCoach(t, "Second")
+
+		coaches, err = s.repositories.Coach.GetAll(s.ctx)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch([]models.Coach{*first, *second}, coaches)
+	})
+}
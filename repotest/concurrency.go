@@ -0,0 +1,101 @@
+package repotest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+// workers is the number of goroutines of the concurrency tests.
+const workers = 20
+
+// parallel runs fn in workers goroutines and returns the errors they
+// returned, indexed by the worker.
+func parallel(fn func(i int) error) []error {
+	errs := make([]error, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func (s *Suite) TestConcurrentClientCreate(t provider.T) {
+	t.Title("Concurrency: Clients created at once get distinct IDs")
+	t.Tags("Client", "Concurrency", "Conformance")
+	t.WithNewStep("Create", func(sCtx provider.StepCtx) {
+		created := make([]uint64, workers)
+		errs := parallel(func(i int) error {
+			client := newClient(telephone(i))
+			err := s.repositories.Client.Create(s.ctx, client)
+			created[i] = client.ID
+			return err
+		})
+
+		seen := make(map[uint64]bool, workers)
+		for i, err := range errs {
+			sCtx.Assert().NoError(err)
+			sCtx.Assert().False(seen[created[i]], "ID %d is assigned twice", created[i])
+			seen[created[i]] = true
+
+			client, err := s.repositories.Client.GetByID(s.ctx, created[i])
+			sCtx.Assert().NoError(err)
+			if client != nil {
+				sCtx.Assert().Equal(telephone(i), client.Telephone)
+			}
+		}
+	})
+}
+
+func (s *Suite) TestConcurrentBookings(t provider.T) {
+	t.Title("Concurrency: Bookings made at once are all kept")
+	t.Tags("Client", "Training", "Concurrency", "Conformance")
+	t.WithNewStep("Bookings", func(sCtx provider.StepCtx) {
+		trainings := s.createTrainings(t, s.createCoach(t, "Coach"), workers, day.Add(10*time.Hour))
+		clientIDs := make([]uint64, workers)
+		for i := range clientIDs {
+			clientIDs[i] = s.createClient(t, telephone(i)).ID
+		}
+
+		errs := parallel(func(i int) error {
+			return s.repositories.Client.CreateAssignment(s.ctx, clientIDs[i], trainings[0].ID)
+		})
+		for _, err := range errs {
+			sCtx.Assert().NoError(err)
+		}
+
+		clients, err := s.repositories.Client.GetByTraining(s.ctx, trainings[0].ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Len(clients, workers)
+	})
+}
+
+func (s *Suite) TestConcurrentReduceAvailablePlaces(t provider.T) {
+	t.Title("Concurrency: Places are not oversold")
+	t.Tags("Training", "Concurrency", "Conformance")
+	t.WithNewStep("Reduce", func(sCtx provider.StepCtx) {
+		const placesNum = workers / 2
+		trainings := s.createTrainings(t, s.createCoach(t, "Coach"), placesNum, day.Add(10*time.Hour))
+
+		errs := parallel(func(int) error {
+			return s.repositories.Training.ReduceAvailablePlacesNum(s.ctx, trainings[0].ID)
+		})
+
+		reduced := 0
+		for _, err := range errs {
+			if err == nil {
+				reduced++
+			}
+		}
+		sCtx.Assert().Equal(placesNum, reduced)
+		sCtx.Assert().Error(s.repositories.Training.ReduceAvailablePlacesNum(s.ctx, trainings[0].ID))
+	})
+}
@@ -0,0 +1,68 @@
+package repotest
+
+import (
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestHallCreateGetRoundTrip(t provider.T) {
+	t.Title("Hall: Create and get round trip")
+	t.Tags("Hall", "Conformance")
+	t.WithNewStep("Round trip", func(sCtx provider.StepCtx) {
+		hall := &models.Hall{Number: 7}
+		err := s.repositories.Hall.Create(s.ctx, hall)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().NotZero(hall.ID)
+
+		byID, err := s.repositories.Hall.GetByID(s.ctx, hall.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(hall, byID)
+
+		byNumber, err := s.repositories.Hall.GetByNumber(s.ctx, hall.Number)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(hall, byNumber)
+	})
+}
+
+func (s *Suite) TestHallCreateDuplicateNumber(t provider.T) {
+	t.Title("Hall: Create with a taken number")
+	t.Tags("Hall", "Conformance")
+	t.WithNewStep("Duplicate number", func(sCtx provider.StepCtx) {
+		s.createHall(t, 7)
+
+		err := s.repositories.Hall.Create(s.ctx, &models.Hall{Number: 7})
+
+		sCtx.Assert().Error(err)
+	})
+}
+
+func (s *Suite) TestHallGetNotFound(t provider.T) {
+	t.Title("Hall: Get a missing hall")
+	t.Tags("Hall", "Conformance")
+	t.WithNewStep("Not found", func(sCtx provider.StepCtx) {
+		_, err := s.repositories.Hall.GetByID(s.ctx, 1)
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		_, err = s.repositories.Hall.GetByNumber(s.ctx, 7)
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *Suite) TestHallGetAll(t provider.T) {
+	t.Title("Hall: Get all halls")
+	t.Tags("Hall", "Conformance")
+	t.WithNewStep("Get all", func(sCtx provider.StepCtx) {
+		halls, err := s.repositories.Hall.GetAll(s.ctx)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(halls)
+
+		first := s.createHall(t, 1)
+		second := s.createHall(t, 2)
+
+		halls, err = s.repositories.Hall.GetAll(s.ctx)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(map[uint64]models.Hall{first.ID: *first, second.ID: *second}, halls)
+	})
+}
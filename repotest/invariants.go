@@ -0,0 +1,444 @@
+package repotest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nkarakotova/lim-core/models"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+var (
+	invariantSeed  = flag.Int64("repotest.seed", 0, "seed of the booking invariant runs, a random one when 0")
+	invariantRuns  = flag.Int("repotest.runs", 30, "number of random booking sequences")
+	invariantSteps = flag.Int("repotest.steps", 40, "number of steps in a booking sequence")
+)
+
+// AvailablePlaces is implemented by training repositories that can tell the
+// number of free places of a training: every backend of this module and the
+// decorators of its extended TrainingRepository. The booking invariants need
+// it.
+type AvailablePlaces interface {
+	AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error)
+}
+
+const (
+	invariantClients   = 4
+	invariantTrainings = 3
+	invariantPlacesNum = 2
+)
+
+type stepKind int
+
+const (
+	// stepBook is CreateAssignment and ReduceAvailablePlacesNum, undone with
+	// DeleteAssignment when there is no place left, as the booking service
+	// does.
+	stepBook stepKind = iota
+	// stepCancel is DeleteAssignment and IncreaseAvailablePlacesNum.
+	stepCancel
+	// stepHold is ReduceAvailablePlacesNum alone, a place taken without a
+	// booking.
+	stepHold
+	// stepRelease is IncreaseAvailablePlacesNum alone, giving a held place
+	// back. It is skipped when no place is held.
+	stepRelease
+	// stepDelete is Delete of the training.
+	stepDelete
+)
+
+// step is an operation on a client and a training, both by index into the
+// ones created for the run.
+type step struct {
+	kind     stepKind
+	client   int
+	training int
+}
+
+func (s step) String() string {
+	switch s.kind {
+	case stepBook:
+		return fmt.Sprintf("book client %d on training %d", s.client, s.training)
+	case stepCancel:
+		return fmt.Sprintf("cancel client %d on training %d", s.client, s.training)
+	case stepHold:
+		return fmt.Sprintf("hold a place on training %d", s.training)
+	case stepRelease:
+		return fmt.Sprintf("release a place on training %d", s.training)
+	default:
+		return fmt.Sprintf("delete training %d", s.training)
+	}
+}
+
+func randomSteps(rng *rand.Rand, n int) []step {
+	steps := make([]step, n)
+	for i := range steps {
+		steps[i] = step{
+			// Bookings and cancellations are the most of the traffic.
+			kind:     []stepKind{stepBook, stepBook, stepBook, stepCancel, stepCancel, stepHold, stepRelease, stepDelete}[rng.Intn(8)],
+			client:   rng.Intn(invariantClients),
+			training: rng.Intn(invariantTrainings),
+		}
+	}
+
+	return steps
+}
+
+// bookingModel is what the repositories are expected to hold.
+type bookingModel struct {
+	deleted [invariantTrainings]bool
+	holds   [invariantTrainings]uint64
+	booked  [invariantTrainings]map[int]bool
+}
+
+func newBookingModel() *bookingModel {
+	m := &bookingModel{}
+	for i := range m.booked {
+		m.booked[i] = map[int]bool{}
+	}
+
+	return m
+}
+
+func (m *bookingModel) available(training int) uint64 {
+	return invariantPlacesNum - uint64(len(m.booked[training])) - m.holds[training]
+}
+
+// bookingRun runs steps against fresh repositories.
+type bookingRun struct {
+	repositories Repositories
+	available    AvailablePlaces
+	ctx          context.Context
+	model        *bookingModel
+	clients      []uint64
+	trainings    []uint64
+}
+
+// runSteps returns the index of the first step that broke an invariant and
+// what broke, or -1.
+func (s *Suite) runSteps(steps []step) (int, error) {
+	repositories, release, err := s.New()
+	if err != nil {
+		return -1, err
+	}
+	defer release()
+
+	available, ok := repositories.Training.(AvailablePlaces)
+	if !ok {
+		return -1, fmt.Errorf("%T does not implement AvailablePlaces", repositories.Training)
+	}
+
+	r := &bookingRun{repositories: repositories, available: available, ctx: context.Background(), model: newBookingModel()}
+	err = r.setup()
+	if err != nil {
+		return -1, err
+	}
+
+	for i, step := range steps {
+		err = r.apply(step)
+		if err == nil {
+			err = r.check()
+		}
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return -1, nil
+}
+
+func (r *bookingRun) setup() error {
+	coach := &models.Coach{Name: "Coach"}
+	err := r.repositories.Coach.Create(r.ctx, coach)
+	if err != nil {
+		return err
+	}
+
+	hall := &models.Hall{Number: 1}
+	err = r.repositories.Hall.Create(r.ctx, hall)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < invariantClients; i++ {
+		client := newClient(telephone(i))
+		err = r.repositories.Client.Create(r.ctx, client)
+		if err != nil {
+			return err
+		}
+		r.clients = append(r.clients, client.ID)
+	}
+
+	for i := 0; i < invariantTrainings; i++ {
+		training := &models.Training{CoachID: coach.ID, HallID: hall.ID, Name: "Name",
+			DateTime: day.Add(time.Duration(10+i) * time.Hour), PlacesNum: invariantPlacesNum}
+		err = r.repositories.Training.Create(r.ctx, training)
+		if err != nil {
+			return err
+		}
+		r.trainings = append(r.trainings, training.ID)
+	}
+
+	return nil
+}
+
+// expect compares the outcome of a repository call with the model.
+func expect(call string, err error, fails bool) error {
+	if fails && err == nil {
+		return fmt.Errorf("%s succeeded, want an error", call)
+	}
+	if !fails && err != nil {
+		return fmt.Errorf("%s: %w", call, err)
+	}
+
+	return nil
+}
+
+func (r *bookingRun) apply(s step) error {
+	m := r.model
+	clientID, trainingID := r.clients[s.client], r.trainings[s.training]
+
+	switch s.kind {
+	case stepBook:
+		fails := m.deleted[s.training] || m.booked[s.training][s.client]
+		err := expect("CreateAssignment", r.repositories.Client.CreateAssignment(r.ctx, clientID, trainingID), fails)
+		if err != nil || fails {
+			return err
+		}
+
+		full := m.available(s.training) == 0
+		err = expect("ReduceAvailablePlacesNum", r.repositories.Training.ReduceAvailablePlacesNum(r.ctx, trainingID), full)
+		if err != nil {
+			return err
+		}
+		if full {
+			return expect("DeleteAssignment", r.repositories.Client.DeleteAssignment(r.ctx, clientID, trainingID), false)
+		}
+		m.booked[s.training][s.client] = true
+
+	case stepCancel:
+		fails := m.deleted[s.training] || !m.booked[s.training][s.client]
+		err := expect("DeleteAssignment", r.repositories.Client.DeleteAssignment(r.ctx, clientID, trainingID), fails)
+		if err != nil || fails {
+			return err
+		}
+
+		err = expect("IncreaseAvailablePlacesNum", r.repositories.Training.IncreaseAvailablePlacesNum(r.ctx, trainingID), false)
+		if err != nil {
+			return err
+		}
+		delete(m.booked[s.training], s.client)
+
+	case stepHold:
+		fails := m.deleted[s.training] || m.available(s.training) == 0
+		err := expect("ReduceAvailablePlacesNum", r.repositories.Training.ReduceAvailablePlacesNum(r.ctx, trainingID), fails)
+		if err != nil || fails {
+			return err
+		}
+		m.holds[s.training]++
+
+	case stepRelease:
+		if m.deleted[s.training] || m.holds[s.training] == 0 {
+			return nil
+		}
+
+		err := expect("IncreaseAvailablePlacesNum", r.repositories.Training.IncreaseAvailablePlacesNum(r.ctx, trainingID), false)
+		if err != nil {
+			return err
+		}
+		m.holds[s.training]--
+
+	case stepDelete:
+		fails := m.deleted[s.training]
+		err := expect("Delete", r.repositories.Training.Delete(r.ctx, trainingID), fails)
+		if err != nil || fails {
+			return err
+		}
+		m.deleted[s.training] = true
+		m.booked[s.training] = map[int]bool{}
+		m.holds[s.training] = 0
+	}
+
+	return nil
+}
+
+// check compares the repositories with the model: places never go below
+// zero or above the places of the training, a client is booked on a
+// training at most once and the free places are the places less the
+// bookings and the held places.
+func (r *bookingRun) check() error {
+	for training, trainingID := range r.trainings {
+		clients, err := r.repositories.Client.GetByTraining(r.ctx, trainingID)
+		if err != nil && !r.model.deleted[training] {
+			return fmt.Errorf("GetByTraining(training %d): %w", training, err)
+		}
+
+		got := make([]int, 0, len(clients))
+		for _, client := range clients {
+			got = append(got, r.clientIndex(client.ID))
+		}
+		want := make([]int, 0, len(r.model.booked[training]))
+		for client := range r.model.booked[training] {
+			want = append(want, client)
+		}
+		sort.Ints(got)
+		sort.Ints(want)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			return fmt.Errorf("clients of training %d are %v, want %v", training, got, want)
+		}
+
+		if r.model.deleted[training] {
+			continue
+		}
+
+		available, err := r.available.AvailablePlacesNum(r.ctx, trainingID)
+		if err != nil {
+			return fmt.Errorf("AvailablePlacesNum(training %d): %w", training, err)
+		}
+		if available > invariantPlacesNum {
+			return fmt.Errorf("training %d has %d free places of %d", training, available, invariantPlacesNum)
+		}
+		if available != r.model.available(training) {
+			return fmt.Errorf("training %d has %d free places, want %d places less %d bookings less %d held",
+				training, available, invariantPlacesNum, len(r.model.booked[training]), r.model.holds[training])
+		}
+	}
+
+	return nil
+}
+
+func (r *bookingRun) clientIndex(id uint64) int {
+	for i, clientID := range r.clients {
+		if clientID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// shrink returns the shortest sequence it finds that still breaks an
+// invariant: it drops chunks of steps, halving the chunk until single steps,
+// and then renames the clients and trainings of the steps to the first ones.
+func (s *Suite) shrink(steps []step) ([]step, error) {
+	fails := func(candidate []step) (int, error) {
+		failed, err := s.runSteps(candidate)
+		if failed < 0 {
+			return -1, nil
+		}
+
+		return failed, err
+	}
+
+	failed, err := fails(steps)
+	if failed < 0 {
+		return steps, fmt.Errorf("the steps no longer fail, the failure depends on more than them")
+	}
+	steps = steps[:failed+1]
+
+	for chunk := len(steps) / 2; chunk >= 1; chunk /= 2 {
+		for start := 0; start+chunk <= len(steps); {
+			candidate := append(append([]step{}, steps[:start]...), steps[start+chunk:]...)
+			if at, candidateErr := fails(candidate); at >= 0 {
+				steps, err = candidate[:at+1], candidateErr
+				continue
+			}
+			start += chunk
+		}
+	}
+
+	for k := 1; k < invariantTrainings; k++ {
+		candidate := swapIndexes(steps, func(s *step) *int { return &s.training }, k)
+		if indexSum(candidate) >= indexSum(steps) {
+			continue
+		}
+		if at, candidateErr := fails(candidate); at >= 0 {
+			steps, err = candidate[:at+1], candidateErr
+		}
+	}
+	for k := 1; k < invariantClients; k++ {
+		candidate := swapIndexes(steps, func(s *step) *int { return &s.client }, k)
+		if indexSum(candidate) >= indexSum(steps) {
+			continue
+		}
+		if at, candidateErr := fails(candidate); at >= 0 {
+			steps, err = candidate[:at+1], candidateErr
+		}
+	}
+
+	return steps, err
+}
+
+// swapIndexes swaps index k with index 0 in a copy of steps, index picks the
+// client or the training of a step.
+func swapIndexes(steps []step, index func(s *step) *int, k int) []step {
+	swapped := append([]step{}, steps...)
+	for i := range swapped {
+		switch value := index(&swapped[i]); *value {
+		case 0:
+			*value = k
+		case k:
+			*value = 0
+		}
+	}
+
+	return swapped
+}
+
+// indexSum is how far the steps are from using only the first client and
+// training.
+func indexSum(steps []step) int {
+	sum := 0
+	for _, step := range steps {
+		sum += step.client + step.training
+	}
+
+	return sum
+}
+
+func formatSteps(steps []step) string {
+	lines := make([]string, len(steps))
+	for i, step := range steps {
+		lines[i] = fmt.Sprintf("  %d. %s", i+1, step)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (s *Suite) TestBookingInvariants(t provider.T) {
+	t.Title("Booking: Invariants hold over random sequences of bookings")
+	t.Tags("Client", "Training", "Property", "Conformance")
+	if _, ok := s.repositories.Training.(AvailablePlaces); !ok {
+		t.Fatalf("%T does not implement AvailablePlaces, decorators must forward AvailablePlacesNum", s.repositories.Training)
+	}
+
+	seed := *invariantSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	t.Logf("booking invariants seed %d, rerun with -repotest.seed %d", seed, seed)
+
+	t.WithNewStep("Random sequences", func(sCtx provider.StepCtx) {
+		rng := rand.New(rand.NewSource(seed))
+		for run := 0; run < *invariantRuns; run++ {
+			steps := randomSteps(rng, *invariantSteps)
+
+			failed, err := s.runSteps(steps)
+			if failed < 0 && err != nil {
+				t.Fatalf("error running the steps: %v", err)
+			}
+			if failed < 0 {
+				continue
+			}
+
+			minimal, err := s.shrink(steps)
+			t.Fatalf("seed %d, run %d: %v after\n%s", seed, run, err, formatSteps(minimal))
+		}
+	})
+}
@@ -0,0 +1,151 @@
+// Package repotest is a conformance suite for implementations of the
+// lim-core repositories. It checks behavior rather than SQL text, so every
+// backend runs the same tests:
+//
+//	func TestConformanceSuiteRunner(t *testing.T) {
+//		suite.RunSuite(t, &repotest.Suite{New: func() (repotest.Repositories, func(), error) {
+//			storage := inmemory.NewStorage()
+//			return repotest.Repositories{...}, func() {}, nil
+//		}})
+//	}
+//
+// The suite expects the semantics below from every backend:
+//
+//   - IDs are assigned by Create and written back to the model;
+//   - Get* of a missing entity returns repositoriesErrors.EntityDoesNotExists;
+//   - the telephone of a client, the name of a coach, the number of a hall and
+//     a booking of a client on a training are unique;
+//   - GetAllByCoachOnDate returns the trainings starting on the calendar day
+//     of date in the location of date;
+//   - GetAllBetweenDateTime includes both bounds;
+//   - times are compared as instants, the location they are read back in
+//     does not matter.
+//
+// TestBookingInvariants runs random sequences of bookings, cancellations and
+// deletions and checks the places and bookings after every step. It fails
+// for a training repository not implementing AvailablePlaces and shrinks a
+// failing sequence to a short one before it reports it.
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+// Repositories are the repositories of one backend. They must share the
+// storage, trainings refer to coaches and halls and bookings to clients and
+// trainings.
+type Repositories struct {
+	Client   repositories.ClientRepository
+	Coach    repositories.CoachRepository
+	Hall     repositories.HallRepository
+	Training repositories.TrainingRepository
+}
+
+// Factory returns repositories over empty storage and a function releasing
+// it. It is called before every test.
+type Factory func() (Repositories, func(), error)
+
+type Suite struct {
+	suite.Suite
+	New Factory
+
+	repositories Repositories
+	release      func()
+	ctx          context.Context
+}
+
+func (s *Suite) BeforeEach(t provider.T) {
+	var err error
+	s.repositories, s.release, err = s.New()
+	if err != nil {
+		t.Fatalf("error creating repositories: %v", err)
+	}
+	s.ctx = context.Background()
+}
+
+func (s *Suite) AfterEach(t provider.T) {
+	s.release()
+}
+
+// day is the first day of the fixtures. Midnight of it is the boundary most
+// tests are built around. It lies in the future, so that a booking cancelled
+// by the tests gives its place back.
+var day = time.Date(2097, 7, 7, 0, 0, 0, 0, time.UTC)
+
+// moscow is fixed, so the tests do not depend on the tz database.
+var moscow = time.FixedZone("MSK", 3*60*60)
+
+func newClient(telephone string) *models.Client {
+	return &models.Client{Name: "Name", Telephone: telephone, Mail: "mail@mail.ru", Password: "123"}
+}
+
+// telephone is the telephone of the i-th client of a test.
+func telephone(i int) string {
+	return fmt.Sprintf("+7900%07d", i)
+}
+
+func (s *Suite) createClient(t provider.T, telephone string) *models.Client {
+	client := newClient(telephone)
+	err := s.repositories.Client.Create(s.ctx, client)
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+
+	return client
+}
+
+func (s *Suite) createCoach(t provider.T, name string) *models.Coach {
+	coach := &models.Coach{Name: name}
+	err := s.repositories.Coach.Create(s.ctx, coach)
+	if err != nil {
+		t.Fatalf("error creating coach: %v", err)
+	}
+
+	return coach
+}
+
+func (s *Suite) createHall(t provider.T, number uint64) *models.Hall {
+	hall := &models.Hall{Number: number}
+	err := s.repositories.Hall.Create(s.ctx, hall)
+	if err != nil {
+		t.Fatalf("error creating hall: %v", err)
+	}
+
+	return hall
+}
+
+// createTrainings creates a training of the coach for every date time, all in
+// one hall.
+func (s *Suite) createTrainings(t provider.T, coach *models.Coach, placesNum uint64, dateTimes ...time.Time) []models.Training {
+	hall := s.createHall(t, 1)
+
+	trainings := make([]models.Training, 0, len(dateTimes))
+	for _, dateTime := range dateTimes {
+		training := &models.Training{CoachID: coach.ID, HallID: hall.ID, Name: "Name", DateTime: dateTime, PlacesNum: placesNum}
+		err := s.repositories.Training.Create(s.ctx, training)
+		if err != nil {
+			t.Fatalf("error creating training: %v", err)
+		}
+
+		trainings = append(trainings, *training)
+	}
+
+	return trainings
+}
+
+func ids(trainings []models.Training) []uint64 {
+	result := make([]uint64, 0, len(trainings))
+	for _, training := range trainings {
+		result = append(result, training.ID)
+	}
+
+	return result
+}
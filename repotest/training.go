@@ -0,0 +1,203 @@
+package repotest
+
+import (
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+)
+
+func (s *Suite) TestTrainingCreateGetRoundTrip(t provider.T) {
+	t.Title("Training: Create and get round trip")
+	t.Tags("Training", "Conformance")
+	t.WithNewStep("Round trip", func(sCtx provider.StepCtx) {
+		coach := s.createCoach(t, "Coach")
+		hall := s.createHall(t, 1)
+
+		training := &models.Training{CoachID: coach.ID, HallID: hall.ID, Name: "Name", DateTime: day.Add(10 * time.Hour), PlacesNum: 10}
+		err := s.repositories.Training.Create(s.ctx, training)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().NotZero(training.ID)
+
+		byID, err := s.repositories.Training.GetByID(s.ctx, training.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(training.ID, byID.ID)
+		sCtx.Assert().Equal(training.CoachID, byID.CoachID)
+		sCtx.Assert().Equal(training.HallID, byID.HallID)
+		sCtx.Assert().Equal(training.Name, byID.Name)
+		sCtx.Assert().Equal(training.PlacesNum, byID.PlacesNum)
+		sCtx.Assert().True(training.DateTime.Equal(byID.DateTime), "date time %v read back as %v", training.DateTime, byID.DateTime)
+	})
+}
+
+func (s *Suite) TestTrainingCreateRoundTripTimeZone(t provider.T) {
+	t.Title("Training: A date time in another zone reads back as the same instant")
+	t.Tags("Training", "Conformance")
+	t.WithNewStep("Time zone", func(sCtx provider.StepCtx) {
+		dateTime := time.Date(day.Year(), day.Month(), day.Day(), 1, 30, 0, 0, moscow)
+		trainings := s.createTrainings(t, s.createCoach(t, "Coach"), 10, dateTime)
+
+		byID, err := s.repositories.Training.GetByID(s.ctx, trainings[0].ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().True(dateTime.Equal(byID.DateTime), "date time %v read back as %v", dateTime, byID.DateTime)
+
+		byDateTime, err := s.repositories.Training.GetAllByDateTime(s.ctx, dateTime.UTC())
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(ids(trainings), ids(byDateTime))
+	})
+}
+
+func (s *Suite) TestTrainingGetNotFound(t provider.T) {
+	t.Title("Training: Get a missing training")
+	t.Tags("Training", "Conformance")
+	t.WithNewStep("Not found", func(sCtx provider.StepCtx) {
+		_, err := s.repositories.Training.GetByID(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *Suite) TestTrainingDelete(t provider.T) {
+	t.Title("Training: Delete a training and its bookings")
+	t.Tags("Training", "Conformance")
+	t.WithNewStep("Delete", func(sCtx provider.StepCtx) {
+		trainings := s.createTrainings(t, s.createCoach(t, "Coach"), 10, day.Add(10*time.Hour))
+		client := s.createClient(t, "1234567890")
+		sCtx.Assert().NoError(s.repositories.Client.CreateAssignment(s.ctx, client.ID, trainings[0].ID))
+
+		err := s.repositories.Training.Delete(s.ctx, trainings[0].ID)
+		sCtx.Assert().NoError(err)
+
+		_, err = s.repositories.Training.GetByID(s.ctx, trainings[0].ID)
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		byClient, err := s.repositories.Training.GetAllByClient(s.ctx, client.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(byClient)
+
+		err = s.repositories.Training.Delete(s.ctx, trainings[0].ID)
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *Suite) TestTrainingAvailablePlaces(t provider.T) {
+	t.Title("Training: Places run out and come back")
+	t.Tags("Training", "Conformance")
+	t.WithNewStep("Available places", func(sCtx provider.StepCtx) {
+		trainings := s.createTrainings(t, s.createCoach(t, "Coach"), 2, day.Add(10*time.Hour))
+		id := trainings[0].ID
+
+		sCtx.Assert().NoError(s.repositories.Training.ReduceAvailablePlacesNum(s.ctx, id))
+		sCtx.Assert().NoError(s.repositories.Training.ReduceAvailablePlacesNum(s.ctx, id))
+		sCtx.Assert().Error(s.repositories.Training.ReduceAvailablePlacesNum(s.ctx, id))
+
+		sCtx.Assert().NoError(s.repositories.Training.IncreaseAvailablePlacesNum(s.ctx, id))
+		sCtx.Assert().NoError(s.repositories.Training.ReduceAvailablePlacesNum(s.ctx, id))
+		sCtx.Assert().Error(s.repositories.Training.ReduceAvailablePlacesNum(s.ctx, id))
+
+		training, err := s.repositories.Training.GetByID(s.ctx, id)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(uint64(2), training.PlacesNum)
+	})
+}
+
+func (s *Suite) TestTrainingAvailablePlacesNotFound(t provider.T) {
+	t.Title("Training: Change places of a missing training")
+	t.Tags("Training", "Conformance")
+	t.WithNewStep("Not found", func(sCtx provider.StepCtx) {
+		sCtx.Assert().Error(s.repositories.Training.ReduceAvailablePlacesNum(s.ctx, 1))
+		sCtx.Assert().Error(s.repositories.Training.IncreaseAvailablePlacesNum(s.ctx, 1))
+	})
+}
+
+func (s *Suite) TestTrainingGetAllByCoachOnDateMidnight(t provider.T) {
+	t.Title("Training: Trainings of a coach on a date include midnight")
+	t.Tags("Training", "Conformance")
+	t.WithNewStep("Midnight", func(sCtx provider.StepCtx) {
+		coach := s.createCoach(t, "Coach")
+		other := s.createCoach(t, "Other")
+		trainings := s.createTrainings(t, coach, 10,
+			day.Add(-time.Nanosecond),
+			day,
+			day.Add(23*time.Hour+59*time.Minute),
+			day.Add(24*time.Hour))
+
+		hall, err := s.repositories.Hall.GetByNumber(s.ctx, 1)
+		sCtx.Assert().NoError(err)
+		otherTraining := &models.Training{CoachID: other.ID, HallID: hall.ID, Name: "Name", DateTime: day.Add(12 * time.Hour), PlacesNum: 10}
+		sCtx.Assert().NoError(s.repositories.Training.Create(s.ctx, otherTraining))
+
+		onDate, err := s.repositories.Training.GetAllByCoachOnDate(s.ctx, coach.ID, day)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch(ids(trainings[1:3]), ids(onDate))
+
+		onDate, err = s.repositories.Training.GetAllByCoachOnDate(s.ctx, coach.ID, day.Add(15*time.Hour))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch(ids(trainings[1:3]), ids(onDate))
+
+		onDate, err = s.repositories.Training.GetAllByCoachOnDate(s.ctx, coach.ID, day.Add(-24*time.Hour))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch(ids(trainings[:1]), ids(onDate))
+	})
+}
+
+func (s *Suite) TestTrainingGetAllByCoachOnDateTimeZone(t provider.T) {
+	t.Title("Training: The date of a coach is taken in the location of the date")
+	t.Tags("Training", "Conformance")
+	t.WithNewStep("Time zone", func(sCtx provider.StepCtx) {
+		coach := s.createCoach(t, "Coach")
+		// 22:00 on the day before in UTC is 01:00 on the day in Moscow.
+		trainings := s.createTrainings(t, coach, 10, day.Add(-2*time.Hour))
+
+		onDate, err := s.repositories.Training.GetAllByCoachOnDate(s.ctx, coach.ID, time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, moscow))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch(ids(trainings), ids(onDate))
+
+		onDate, err = s.repositories.Training.GetAllByCoachOnDate(s.ctx, coach.ID, day)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(onDate)
+
+		onDate, err = s.repositories.Training.GetAllByCoachOnDate(s.ctx, coach.ID, day.Add(-24*time.Hour))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch(ids(trainings), ids(onDate))
+	})
+}
+
+func (s *Suite) TestTrainingGetAllByDateTime(t provider.T) {
+	t.Title("Training: Trainings at a date time")
+	t.Tags("Training", "Conformance")
+	t.WithNewStep("Date time", func(sCtx provider.StepCtx) {
+		trainings := s.createTrainings(t, s.createCoach(t, "Coach"), 10, day, day, day.Add(time.Hour))
+
+		byDateTime, err := s.repositories.Training.GetAllByDateTime(s.ctx, day)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch(ids(trainings[:2]), ids(byDateTime))
+
+		byDateTime, err = s.repositories.Training.GetAllByDateTime(s.ctx, day.Add(time.Minute))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(byDateTime)
+	})
+}
+
+func (s *Suite) TestTrainingGetAllBetweenDateTime(t provider.T) {
+	t.Title("Training: Trainings between date times include both bounds")
+	t.Tags("Training", "Conformance")
+	t.WithNewStep("Between", func(sCtx provider.StepCtx) {
+		trainings := s.createTrainings(t, s.createCoach(t, "Coach"), 10,
+			day.Add(-time.Hour),
+			day,
+			day.Add(12*time.Hour),
+			day.Add(24*time.Hour),
+			day.Add(25*time.Hour))
+
+		between, err := s.repositories.Training.GetAllBetweenDateTime(s.ctx, day, day.Add(24*time.Hour))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch(ids(trainings[1:4]), ids(between))
+
+		between, err = s.repositories.Training.GetAllBetweenDateTime(s.ctx, day.In(moscow), day.Add(24*time.Hour).In(moscow))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch(ids(trainings[1:4]), ids(between))
+	})
+}
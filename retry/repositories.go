@@ -0,0 +1,262 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+)
+
+type ClientRepository struct {
+	next   repositories.ClientRepository
+	policy *Policy
+}
+
+// NewClientRepository retries the calls of next with policy.
+func NewClientRepository(next repositories.ClientRepository, policy *Policy) repositories.ClientRepository {
+	return &ClientRepository{next: next, policy: policy}
+}
+
+func (c *ClientRepository) Create(ctx context.Context, client *models.Client) error {
+	return c.policy.do(ctx, false, func() error {
+		return c.next.Create(ctx, client)
+	})
+}
+
+func (c *ClientRepository) GetByID(ctx context.Context, id uint64) (*models.Client, error) {
+	return get(ctx, c.policy, func() (*models.Client, error) {
+		return c.next.GetByID(ctx, id)
+	})
+}
+
+func (c *ClientRepository) GetByTelephone(ctx context.Context, telephone string) (*models.Client, error) {
+	return get(ctx, c.policy, func() (*models.Client, error) {
+		return c.next.GetByTelephone(ctx, telephone)
+	})
+}
+
+func (c *ClientRepository) GetByTraining(ctx context.Context, id uint64) ([]models.Client, error) {
+	return get(ctx, c.policy, func() ([]models.Client, error) {
+		return c.next.GetByTraining(ctx, id)
+	})
+}
+
+func (c *ClientRepository) CreateAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	return c.policy.do(ctx, false, func() error {
+		return c.next.CreateAssignment(ctx, clientID, trainingID)
+	})
+}
+
+func (c *ClientRepository) DeleteAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	return c.policy.do(ctx, false, func() error {
+		return c.next.DeleteAssignment(ctx, clientID, trainingID)
+	})
+}
+
+type CoachRepository struct {
+	next   repositories.CoachRepository
+	policy *Policy
+}
+
+// NewCoachRepository retries the calls of next with policy.
+func NewCoachRepository(next repositories.CoachRepository, policy *Policy) repositories.CoachRepository {
+	return &CoachRepository{next: next, policy: policy}
+}
+
+func (c *CoachRepository) Create(ctx context.Context, coach *models.Coach) error {
+	return c.policy.do(ctx, false, func() error {
+		return c.next.Create(ctx, coach)
+	})
+}
+
+func (c *CoachRepository) GetByID(ctx context.Context, id uint64) (*models.Coach, error) {
+	return get(ctx, c.policy, func() (*models.Coach, error) {
+		return c.next.GetByID(ctx, id)
+	})
+}
+
+func (c *CoachRepository) GetByName(ctx context.Context, name string) (*models.Coach, error) {
+	return get(ctx, c.policy, func() (*models.Coach, error) {
+		return c.next.GetByName(ctx, name)
+	})
+}
+
+func (c *CoachRepository) GetAll(ctx context.Context) ([]models.Coach, error) {
+	return get(ctx, c.policy, func() ([]models.Coach, error) {
+		return c.next.GetAll(ctx)
+	})
+}
+
+type HallRepository struct {
+	next   repositories.HallRepository
+	policy *Policy
+}
+
+// NewHallRepository retries the calls of next with policy.
+func NewHallRepository(next repositories.HallRepository, policy *Policy) repositories.HallRepository {
+	return newHallRepository(next, policy)
+}
+
+func newHallRepository(next repositories.HallRepository, policy *Policy) *HallRepository {
+	return &HallRepository{next: next, policy: policy}
+}
+
+func (h *HallRepository) Create(ctx context.Context, hall *models.Hall) error {
+	return h.policy.do(ctx, false, func() error {
+		return h.next.Create(ctx, hall)
+	})
+}
+
+func (h *HallRepository) GetByID(ctx context.Context, id uint64) (*models.Hall, error) {
+	return get(ctx, h.policy, func() (*models.Hall, error) {
+		return h.next.GetByID(ctx, id)
+	})
+}
+
+func (h *HallRepository) GetByNumber(ctx context.Context, number uint64) (*models.Hall, error) {
+	return get(ctx, h.policy, func() (*models.Hall, error) {
+		return h.next.GetByNumber(ctx, number)
+	})
+}
+
+func (h *HallRepository) GetAll(ctx context.Context) (map[uint64]models.Hall, error) {
+	return get(ctx, h.policy, func() (map[uint64]models.Hall, error) {
+		return h.next.GetAll(ctx)
+	})
+}
+
+// ExtHallRepository also retries the methods of the extended hall
+// repository.
+type ExtHallRepository struct {
+	*HallRepository
+	next extRepositories.HallRepository
+}
+
+// NewExtHallRepository is NewHallRepository for the extended hall
+// repository.
+func NewExtHallRepository(next extRepositories.HallRepository, policy *Policy) extRepositories.HallRepository {
+	return &ExtHallRepository{HallRepository: newHallRepository(next, policy), next: next}
+}
+
+func (h *ExtHallRepository) GetDetails(ctx context.Context, id uint64) (*extModels.HallDetails, error) {
+	return get(ctx, h.policy, func() (*extModels.HallDetails, error) {
+		return h.next.GetDetails(ctx, id)
+	})
+}
+
+// UpdateDetails overwrites the details, so it is retried like a read.
+func (h *ExtHallRepository) UpdateDetails(ctx context.Context, details *extModels.HallDetails) error {
+	return h.policy.do(ctx, true, func() error {
+		return h.next.UpdateDetails(ctx, details)
+	})
+}
+
+// SetEquipment overwrites the count, so it is retried like a read.
+func (h *ExtHallRepository) SetEquipment(ctx context.Context, id uint64, equipment extModels.Equipment, count uint64) error {
+	return h.policy.do(ctx, true, func() error {
+		return h.next.SetEquipment(ctx, id, equipment, count)
+	})
+}
+
+func (h *ExtHallRepository) GetAllByEquipment(ctx context.Context, required map[extModels.Equipment]uint64) (map[uint64]models.Hall, error) {
+	return get(ctx, h.policy, func() (map[uint64]models.Hall, error) {
+		return h.next.GetAllByEquipment(ctx, required)
+	})
+}
+
+type TrainingRepository struct {
+	next   repositories.TrainingRepository
+	policy *Policy
+}
+
+// NewTrainingRepository retries the calls of next with policy.
+func NewTrainingRepository(next repositories.TrainingRepository, policy *Policy) repositories.TrainingRepository {
+	return newTrainingRepository(next, policy)
+}
+
+func newTrainingRepository(next repositories.TrainingRepository, policy *Policy) *TrainingRepository {
+	return &TrainingRepository{next: next, policy: policy}
+}
+
+func (t *TrainingRepository) Create(ctx context.Context, training *models.Training) error {
+	return t.policy.do(ctx, false, func() error {
+		return t.next.Create(ctx, training)
+	})
+}
+
+func (t *TrainingRepository) Delete(ctx context.Context, id uint64) error {
+	return t.policy.do(ctx, false, func() error {
+		return t.next.Delete(ctx, id)
+	})
+}
+
+func (t *TrainingRepository) GetByID(ctx context.Context, id uint64) (*models.Training, error) {
+	return get(ctx, t.policy, func() (*models.Training, error) {
+		return t.next.GetByID(ctx, id)
+	})
+}
+
+func (t *TrainingRepository) GetAllByClient(ctx context.Context, id uint64) ([]models.Training, error) {
+	return get(ctx, t.policy, func() ([]models.Training, error) {
+		return t.next.GetAllByClient(ctx, id)
+	})
+}
+
+func (t *TrainingRepository) GetAllByCoachOnDate(ctx context.Context, id uint64, date time.Time) ([]models.Training, error) {
+	return get(ctx, t.policy, func() ([]models.Training, error) {
+		return t.next.GetAllByCoachOnDate(ctx, id, date)
+	})
+}
+
+func (t *TrainingRepository) GetAllByDateTime(ctx context.Context, dateTime time.Time) ([]models.Training, error) {
+	return get(ctx, t.policy, func() ([]models.Training, error) {
+		return t.next.GetAllByDateTime(ctx, dateTime)
+	})
+}
+
+func (t *TrainingRepository) GetAllBetweenDateTime(ctx context.Context, start time.Time, end time.Time) ([]models.Training, error) {
+	return get(ctx, t.policy, func() ([]models.Training, error) {
+		return t.next.GetAllBetweenDateTime(ctx, start, end)
+	})
+}
+
+func (t *TrainingRepository) ReduceAvailablePlacesNum(ctx context.Context, id uint64) error {
+	return t.policy.do(ctx, false, func() error {
+		return t.next.ReduceAvailablePlacesNum(ctx, id)
+	})
+}
+
+func (t *TrainingRepository) IncreaseAvailablePlacesNum(ctx context.Context, id uint64) error {
+	return t.policy.do(ctx, false, func() error {
+		return t.next.IncreaseAvailablePlacesNum(ctx, id)
+	})
+}
+
+// ExtTrainingRepository also retries the methods of the extended training
+// repository.
+type ExtTrainingRepository struct {
+	*TrainingRepository
+	next extRepositories.TrainingRepository
+}
+
+// NewExtTrainingRepository is NewTrainingRepository for the extended
+// training repository.
+func NewExtTrainingRepository(next extRepositories.TrainingRepository, policy *Policy) extRepositories.TrainingRepository {
+	return &ExtTrainingRepository{TrainingRepository: newTrainingRepository(next, policy), next: next}
+}
+
+func (t *ExtTrainingRepository) Reschedule(ctx context.Context, id uint64, dateTime time.Time) error {
+	return t.policy.do(ctx, false, func() error {
+		return t.next.Reschedule(ctx, id, dateTime)
+	})
+}
+
+func (t *ExtTrainingRepository) AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error) {
+	return get(ctx, t.policy, func() (uint64, error) {
+		return t.next.AvailablePlacesNum(ctx, id)
+	})
+}
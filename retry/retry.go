@@ -0,0 +1,225 @@
+// Package retry retries the repository calls that failed on a transient
+// database error: a dropped connection, a serialization failure or a
+// deadlock, the shutdown of the server during a failover.
+//
+// The constructors of this package decorate the client, coach, hall and
+// training repositories and the transaction manager with a Policy. Reads are
+// retried on every transient error. A write is retried only when the error
+// proves it was not applied, a connection dropped while it ran may have
+// committed it. A call within a transaction is never retried, the
+// transaction is aborted; the transaction manager retries it as a whole:
+//
+//	policy := retry.NewPolicy(3)
+//	clients := retry.NewClientRepository(postgreSQL.NewClientPostgreSQLRepository(dbx), policy)
+//	transactions := retry.NewTransactionManager(postgreSQL.CreateTransactionManager(fields), policy)
+package retry
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/avito-tech/go-transaction-manager/trm/v2"
+	"github.com/jackc/pgconn"
+
+	"github.com/nkarakotova/lim-repo/internal/txctx"
+)
+
+// Policy is how often and how fast the calls are retried. The calls of the
+// repositories sharing a Policy draw on the same Budget.
+type Policy struct {
+	// MaxAttempts counts the first call, one disables retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, it doubles with each
+	// retry up to MaxDelay. The actual delay is drawn at random below it.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Budget limits the retries across calls, nil does not.
+	Budget *Budget
+}
+
+// NewPolicy returns a policy of maxAttempts attempts with a backoff from
+// 10ms to 1s and a budget of a retry for every ten calls, up to ten at once.
+func NewPolicy(maxAttempts int) *Policy {
+	return &Policy{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   10 * time.Millisecond,
+		MaxDelay:    time.Second,
+		Budget:      NewBudget(0.1, 10),
+	}
+}
+
+// Budget is a token bucket keeping the retries from piling onto a database
+// that is down: each retry takes a token, each successful call gives back
+// ratio of one.
+type Budget struct {
+	mutex  sync.Mutex
+	tokens float64
+	ratio  float64
+	max    float64
+}
+
+// NewBudget returns a full budget of max tokens.
+func NewBudget(ratio float64, max int) *Budget {
+	return &Budget{tokens: float64(max), ratio: ratio, max: float64(max)}
+}
+
+func (b *Budget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+
+	return true
+}
+
+func (b *Budget) deposit() {
+	if b == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.tokens += b.ratio
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+}
+
+// rolledBackCodes are the SQLSTATEs of a statement the server aborted:
+// serialization_failure, deadlock_detected, admin_shutdown, crash_shutdown
+// and cannot_connect_now.
+var rolledBackCodes = map[string]bool{
+	"40001": true,
+	"40P01": true,
+	"57P01": true,
+	"57P02": true,
+	"57P03": true,
+}
+
+// Retryable reports whether err is transient, so that an idempotent call
+// failed with it can be run again.
+func Retryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if unapplied(err) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Class 08 is connection_exception.
+		return len(pgErr.Code) == 5 && pgErr.Code[:2] == "08"
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return !netErr.Timeout()
+	}
+
+	return false
+}
+
+// unapplied reports whether err proves the statement did not change the
+// database, so that any call failed with it can be run again.
+func unapplied(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return rolledBackCodes[pgErr.Code]
+	}
+
+	// pgconn failed before sending anything to the server.
+	var safe interface{ SafeToRetry() bool }
+
+	return errors.As(err, &safe) && safe.SafeToRetry()
+}
+
+// do calls fn until it succeeds or its error is not worth a retry. An
+// idempotent fn is retried on every transient error.
+func (p *Policy) do(ctx context.Context, idempotent bool, fn func() error) error {
+	err := fn()
+	for attempt := 1; err != nil && attempt < p.MaxAttempts; attempt++ {
+		if !p.retryable(ctx, idempotent, err) || !p.Budget.withdraw() || !p.wait(ctx, attempt) {
+			break
+		}
+		err = fn()
+	}
+	if err == nil {
+		p.Budget.deposit()
+	}
+
+	return err
+}
+
+func (p *Policy) retryable(ctx context.Context, idempotent bool, err error) bool {
+	if ctx.Err() != nil || txctx.InTransaction(ctx) {
+		return false
+	}
+	// A failed commit may have been applied however idempotent the
+	// transaction is.
+	if idempotent && !errors.Is(err, trm.ErrCommit) {
+		return Retryable(err)
+	}
+
+	return unapplied(err)
+}
+
+// wait sleeps before the retry following attempt with full jitter. It gives
+// up when the delay would outlast the deadline of ctx.
+func (p *Policy) wait(ctx context.Context, attempt int) bool {
+	backoff := p.BaseDelay << (attempt - 1)
+	if backoff > p.MaxDelay || backoff <= 0 {
+		backoff = p.MaxDelay
+	}
+	var delay time.Duration
+	if backoff > 0 {
+		delay = time.Duration(rand.Int63n(int64(backoff) + 1))
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// get is do for an idempotent fn returning a result.
+func get[T any](ctx context.Context, p *Policy, fn func() (T, error)) (T, error) {
+	var result T
+	err := p.do(ctx, true, func() error {
+		var err error
+		result, err = fn()
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,275 @@
+package retry_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/managers"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	"github.com/nkarakotova/lim-repo/retry"
+	"github.com/nkarakotova/lim-repo/sqlite"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+var (
+	connectionReset    = &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	serializationError = &pgconn.PgError{Severity: "ERROR", Code: "40001", Message: "could not serialize access"}
+)
+
+// flakyClients fails its next calls with errs, one each.
+type flakyClients struct {
+	repositories.ClientRepository
+	errs  []error
+	calls int
+}
+
+func (f *flakyClients) fail() error {
+	f.calls++
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+
+	return err
+}
+
+func (f *flakyClients) Create(ctx context.Context, client *models.Client) error {
+	if err := f.fail(); err != nil {
+		return err
+	}
+
+	return f.ClientRepository.Create(ctx, client)
+}
+
+func (f *flakyClients) GetByID(ctx context.Context, id uint64) (*models.Client, error) {
+	if err := f.fail(); err != nil {
+		return nil, err
+	}
+
+	return f.ClientRepository.GetByID(ctx, id)
+}
+
+type RetrySuite struct {
+	suite.Suite
+	db           *sql.DB
+	flaky        *flakyClients
+	clients      repositories.ClientRepository
+	transactions managers.TransactionManager
+	policy       *retry.Policy
+	ctx          context.Context
+}
+
+func (s *RetrySuite) BeforeEach(t provider.T) {
+	var err error
+	s.db, err = sqlite.SetupTestDatabase()
+	if err != nil {
+		t.Fatalf("error setting up the database: %v", err)
+	}
+
+	s.policy = &retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	s.flaky = &flakyClients{ClientRepository: sqlite.NewClientSQLiteRepository(sqlx.NewDb(s.db, "sqlite"))}
+	s.clients = retry.NewClientRepository(s.flaky, s.policy)
+	s.transactions = retry.NewTransactionManager(sqlite.CreateTransactionManager(&sqlite.SQLiteRepositoryFields{DB: s.db}), s.policy)
+	s.ctx = context.Background()
+}
+
+func (s *RetrySuite) AfterEach(t provider.T) {
+	s.db.Close()
+}
+
+func (s *RetrySuite) create(sCtx provider.StepCtx) *models.Client {
+	client := &models.Client{Name: "Ivan", Telephone: "+79990001122", Mail: "ivan@mail.ru", Password: "secret"}
+	sCtx.Require().NoError(s.flaky.ClientRepository.Create(s.ctx, client))
+
+	return client
+}
+
+func (s *RetrySuite) TestRetryable(t provider.T) {
+	t.Title("Retry: Only transient errors are retryable")
+	t.Tags("Retry")
+	t.WithNewStep("Classify", func(sCtx provider.StepCtx) {
+		retryable := []error{
+			connectionReset,
+			io.ErrUnexpectedEOF,
+			fmt.Errorf("select: %w", serializationError),
+			&pgconn.PgError{Code: "40P01"},
+			&pgconn.PgError{Code: "57P01"},
+			&pgconn.PgError{Code: "08006"},
+		}
+		for _, err := range retryable {
+			sCtx.Assert().True(retry.Retryable(err), err.Error())
+		}
+
+		permanent := []error{
+			&pgconn.PgError{Code: "23505"},
+			repositoriesErrors.EntityDoesNotExists,
+			context.DeadlineExceeded,
+			&net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded},
+		}
+		for _, err := range permanent {
+			sCtx.Assert().False(retry.Retryable(err), err.Error())
+		}
+	})
+}
+
+func (s *RetrySuite) TestRead(t provider.T) {
+	t.Title("Retry: A read is retried on a dropped connection")
+	t.Tags("Retry")
+	t.WithNewStep("Read", func(sCtx provider.StepCtx) {
+		client := s.create(sCtx)
+		s.flaky.errs = []error{connectionReset, serializationError}
+
+		found, err := s.clients.GetByID(s.ctx, client.ID)
+
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(client.Name, found.Name)
+		sCtx.Assert().Equal(3, s.flaky.calls)
+	})
+	t.WithNewStep("Attempts", func(sCtx provider.StepCtx) {
+		s.flaky.calls = 0
+		s.flaky.errs = []error{connectionReset, connectionReset, connectionReset}
+
+		_, err := s.clients.GetByID(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, syscall.ECONNRESET)
+		sCtx.Assert().Equal(3, s.flaky.calls)
+	})
+	t.WithNewStep("Permanent", func(sCtx provider.StepCtx) {
+		s.flaky.calls = 0
+
+		_, err := s.clients.GetByID(s.ctx, 42)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+		sCtx.Assert().Equal(1, s.flaky.calls)
+	})
+}
+
+func (s *RetrySuite) TestWrite(t provider.T) {
+	t.Title("Retry: A write is retried only when it was not applied")
+	t.Tags("Retry")
+	t.WithNewStep("Rolled back", func(sCtx provider.StepCtx) {
+		s.flaky.errs = []error{serializationError}
+
+		err := s.clients.Create(s.ctx, &models.Client{Name: "Ivan", Telephone: "+79990001122"})
+
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(2, s.flaky.calls)
+	})
+	t.WithNewStep("Dropped connection", func(sCtx provider.StepCtx) {
+		s.flaky.calls = 0
+		s.flaky.errs = []error{connectionReset}
+
+		err := s.clients.Create(s.ctx, &models.Client{Name: "Petr", Telephone: "+79990003344"})
+
+		sCtx.Assert().ErrorIs(err, syscall.ECONNRESET)
+		sCtx.Assert().Equal(1, s.flaky.calls)
+	})
+}
+
+func (s *RetrySuite) TestBudget(t provider.T) {
+	t.Title("Retry: The retries stop once the budget is spent")
+	t.Tags("Retry")
+	t.WithNewStep("Budget", func(sCtx provider.StepCtx) {
+		s.policy.Budget = retry.NewBudget(0.5, 1)
+		s.flaky.errs = []error{connectionReset, connectionReset, connectionReset}
+
+		_, err := s.clients.GetByID(s.ctx, 1)
+		sCtx.Assert().ErrorIs(err, syscall.ECONNRESET)
+		sCtx.Assert().Equal(2, s.flaky.calls)
+
+		_, err = s.clients.GetByID(s.ctx, 1)
+		sCtx.Assert().ErrorIs(err, syscall.ECONNRESET)
+		sCtx.Assert().Equal(3, s.flaky.calls)
+
+		// Two successful calls earn a retry back.
+		s.flaky.errs = nil
+		s.create(sCtx)
+		_, err = s.clients.GetByID(s.ctx, 1)
+		sCtx.Require().NoError(err)
+		_, err = s.clients.GetByID(s.ctx, 1)
+		sCtx.Require().NoError(err)
+
+		s.flaky.calls = 0
+		s.flaky.errs = []error{connectionReset}
+		_, err = s.clients.GetByID(s.ctx, 1)
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(2, s.flaky.calls)
+	})
+}
+
+func (s *RetrySuite) TestDeadline(t provider.T) {
+	t.Title("Retry: No retry outlasts the deadline of the context")
+	t.Tags("Retry")
+	t.WithNewStep("Deadline", func(sCtx provider.StepCtx) {
+		s.policy.BaseDelay = time.Hour
+		s.policy.MaxDelay = time.Hour
+		s.flaky.errs = []error{connectionReset}
+		ctx, cancel := context.WithTimeout(s.ctx, time.Second)
+		defer cancel()
+
+		start := time.Now()
+		_, err := s.clients.GetByID(ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, syscall.ECONNRESET)
+		sCtx.Assert().Equal(1, s.flaky.calls)
+		sCtx.Assert().Less(time.Since(start), time.Second)
+	})
+}
+
+func (s *RetrySuite) TestTransaction(t provider.T) {
+	t.Title("Retry: A transaction is retried as a whole, not its statements")
+	t.Tags("Retry")
+	t.WithNewStep("Transaction", func(sCtx provider.StepCtx) {
+		s.flaky.errs = []error{serializationError}
+		runs := 0
+
+		err := s.transactions.WithinTransaction(s.ctx, func(ctx context.Context) error {
+			runs++
+			err := s.clients.Create(ctx, &models.Client{Name: "Ivan", Telephone: "+79990001122"})
+			if err != nil {
+				return err
+			}
+
+			return s.clients.Create(ctx, &models.Client{Name: "Petr", Telephone: "+79990003344"})
+		})
+
+		sCtx.Require().NoError(err)
+		sCtx.Assert().Equal(2, runs)
+		sCtx.Assert().Equal(3, s.flaky.calls)
+
+		var count int
+		sCtx.Require().NoError(s.db.QueryRowContext(s.ctx, `select count(*) from clients;`).Scan(&count))
+		sCtx.Assert().Equal(2, count)
+	})
+	t.WithNewStep("Permanent", func(sCtx provider.StepCtx) {
+		runs := 0
+
+		err := s.transactions.WithinTransaction(s.ctx, func(ctx context.Context) error {
+			runs++
+			return errors.New("no free places")
+		})
+
+		sCtx.Assert().Error(err)
+		sCtx.Assert().Equal(1, runs)
+	})
+}
+
+func TestRetrySuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(RetrySuite))
+}
@@ -0,0 +1,27 @@
+package retry
+
+import (
+	"context"
+
+	"github.com/nkarakotova/lim-core/managers"
+)
+
+type TransactionManager struct {
+	next   managers.TransactionManager
+	policy *Policy
+}
+
+// NewTransactionManager reruns a transaction of next that failed on a
+// transient error, so the function it runs must have no effects outside the
+// database. A transaction that failed to commit is rerun only when the
+// server rolled it back. A transaction within another is never rerun, the
+// outer one is.
+func NewTransactionManager(next managers.TransactionManager, policy *Policy) managers.TransactionManager {
+	return &TransactionManager{next: next, policy: policy}
+}
+
+func (t *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.policy.do(ctx, true, func() error {
+		return t.next.WithinTransaction(ctx, fn)
+	})
+}
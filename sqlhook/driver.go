@@ -0,0 +1,415 @@
+package sqlhook
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type hookedConnector struct {
+	connector driver.Connector
+	hooked    *hooked
+}
+
+func (c *hookedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hookedConn{Conn: conn, hooked: c.hooked}, nil
+}
+
+func (c *hookedConnector) Driver() driver.Driver {
+	return &hookedDriver{Driver: c.connector.Driver(), hooked: c.hooked}
+}
+
+type hookedDriver struct {
+	driver.Driver
+	hooked *hooked
+}
+
+func (d *hookedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hookedConn{Conn: conn, hooked: d.hooked}, nil
+}
+
+type hookedConn struct {
+	driver.Conn
+	hooked *hooked
+	// tx tells that a transaction is open on the connection. database/sql
+	// uses a connection from one goroutine at a time.
+	tx bool
+}
+
+func (c *hookedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *hookedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &hookedStmt{Stmt: stmt, conn: c, query: query}, nil
+}
+
+func (c *hookedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *hookedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	start := time.Now()
+
+	var (
+		tx  driver.Tx
+		err error
+	)
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = beginner.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.Conn.Begin()
+	}
+	c.hooked.after(ctx, Event{Kind: Begin, Start: start, End: time.Now(), RowsAffected: -1, Err: err})
+	if err != nil {
+		return nil, err
+	}
+
+	c.tx = true
+
+	return &hookedTx{Tx: tx, ctx: ctx, conn: c}, nil
+}
+
+func (c *hookedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if errors.Is(err, driver.ErrSkip) {
+		return nil, err
+	}
+
+	return c.rows(ctx, query, args, start, rows, err)
+}
+
+func (c *hookedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	if errors.Is(err, driver.ErrSkip) {
+		return nil, err
+	}
+
+	return c.result(ctx, query, args, start, result, err)
+}
+
+func (c *hookedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c *hookedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *hookedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+
+	return true
+}
+
+func (c *hookedConn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+
+	return driver.ErrSkip
+}
+
+func (c *hookedConn) result(ctx context.Context, query string, args []driver.NamedValue, start time.Time, result driver.Result, err error) (driver.Result, error) {
+	event := Event{Kind: Exec, Query: query, Args: args, Start: start, End: time.Now(), RowsAffected: -1, Err: err}
+	if err == nil {
+		affected, affectedErr := result.RowsAffected()
+		if affectedErr == nil {
+			event.RowsAffected = affected
+		}
+		event.explain = c.explainer(query, args)
+	}
+	c.hooked.after(ctx, event)
+
+	return result, err
+}
+
+// rows calls the hooks once the rows are closed.
+func (c *hookedConn) rows(ctx context.Context, query string, args []driver.NamedValue, start time.Time, rows driver.Rows, err error) (driver.Rows, error) {
+	event := Event{Kind: Query, Query: query, Args: args, Start: start, RowsAffected: -1}
+	if err != nil {
+		event.End = time.Now()
+		event.Err = err
+		c.hooked.after(ctx, event)
+		return nil, err
+	}
+
+	return &hookedRows{Rows: rows, conn: c, ctx: ctx, event: event}, nil
+}
+
+// explainer runs the explain query on the connection itself: the hooks
+// are called while database/sql holds it, before it goes back to the pool.
+// Within a transaction it does not, so that a failed explain does not abort
+// the transaction of the caller.
+func (c *hookedConn) explainer(query string, args []driver.NamedValue) func(ctx context.Context) (string, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok || c.tx {
+		return nil
+	}
+
+	return func(ctx context.Context) (string, error) {
+		rows, err := queryer.QueryContext(ctx, c.hooked.explainPrefix+query, args)
+		if err != nil {
+			return "", err
+		}
+		defer rows.Close()
+
+		var lines []string
+		dest := make([]driver.Value, len(rows.Columns()))
+		for {
+			err = rows.Next(dest)
+			if errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return "", err
+			}
+
+			// The plan is the last column: the only one of Postgres, the
+			// detail of SQLite.
+			line := dest[len(dest)-1]
+			if b, ok := line.([]byte); ok {
+				line = string(b)
+			}
+			lines = append(lines, fmt.Sprint(line))
+		}
+
+		return strings.Join(lines, "\n"), nil
+	}
+}
+
+type hookedStmt struct {
+	driver.Stmt
+	conn  *hookedConn
+	query string
+}
+
+func (s *hookedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+
+	var (
+		result driver.Result
+		err    error
+	)
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		result, err = s.Stmt.Exec(values(args))
+	}
+
+	return s.conn.result(ctx, s.query, args, start, result, err)
+}
+
+func (s *hookedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		rows, err = s.Stmt.Query(values(args))
+	}
+
+	return s.conn.rows(ctx, s.query, args, start, rows, err)
+}
+
+func (s *hookedStmt) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+
+	return driver.ErrSkip
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+
+	return values
+}
+
+// hookedTx keeps the context of BEGIN, the driver gets none for COMMIT and
+// ROLLBACK.
+type hookedTx struct {
+	driver.Tx
+	ctx  context.Context
+	conn *hookedConn
+}
+
+func (tx *hookedTx) Commit() error {
+	start := time.Now()
+	err := tx.Tx.Commit()
+	tx.conn.tx = false
+	tx.conn.hooked.after(tx.ctx, Event{Kind: Commit, Start: start, End: time.Now(), RowsAffected: -1, Err: err})
+
+	return err
+}
+
+func (tx *hookedTx) Rollback() error {
+	start := time.Now()
+	err := tx.Tx.Rollback()
+	tx.conn.tx = false
+	tx.conn.hooked.after(tx.ctx, Event{Kind: Rollback, Start: start, End: time.Now(), RowsAffected: -1, Err: err})
+
+	return err
+}
+
+type hookedRows struct {
+	driver.Rows
+	conn   *hookedConn
+	ctx    context.Context
+	event  Event
+	closed bool
+}
+
+func (r *hookedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	switch {
+	case err == nil:
+		r.event.Rows++
+	case !errors.Is(err, io.EOF):
+		r.event.Err = err
+	}
+
+	return err
+}
+
+func (r *hookedRows) Close() error {
+	err := r.Rows.Close()
+	if !r.closed {
+		r.closed = true
+		r.event.End = time.Now()
+		if r.event.Err == nil {
+			r.event.explain = r.conn.explainer(r.event.Query, r.event.Args)
+		}
+		r.conn.hooked.after(r.ctx, r.event)
+	}
+
+	return err
+}
+
+// The optional interfaces of the rows fall back to what database/sql
+// assumes when a driver does not implement them.
+
+func (r *hookedRows) HasNextResultSet() bool {
+	if set, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return set.HasNextResultSet()
+	}
+
+	return false
+}
+
+func (r *hookedRows) NextResultSet() error {
+	if set, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return set.NextResultSet()
+	}
+
+	return io.EOF
+}
+
+func (r *hookedRows) ColumnTypeScanType(index int) reflect.Type {
+	if column, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return column.ColumnTypeScanType(index)
+	}
+
+	return reflect.TypeOf(new(any)).Elem()
+}
+
+func (r *hookedRows) ColumnTypeDatabaseTypeName(index int) string {
+	if column, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return column.ColumnTypeDatabaseTypeName(index)
+	}
+
+	return ""
+}
+
+func (r *hookedRows) ColumnTypeLength(index int) (int64, bool) {
+	if column, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return column.ColumnTypeLength(index)
+	}
+
+	return 0, false
+}
+
+func (r *hookedRows) ColumnTypeNullable(index int) (bool, bool) {
+	if column, ok := r.Rows.(driver.RowsColumnTypeNullable); ok {
+		return column.ColumnTypeNullable(index)
+	}
+
+	return false, false
+}
+
+func (r *hookedRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if column, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return column.ColumnTypePrecisionScale(index)
+	}
+
+	return 0, 0, false
+}
@@ -0,0 +1,148 @@
+// Package sqlhook wraps a database/sql driver and calls hooks after every
+// statement, BEGIN, COMMIT and ROLLBACK run on the database. The sqlx
+// repositories are unaware of it; the hooks get the context of the call, so
+// they see the transaction and the span of the repository that ran it.
+//
+// The tracing and querylog packages are the hooks of the repositories:
+//
+//	db, err := sqlhook.OpenDB("pgx", dsn,
+//		tracing.NewHook("pgx"),
+//		querylog.NewHook(logger, querylog.Config{SlowThreshold: 100 * time.Millisecond}))
+package sqlhook
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kind is what an Event ran.
+type Kind int
+
+const (
+	// Query returned rows, the event comes once they are closed.
+	Query Kind = iota
+	// Exec returned a result.
+	Exec
+	Begin
+	Commit
+	Rollback
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Query:
+		return "QUERY"
+	case Exec:
+		return "EXEC"
+	case Begin:
+		return "BEGIN"
+	case Commit:
+		return "COMMIT"
+	case Rollback:
+		return "ROLLBACK"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// Event is a finished statement or transaction command.
+type Event struct {
+	Kind Kind
+	// Query and Args are empty for Begin, Commit and Rollback.
+	Query string
+	Args  []driver.NamedValue
+	Start time.Time
+	End   time.Time
+	// Rows is the number of rows a Query returned.
+	Rows int64
+	// RowsAffected is the number of rows an Exec changed, -1 when the
+	// driver does not know.
+	RowsAffected int64
+	Err          error
+
+	explain func(ctx context.Context) (string, error)
+}
+
+// Duration of the statement, for a Query including reading the rows.
+func (e Event) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+// Explain returns the plan of the Query or Exec, one line per row. It runs
+// on the connection of the statement, so it must be called from the hook.
+func (e Event) Explain(ctx context.Context) (string, error) {
+	if e.explain == nil {
+		return "", ErrNoExplain
+	}
+
+	return e.explain(ctx)
+}
+
+// ErrNoExplain is returned by Event.Explain for a command, a failed
+// statement, a statement within a transaction or a driver that cannot run
+// queries on a connection.
+var ErrNoExplain = errors.New("sqlhook: the event cannot be explained")
+
+// Hook is called after every statement and transaction command.
+type Hook interface {
+	After(ctx context.Context, event Event)
+}
+
+// HookFunc is a function used as a Hook.
+type HookFunc func(ctx context.Context, event Event)
+
+func (f HookFunc) After(ctx context.Context, event Event) {
+	f(ctx, event)
+}
+
+// OpenDB opens a database like sql.Open does and calls hooks after every
+// statement and transaction command run on it. driverName must be
+// registered, e.g. by importing the pgx stdlib or the sqlite driver.
+func OpenDB(driverName, dsn string, hooks ...Hook) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	drv := db.Driver()
+	err = db.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var connector driver.Connector = dsnConnector{dsn: dsn, driver: drv}
+	if driverContext, ok := drv.(driver.DriverContext); ok {
+		connector, err = driverContext.OpenConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	h := &hooked{hooks: hooks, explainPrefix: explainPrefix(driverName)}
+
+	return sql.OpenDB(&hookedConnector{connector: connector, hooked: h}), nil
+}
+
+func explainPrefix(driverName string) string {
+	if strings.HasPrefix(driverName, "sqlite") {
+		return "explain query plan "
+	}
+
+	return "explain "
+}
+
+// hooked is the state shared by the wrappers of one database.
+type hooked struct {
+	hooks         []Hook
+	explainPrefix string
+}
+
+func (h *hooked) after(ctx context.Context, event Event) {
+	for _, hook := range h.hooks {
+		hook.After(ctx, event)
+	}
+}
@@ -0,0 +1,163 @@
+package sqlhook_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/nkarakotova/lim-repo/sqlhook"
+	"github.com/nkarakotova/lim-repo/sqlite"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type recorded struct {
+	event sqlhook.Event
+	plan  string
+	err   error
+}
+
+type SQLHookSuite struct {
+	suite.Suite
+	mutex  sync.Mutex
+	events []recorded
+	db     *sql.DB
+	ctx    context.Context
+}
+
+func (s *SQLHookSuite) BeforeEach(t provider.T) {
+	s.ctx = context.Background()
+
+	var err error
+	s.db, err = sqlhook.OpenDB("sqlite", "file::memory:?_pragma=foreign_keys(1)",
+		sqlhook.HookFunc(func(ctx context.Context, event sqlhook.Event) {
+			r := recorded{event: event}
+			// The plan can only be read from within the hook.
+			r.plan, r.err = event.Explain(ctx)
+
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+			s.events = append(s.events, r)
+		}))
+	if err != nil {
+		t.Fatalf("error opening hooked database: %v", err)
+	}
+	s.db.SetMaxOpenConns(1)
+
+	err = sqlite.Migrate(s.ctx, s.db)
+	if err != nil {
+		t.Fatalf("error applying migrations: %v", err)
+	}
+	s.events = nil
+}
+
+func (s *SQLHookSuite) AfterEach(t provider.T) {
+	s.db.Close()
+}
+
+func (s *SQLHookSuite) TestExec(t provider.T) {
+	t.Title("SQLHook: An exec with its rows affected")
+	t.Tags("SQLHook")
+	t.WithNewStep("Exec", func(sCtx provider.StepCtx) {
+		_, err := s.db.ExecContext(s.ctx, `insert into coaches(name) values($1), ($2);`, "First", "Second")
+		sCtx.Require().NoError(err)
+
+		sCtx.Require().Len(s.events, 1)
+		event := s.events[0].event
+		sCtx.Assert().Equal(sqlhook.Exec, event.Kind)
+		sCtx.Assert().Equal(`insert into coaches(name) values($1), ($2);`, event.Query)
+		sCtx.Assert().Len(event.Args, 2)
+		sCtx.Assert().Equal(int64(2), event.RowsAffected)
+		sCtx.Assert().GreaterOrEqual(event.Duration().Nanoseconds(), int64(0))
+		sCtx.Assert().NoError(s.events[0].err)
+	})
+}
+
+func (s *SQLHookSuite) TestQuery(t provider.T) {
+	t.Title("SQLHook: A query with the rows read and its plan, once the rows are closed")
+	t.Tags("SQLHook")
+	t.WithNewStep("Query", func(sCtx provider.StepCtx) {
+		_, err := s.db.ExecContext(s.ctx, `insert into coaches(name) values('First'), ('Second'), ('Third');`)
+		sCtx.Require().NoError(err)
+		s.events = nil
+
+		rows, err := s.db.QueryContext(s.ctx, `select name from coaches where coach_id > $1;`, 1)
+		sCtx.Require().NoError(err)
+		sCtx.Require().True(rows.Next())
+		sCtx.Assert().Empty(s.events)
+		sCtx.Require().NoError(rows.Close())
+
+		sCtx.Require().Len(s.events, 1)
+		event := s.events[0].event
+		sCtx.Assert().Equal(sqlhook.Query, event.Kind)
+		sCtx.Assert().Equal(int64(1), event.Rows)
+		sCtx.Assert().NoError(s.events[0].err)
+		sCtx.Assert().Contains(s.events[0].plan, "coaches")
+	})
+}
+
+func (s *SQLHookSuite) TestFailure(t provider.T) {
+	t.Title("SQLHook: A failed statement cannot be explained")
+	t.Tags("SQLHook")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		_, err := s.db.QueryContext(s.ctx, `select * from missing;`)
+		sCtx.Require().Error(err)
+
+		sCtx.Require().Len(s.events, 1)
+		sCtx.Assert().Error(s.events[0].event.Err)
+		sCtx.Assert().ErrorIs(s.events[0].err, sqlhook.ErrNoExplain)
+	})
+}
+
+func (s *SQLHookSuite) TestTransaction(t provider.T) {
+	t.Title("SQLHook: BEGIN and ROLLBACK get the context of the transaction")
+	t.Tags("SQLHook")
+	t.WithNewStep("Rollback", func(sCtx provider.StepCtx) {
+		type key struct{}
+		var contexts []interface{}
+		db, err := sqlhook.OpenDB("sqlite", "file::memory:", sqlhook.HookFunc(func(ctx context.Context, event sqlhook.Event) {
+			contexts = append(contexts, ctx.Value(key{}))
+			s.events = append(s.events, recorded{event: event})
+		}))
+		sCtx.Require().NoError(err)
+		defer db.Close()
+
+		tx, err := db.BeginTx(context.WithValue(s.ctx, key{}, "booking"), nil)
+		sCtx.Require().NoError(err)
+		sCtx.Require().NoError(tx.Rollback())
+		sCtx.Assert().True(errors.Is(tx.Commit(), sql.ErrTxDone))
+
+		sCtx.Require().Len(s.events, 2)
+		sCtx.Assert().Equal(sqlhook.Begin, s.events[0].event.Kind)
+		sCtx.Assert().Equal(sqlhook.Rollback, s.events[1].event.Kind)
+		sCtx.Assert().Equal([]interface{}{"booking", "booking"}, contexts)
+	})
+}
+
+func (s *SQLHookSuite) TestTransactionExplain(t provider.T) {
+	t.Title("SQLHook: A statement within a transaction is not explained")
+	t.Tags("SQLHook")
+	t.WithNewStep("Transaction", func(sCtx provider.StepCtx) {
+		tx, err := s.db.BeginTx(s.ctx, nil)
+		sCtx.Require().NoError(err)
+		_, err = tx.ExecContext(s.ctx, `insert into coaches(name) values($1);`, "First")
+		sCtx.Require().NoError(err)
+		sCtx.Require().NoError(tx.Commit())
+		_, err = s.db.ExecContext(s.ctx, `delete from coaches where coach_id = $1;`, 1)
+		sCtx.Require().NoError(err)
+
+		sCtx.Require().Len(s.events, 4)
+		sCtx.Assert().Equal(sqlhook.Exec, s.events[1].event.Kind)
+		sCtx.Assert().ErrorIs(s.events[1].err, sqlhook.ErrNoExplain)
+		sCtx.Assert().Equal(sqlhook.Commit, s.events[2].event.Kind)
+		sCtx.Assert().NoError(s.events[3].err)
+		sCtx.Assert().Contains(s.events[3].plan, "coaches")
+	})
+}
+
+func TestSQLHookSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(SQLHookSuite))
+}
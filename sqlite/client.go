@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jinzhu/copier"
+	"github.com/jmoiron/sqlx"
+)
+
+type ClientSQLite struct {
+	ID        uint64 `db:"client_id"`
+	Name      string `db:"name"`
+	Telephone string `db:"telephone"`
+	Mail      string `db:"mail"`
+	Password  string `db:"password"`
+}
+
+type ClientSQLiteRepository struct {
+	db         *sqlx.DB
+	txResolver *trmsqlx.CtxGetter
+}
+
+func NewClientSQLiteRepository(db *sqlx.DB) repositories.ClientRepository {
+	return &ClientSQLiteRepository{db: db, txResolver: trmsqlx.DefaultCtxGetter}
+}
+
+func (c *ClientSQLiteRepository) Create(ctx context.Context, client *models.Client) error {
+	var err error
+
+	query := `insert into clients(name, telephone, mail, password) values($1, $2, $3, $4) returning client_id;`
+	err = c.txResolver.DefaultTrOrDB(ctx, c.db).
+		QueryRowxContext(ctx, query, client.Name, client.Telephone, client.Mail, client.Password).
+		Scan(&client.ID)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *ClientSQLiteRepository) GetByID(ctx context.Context, id uint64) (*models.Client, error) {
+	query := `select * from clients where client_id = $1;`
+
+	clientDB := &ClientSQLite{}
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).GetContext(ctx, clientDB, query, id)
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	clientModels := &models.Client{}
+	err = copier.Copy(clientModels, clientDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return clientModels, nil
+}
+
+func (c *ClientSQLiteRepository) GetByTelephone(ctx context.Context, telephone string) (*models.Client, error) {
+	query := `select * from clients where telephone = $1;`
+
+	clientDB := &ClientSQLite{}
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).GetContext(ctx, clientDB, query, telephone)
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	clientModels := &models.Client{}
+	err = copier.Copy(clientModels, clientDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return clientModels, nil
+}
+
+func (c *ClientSQLiteRepository) GetByTraining(ctx context.Context, id uint64) ([]models.Client, error) {
+	query := `select * from clients where client_id in (select client_id from clients_trainings where training_id=$1);`
+
+	clientDB := []ClientSQLite{}
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).SelectContext(ctx, &clientDB, query, id)
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	clientModels := []models.Client{}
+	for i := range clientDB {
+		client := models.Client{}
+		err = copier.Copy(&client, &clientDB[i])
+		if err != nil {
+			return nil, err
+		}
+
+		clientModels = append(clientModels, client)
+	}
+
+	return clientModels, nil
+}
+
+func (c *ClientSQLiteRepository) CreateAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	query := `insert into clients_trainings(client_id, training_id) values($1, $2) returning client_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRowxContext(ctx, query, clientID, trainingID).Scan(&clientID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *ClientSQLiteRepository) DeleteAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	query := `delete from clients_trainings where client_id=$1 and training_id=$2 returning client_id;`
+
+	err := c.txResolver.DefaultTrOrDB(ctx, c.db).QueryRowxContext(ctx, query, clientID, trainingID).Scan(&clientID)
+	if err == sql.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
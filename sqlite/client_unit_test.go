@@ -0,0 +1,143 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type ClientSuite struct {
+	suite.Suite
+	db         *sql.DB
+	repository repositories.ClientRepository
+	training   *models.Training
+	ctx        context.Context
+}
+
+func (s *ClientSuite) BeforeEach(t provider.T) {
+	var err error
+	s.db, err = SetupTestDatabase()
+	if err != nil {
+		t.Fatalf("error creating test database: %v", err)
+	}
+	dbx := sqlx.NewDb(s.db, "sqlite")
+	s.repository = NewClientSQLiteRepository(dbx)
+	s.ctx = context.Background()
+
+	s.training = postgreSQLObjectMother.CreateTestTraining()
+	if err := NewCoachSQLiteRepository(dbx).Create(s.ctx, postgreSQLObjectMother.CreateTestCoach()); err != nil {
+		t.Fatalf("error creating coach: %v", err)
+	}
+	if err := NewHallSQLiteRepository(dbx).Create(s.ctx, postgreSQLObjectMother.CreateTestHall()); err != nil {
+		t.Fatalf("error creating hall: %v", err)
+	}
+	if err := NewTrainingSQLiteRepository(dbx).Create(s.ctx, s.training); err != nil {
+		t.Fatalf("error creating training: %v", err)
+	}
+}
+
+func (s *ClientSuite) AfterEach(t provider.T) {
+	s.db.Close()
+}
+
+func (s *ClientSuite) TestClientSQLiteCreateSuccess(t provider.T) {
+	t.Title("ClientSQLiteCreate: Success")
+	t.Tags("Client")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		client := postgreSQLObjectMother.CreateTestClient()
+		client.ID = 0
+
+		err := s.repository.Create(s.ctx, client)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(uint64(1), client.ID)
+	})
+}
+
+func (s *ClientSuite) TestClientSQLiteCreateDuplicateTelephone(t provider.T) {
+	t.Title("ClientSQLiteCreate: Duplicate telephone")
+	t.Tags("Client")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestClient()))
+
+		err := s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestClient())
+
+		sCtx.Assert().Error(err)
+	})
+}
+
+func (s *ClientSuite) TestClientSQLiteGetByTelephoneSuccess(t provider.T) {
+	t.Title("ClientSQLiteGetByTelephone: Success")
+	t.Tags("Client")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestClient()))
+
+		client, err := s.repository.GetByTelephone(s.ctx, "1234567890")
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(postgreSQLObjectMother.CreateTestClient(), client)
+	})
+}
+
+func (s *ClientSuite) TestClientSQLiteGetByIDFailure(t provider.T) {
+	t.Title("ClientSQLiteGetByID: Failure")
+	t.Tags("Client")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		_, err := s.repository.GetByID(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *ClientSuite) TestClientSQLiteAssignmentSuccess(t provider.T) {
+	t.Title("ClientSQLiteAssignment: Create, get and delete")
+	t.Tags("Client")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		client := postgreSQLObjectMother.CreateTestClient()
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, client))
+
+		sCtx.Assert().NoError(s.repository.CreateAssignment(s.ctx, client.ID, s.training.ID))
+		clients, err := s.repository.GetByTraining(s.ctx, s.training.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Client{*client}, clients)
+
+		sCtx.Assert().NoError(s.repository.DeleteAssignment(s.ctx, client.ID, s.training.ID))
+		clients, err = s.repository.GetByTraining(s.ctx, s.training.ID)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Empty(clients)
+	})
+}
+
+func (s *ClientSuite) TestClientSQLiteCreateAssignmentUnknownClient(t provider.T) {
+	t.Title("ClientSQLiteCreateAssignment: Unknown client")
+	t.Tags("Client")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		err := s.repository.CreateAssignment(s.ctx, 1, s.training.ID)
+
+		sCtx.Assert().Error(err)
+	})
+}
+
+func (s *ClientSuite) TestClientSQLiteDeleteAssignmentFailure(t provider.T) {
+	t.Title("ClientSQLiteDeleteAssignment: Failure")
+	t.Tags("Client")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		err := s.repository.DeleteAssignment(s.ctx, 1, s.training.ID)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func TestClientSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(ClientSuite))
+}
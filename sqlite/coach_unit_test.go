@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type CoachSuite struct {
+	suite.Suite
+	db         *sql.DB
+	repository repositories.CoachRepository
+	ctx        context.Context
+}
+
+func (s *CoachSuite) BeforeEach(t provider.T) {
+	var err error
+	s.db, err = SetupTestDatabase()
+	if err != nil {
+		t.Fatalf("error creating test database: %v", err)
+	}
+	s.repository = NewCoachSQLiteRepository(sqlx.NewDb(s.db, "sqlite"))
+	s.ctx = context.Background()
+}
+
+func (s *CoachSuite) AfterEach(t provider.T) {
+	s.db.Close()
+}
+
+func (s *CoachSuite) TestCoachSQLiteGetByNameSuccess(t provider.T) {
+	t.Title("CoachSQLiteGetByName: Success")
+	t.Tags("Coach")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestCoach()))
+
+		coach, err := s.repository.GetByName(s.ctx, "Name")
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(postgreSQLObjectMother.CreateTestCoach(), coach)
+	})
+}
+
+func (s *CoachSuite) TestCoachSQLiteGetByIDFailure(t provider.T) {
+	t.Title("CoachSQLiteGetByID: Failure")
+	t.Tags("Coach")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		_, err := s.repository.GetByID(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *CoachSuite) TestCoachSQLiteGetAllSuccess(t provider.T) {
+	t.Title("CoachSQLiteGetAll: Success")
+	t.Tags("Coach")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, &models.Coach{Name: "B"}))
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, &models.Coach{Name: "A"}))
+		sCtx.Assert().Error(s.repository.Create(s.ctx, &models.Coach{Name: "A"}))
+
+		coaches, err := s.repository.GetAll(s.ctx)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().ElementsMatch([]models.Coach{{ID: 1, Name: "B"}, {ID: 2, Name: "A"}}, coaches)
+	})
+}
+
+func TestCoachSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(CoachSuite))
+}
@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/nkarakotova/lim-repo/repotest"
+
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+func TestConformanceSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, &repotest.Suite{New: func() (repotest.Repositories, func(), error) {
+		db, err := SetupTestDatabase()
+		if err != nil {
+			return repotest.Repositories{}, nil, err
+		}
+		dbx := sqlx.NewDb(db, "sqlite")
+
+		return repotest.Repositories{
+			Client:   NewClientSQLiteRepository(dbx),
+			Coach:    NewCoachSQLiteRepository(dbx),
+			Hall:     NewHallSQLiteRepository(dbx),
+			Training: NewTrainingSQLiteRepository(dbx),
+		}, func() { db.Close() }, nil
+	}})
+}
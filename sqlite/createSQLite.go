@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nkarakotova/lim-repo/config"
+	"github.com/nkarakotova/lim-repo/flags"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/charmbracelet/log"
+	"github.com/jmoiron/sqlx"
+	"github.com/nkarakotova/lim-core/managers"
+	transactionManager "github.com/nkarakotova/lim-core/managers/implementation"
+	"github.com/nkarakotova/lim-core/repositories"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+
+	"github.com/nkarakotova/lim-repo/tracing"
+)
+
+type SQLiteRepositoryFields struct {
+	DB     *sql.DB
+	Config config.Config
+}
+
+// CreateSQLiteRepositoryFields opens the database file and applies the
+// embedded migrations.
+func CreateSQLiteRepositoryFields(SQLite flags.SQLiteFlags, logger *log.Logger) (*SQLiteRepositoryFields, error) {
+	fields := new(SQLiteRepositoryFields)
+	var err error
+	fields.Config.SQLite = SQLite
+
+	fields.DB, err = fields.Config.SQLite.InitDB(logger)
+	if err != nil {
+		logger.Error("SQLITE! Error parse config for sqlite")
+		return nil, err
+	}
+
+	err = Migrate(context.Background(), fields.DB)
+	if err != nil {
+		logger.Error("SQLITE! Error apply migrations")
+		return nil, err
+	}
+
+	logger.Info("SQLITE! Successfully create sqlite repository fields")
+
+	return fields, nil
+}
+
+func CreateClientSQLiteRepository(fields *SQLiteRepositoryFields) repositories.ClientRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite")
+
+	repository := NewClientSQLiteRepository(dbx)
+	if fields.Config.SQLite.Trace {
+		return tracing.NewClientRepository(repository, tracing.WithDBSystem(semconv.DBSystemSqlite))
+	}
+
+	return repository
+}
+
+func CreateCoachSQLiteRepository(fields *SQLiteRepositoryFields) repositories.CoachRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite")
+
+	repository := NewCoachSQLiteRepository(dbx)
+	if fields.Config.SQLite.Trace {
+		return tracing.NewCoachRepository(repository, tracing.WithDBSystem(semconv.DBSystemSqlite))
+	}
+
+	return repository
+}
+
+func CreateHallSQLiteRepository(fields *SQLiteRepositoryFields) repositories.HallRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite")
+
+	repository := NewHallSQLiteRepository(dbx)
+	if fields.Config.SQLite.Trace {
+		return tracing.NewHallRepository(repository, tracing.WithDBSystem(semconv.DBSystemSqlite))
+	}
+
+	return repository
+}
+
+func CreateTrainingSQLiteRepository(fields *SQLiteRepositoryFields) repositories.TrainingRepository {
+	dbx := sqlx.NewDb(fields.DB, "sqlite")
+
+	repository := NewTrainingSQLiteRepository(dbx)
+	if fields.Config.SQLite.Trace {
+		return tracing.NewTrainingRepository(repository, tracing.WithDBSystem(semconv.DBSystemSqlite))
+	}
+
+	return repository
+}
+
+func CreateTransactionManager(fields *SQLiteRepositoryFields) managers.TransactionManager {
+	dbx := sqlx.NewDb(fields.DB, "sqlite")
+
+	return transactionManager.NewTransactionManagerImplementation(manager.Must(trmsqlx.NewDefaultFactory(dbx)))
+}
@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type HallSuite struct {
+	suite.Suite
+	db         *sql.DB
+	repository repositories.HallRepository
+	ctx        context.Context
+}
+
+func (s *HallSuite) BeforeEach(t provider.T) {
+	var err error
+	s.db, err = SetupTestDatabase()
+	if err != nil {
+		t.Fatalf("error creating test database: %v", err)
+	}
+	s.repository = NewHallSQLiteRepository(sqlx.NewDb(s.db, "sqlite"))
+	s.ctx = context.Background()
+}
+
+func (s *HallSuite) AfterEach(t provider.T) {
+	s.db.Close()
+}
+
+func (s *HallSuite) TestHallSQLiteGetByNumberSuccess(t provider.T) {
+	t.Title("HallSQLiteGetByNumber: Success")
+	t.Tags("Hall")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestHall()))
+
+		hall, err := s.repository.GetByNumber(s.ctx, postgreSQLObjectMother.CreateTestHall().Number)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(postgreSQLObjectMother.CreateTestHall(), hall)
+	})
+}
+
+func (s *HallSuite) TestHallSQLiteGetByIDFailure(t provider.T) {
+	t.Title("HallSQLiteGetByID: Failure")
+	t.Tags("Hall")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		_, err := s.repository.GetByID(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *HallSuite) TestHallSQLiteGetAllSuccess(t provider.T) {
+	t.Title("HallSQLiteGetAll: Success")
+	t.Tags("Hall")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, &models.Hall{Number: 7}))
+
+		halls, err := s.repository.GetAll(s.ctx)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(map[uint64]models.Hall{1: {ID: 1, Number: 7}}, halls)
+	})
+}
+
+func TestHallSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(HallSuite))
+}
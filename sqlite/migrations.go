@@ -0,0 +1,24 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+
+	"github.com/nkarakotova/lim-repo/internal/migrate"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// Migrations returns the names of the embedded migration files in the order
+// they are applied.
+func Migrations() ([]string, error) {
+	return migrate.Names(migrationsFS)
+}
+
+// Migrate applies every embedded migration that is not yet recorded in
+// schema_migrations. Each migration runs in its own transaction.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	return migrate.Run(ctx, db, migrationsFS, "current_timestamp", "")
+}
@@ -0,0 +1,43 @@
+create table if not exists clients (
+	client_id integer primary key autoincrement,
+	name      text not null,
+	telephone text not null unique,
+	mail      text not null,
+	password  text not null
+);
+
+create table if not exists coaches (
+	coach_id integer primary key autoincrement,
+	name     text not null unique
+);
+
+create table if not exists halls (
+	hall_id integer primary key autoincrement,
+	number  integer not null unique
+);
+
+create table if not exists trainings (
+	training_id          integer primary key autoincrement,
+	coach_id             integer not null references coaches(coach_id) on delete cascade,
+	hall_id              integer not null references halls(hall_id) on delete cascade,
+	name                 text not null,
+	date_time            timestamp not null,
+	places_num           integer not null check (places_num >= 0),
+	available_places_num integer not null default 0 check (available_places_num >= 0)
+);
+
+create index if not exists trainings_date_time_idx on trainings(date_time);
+create index if not exists trainings_coach_id_idx on trainings(coach_id, date_time);
+
+create trigger if not exists trainings_set_available_places_num
+	after insert on trainings
+	for each row
+begin
+	update trainings set available_places_num = new.places_num where training_id = new.training_id;
+end;
+
+create table if not exists clients_trainings (
+	client_id   integer not null references clients(client_id) on delete cascade,
+	training_id integer not null references trainings(training_id) on delete cascade,
+	primary key (client_id, training_id)
+);
@@ -0,0 +1,26 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	_ "modernc.org/sqlite"
+)
+
+// SetupTestDatabase opens a private in-memory database with all migrations
+// applied. The database is gone once it is closed.
+func SetupTestDatabase() (*sql.DB, error) {
+	db, err := sql.Open("sqlite", "file::memory:?_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	err = Migrate(context.Background(), db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
@@ -0,0 +1,232 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+
+	"github.com/nkarakotova/lim-core/models"
+
+	"github.com/nkarakotova/lim-core/repositories"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/jinzhu/copier"
+	"github.com/jmoiron/sqlx"
+)
+
+type TrainingSQLite struct {
+	ID                 uint64    `db:"training_id"`
+	CoachID            uint64    `db:"coach_id"`
+	HallID             uint64    `db:"hall_id"`
+	Name               string    `db:"name"`
+	DateTime           time.Time `db:"date_time"`
+	PlacesNum          uint64    `db:"places_num"`
+	AvailablePlacesNum uint64    `db:"available_places_num"`
+}
+
+// timestampLayout has no offset: the driver reads such values back as UTC
+// and they compare as text in chronological order.
+const timestampLayout = "2006-01-02 15:04:05.999999999"
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
+
+type TrainingSQLiteRepository struct {
+	db         *sqlx.DB
+	txResolver *trmsqlx.CtxGetter
+}
+
+func NewTrainingSQLiteRepository(db *sqlx.DB) repositories.TrainingRepository {
+	return &TrainingSQLiteRepository{db: db, txResolver: trmsqlx.DefaultCtxGetter}
+}
+
+func (t *TrainingSQLiteRepository) Create(ctx context.Context, training *models.Training) error {
+	query := `insert into trainings(coach_id, hall_id, name, date_time, places_num) values($1, $2, $3, $4, $5) returning training_id;`
+
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRowxContext(ctx, query, training.CoachID, training.HallID, training.Name, timestamp(training.DateTime), training.PlacesNum).Scan(&training.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *TrainingSQLiteRepository) Delete(ctx context.Context, id uint64) error {
+	query := `delete from trainings where training_id=$1 returning training_id;`
+
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRowxContext(ctx, query, id).Scan(&id)
+	if err == sql.ErrNoRows {
+		return repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *TrainingSQLiteRepository) GetByID(ctx context.Context, id uint64) (*models.Training, error) {
+	query := `select * from trainings where training_id=$1;`
+
+	trainingDB := &TrainingSQLite{}
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).GetContext(ctx, trainingDB, query, id)
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	trainingModels := &models.Training{}
+	err = copier.Copy(trainingModels, trainingDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return trainingModels, nil
+}
+
+func (t *TrainingSQLiteRepository) GetAllByClient(ctx context.Context, id uint64) ([]models.Training, error) {
+	query := `select * from trainings where training_id in (select training_id from clients_trainings where client_id=$1);`
+
+	trainingDB := []TrainingSQLite{}
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).SelectContext(ctx, &trainingDB, query, id)
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	trainingModels := []models.Training{}
+	for i := range trainingDB {
+		training := models.Training{}
+		err = copier.Copy(&training, &trainingDB[i])
+		if err != nil {
+			return nil, err
+		}
+
+		trainingModels = append(trainingModels, training)
+	}
+
+	return trainingModels, nil
+}
+
+// GetAllByCoachOnDate returns the trainings starting on the calendar day of
+// date in the location of date. The day is turned into a range of UTC times,
+// as the times are stored in UTC.
+func (t *TrainingSQLiteRepository) GetAllByCoachOnDate(ctx context.Context, id uint64, date time.Time) ([]models.Training, error) {
+	query := `select * from trainings where coach_id=$1 and date_time >= $2 and date_time < $3;`
+
+	year, month, day := date.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+	end := time.Date(year, month, day+1, 0, 0, 0, 0, date.Location())
+
+	trainingDB := []TrainingSQLite{}
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).SelectContext(ctx, &trainingDB, query, id, timestamp(start), timestamp(end))
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	trainingModels := []models.Training{}
+	for i := range trainingDB {
+		training := models.Training{}
+		err = copier.Copy(&training, &trainingDB[i])
+		if err != nil {
+			return nil, err
+		}
+
+		trainingModels = append(trainingModels, training)
+	}
+
+	return trainingModels, nil
+}
+
+func (t *TrainingSQLiteRepository) GetAllByDateTime(ctx context.Context, dateTime time.Time) ([]models.Training, error) {
+	query := `select * from trainings where date_time=$1;`
+
+	trainingDB := []TrainingSQLite{}
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).SelectContext(ctx, &trainingDB, query, timestamp(dateTime))
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	trainingModels := []models.Training{}
+	for i := range trainingDB {
+		training := models.Training{}
+		err = copier.Copy(&training, &trainingDB[i])
+		if err != nil {
+			return nil, err
+		}
+
+		trainingModels = append(trainingModels, training)
+	}
+
+	return trainingModels, nil
+}
+
+func (t *TrainingSQLiteRepository) GetAllBetweenDateTime(ctx context.Context, start time.Time, end time.Time) ([]models.Training, error) {
+	query := `select * from trainings where date_time between $1 and $2;`
+
+	trainingDB := []TrainingSQLite{}
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).SelectContext(ctx, &trainingDB, query, timestamp(start), timestamp(end))
+	if err == sql.ErrNoRows {
+		return nil, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	trainingModels := []models.Training{}
+	for i := range trainingDB {
+		training := models.Training{}
+		err = copier.Copy(&training, &trainingDB[i])
+		if err != nil {
+			return nil, err
+		}
+
+		trainingModels = append(trainingModels, training)
+	}
+
+	return trainingModels, nil
+}
+
+func (t *TrainingSQLiteRepository) ReduceAvailablePlacesNum(ctx context.Context, id uint64) error {
+	query := `update trainings set available_places_num = available_places_num - 1 where training_id=$1 returning training_id;`
+
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRowxContext(ctx, query, id).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *TrainingSQLiteRepository) IncreaseAvailablePlacesNum(ctx context.Context, id uint64) error {
+	query := `update trainings set available_places_num = available_places_num + 1 where training_id=$1 returning training_id;`
+
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).QueryRowxContext(ctx, query, id).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AvailablePlacesNum returns the number of free places left on the training.
+func (t *TrainingSQLiteRepository) AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error) {
+	query := `select available_places_num from trainings where training_id=$1;`
+
+	var available uint64
+	err := t.txResolver.DefaultTrOrDB(ctx, t.db).GetContext(ctx, &available, query, id)
+	if err == sql.ErrNoRows {
+		return 0, repositoriesErrors.EntityDoesNotExists
+	} else if err != nil {
+		return 0, err
+	}
+
+	return available, nil
+}
@@ -0,0 +1,175 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	transactionManager "github.com/nkarakotova/lim-core/managers/implementation"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type TrainingSuite struct {
+	suite.Suite
+	db         *sql.DB
+	dbx        *sqlx.DB
+	repository repositories.TrainingRepository
+	ctx        context.Context
+}
+
+func (s *TrainingSuite) BeforeEach(t provider.T) {
+	var err error
+	s.db, err = SetupTestDatabase()
+	if err != nil {
+		t.Fatalf("error creating test database: %v", err)
+	}
+	s.dbx = sqlx.NewDb(s.db, "sqlite")
+	s.repository = NewTrainingSQLiteRepository(s.dbx)
+	s.ctx = context.Background()
+
+	if err := NewCoachSQLiteRepository(s.dbx).Create(s.ctx, postgreSQLObjectMother.CreateTestCoach()); err != nil {
+		t.Fatalf("error creating coach: %v", err)
+	}
+	if err := NewHallSQLiteRepository(s.dbx).Create(s.ctx, postgreSQLObjectMother.CreateTestHall()); err != nil {
+		t.Fatalf("error creating hall: %v", err)
+	}
+}
+
+func (s *TrainingSuite) AfterEach(t provider.T) {
+	s.db.Close()
+}
+
+func (s *TrainingSuite) TestTrainingSQLiteCreateSuccess(t provider.T) {
+	t.Title("TrainingSQLiteCreate: Success")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, postgreSQLObjectMother.CreateTestTraining()))
+
+		training, err := s.repository.GetByID(s.ctx, 1)
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(postgreSQLObjectMother.CreateTestTraining(), training)
+	})
+}
+
+func (s *TrainingSuite) TestTrainingSQLiteCreateUnknownCoach(t provider.T) {
+	t.Title("TrainingSQLiteCreate: Unknown coach")
+	t.Tags("Training")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		training := postgreSQLObjectMother.CreateTestTraining()
+		training.CoachID = 2
+
+		err := s.repository.Create(s.ctx, training)
+
+		sCtx.Assert().Error(err)
+	})
+}
+
+func (s *TrainingSuite) TestTrainingSQLiteDeleteFailure(t provider.T) {
+	t.Title("TrainingSQLiteDelete: Failure")
+	t.Tags("Training")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		err := s.repository.Delete(s.ctx, 1)
+
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *TrainingSuite) TestTrainingSQLiteGetAllByCoachOnDate(t provider.T) {
+	t.Title("TrainingSQLiteGetAllByCoachOnDate: Success")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		training := postgreSQLObjectMother.CreateTestTraining()
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, training))
+		other := postgreSQLObjectMother.CreateTestTraining()
+		other.DateTime = other.DateTime.AddDate(0, 0, 1)
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, other))
+
+		trainings, err := s.repository.GetAllByCoachOnDate(s.ctx, 1, time.Date(2024, 7, 7, 0, 0, 0, 0, time.UTC))
+
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Training{*training}, trainings)
+	})
+}
+
+func (s *TrainingSuite) TestTrainingSQLiteGetAllByDateTime(t provider.T) {
+	t.Title("TrainingSQLiteGetAllByDateTime: Other location")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		training := postgreSQLObjectMother.CreateTestTraining()
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, training))
+
+		trainings, err := s.repository.GetAllByDateTime(s.ctx, training.DateTime.In(time.FixedZone("MSK", 3*60*60)))
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Training{*training}, trainings)
+
+		trainings, err = s.repository.GetAllBetweenDateTime(s.ctx, training.DateTime.Add(-time.Hour), training.DateTime)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal([]models.Training{*training}, trainings)
+	})
+}
+
+func (s *TrainingSuite) TestTrainingSQLiteAvailablePlacesNum(t provider.T) {
+	t.Title("TrainingSQLiteAvailablePlacesNum: Places are counted")
+	t.Tags("Training")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		training := postgreSQLObjectMother.CreateTestTraining()
+		training.PlacesNum = 1
+		sCtx.Assert().NoError(s.repository.Create(s.ctx, training))
+
+		sCtx.Assert().NoError(s.repository.ReduceAvailablePlacesNum(s.ctx, 1))
+		sCtx.Assert().Error(s.repository.ReduceAvailablePlacesNum(s.ctx, 1))
+		sCtx.Assert().NoError(s.repository.IncreaseAvailablePlacesNum(s.ctx, 1))
+
+		available, err := s.repository.(*TrainingSQLiteRepository).AvailablePlacesNum(s.ctx, 1)
+		sCtx.Assert().NoError(err)
+		sCtx.Assert().Equal(uint64(1), available)
+
+		_, err = s.repository.(*TrainingSQLiteRepository).AvailablePlacesNum(s.ctx, 2)
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *TrainingSuite) TestTrainingSQLiteTransactionRollback(t provider.T) {
+	t.Title("TrainingSQLite: Rollback within the transaction manager")
+	t.Tags("Training")
+	t.WithNewStep("Rollback", func(sCtx provider.StepCtx) {
+		transactor := transactionManager.NewTransactionManagerImplementation(manager.Must(trmsqlx.NewDefaultFactory(s.dbx)))
+		fnErr := errors.New("fail")
+
+		err := transactor.WithinTransaction(s.ctx, func(txCtx context.Context) error {
+			if err := s.repository.Create(txCtx, postgreSQLObjectMother.CreateTestTraining()); err != nil {
+				return err
+			}
+			return fnErr
+		})
+		sCtx.Assert().ErrorIs(err, fnErr)
+
+		_, err = s.repository.GetByID(s.ctx, 1)
+		sCtx.Assert().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+	})
+}
+
+func (s *TrainingSuite) TestTrainingSQLiteMigrateTwice(t provider.T) {
+	t.Title("TrainingSQLite: Migrations are applied once")
+	t.Tags("Training")
+	t.WithNewStep("Migrate", func(sCtx provider.StepCtx) {
+		sCtx.Assert().NoError(Migrate(s.ctx, s.db))
+	})
+}
+
+func TestTrainingSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(TrainingSuite))
+}
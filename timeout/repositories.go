@@ -0,0 +1,260 @@
+package timeout
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+)
+
+type ClientRepository struct {
+	next     repositories.ClientRepository
+	timeouts Timeouts
+}
+
+// NewClientRepository bounds the calls of next by timeouts.
+func NewClientRepository(next repositories.ClientRepository, timeouts Timeouts) repositories.ClientRepository {
+	return &ClientRepository{next: next, timeouts: timeouts}
+}
+
+func (c *ClientRepository) Create(ctx context.Context, client *models.Client) error {
+	return c.timeouts.do(ctx, Write, func(ctx context.Context) error {
+		return c.next.Create(ctx, client)
+	})
+}
+
+func (c *ClientRepository) GetByID(ctx context.Context, id uint64) (*models.Client, error) {
+	return get(ctx, c.timeouts, PointRead, func(ctx context.Context) (*models.Client, error) {
+		return c.next.GetByID(ctx, id)
+	})
+}
+
+func (c *ClientRepository) GetByTelephone(ctx context.Context, telephone string) (*models.Client, error) {
+	return get(ctx, c.timeouts, PointRead, func(ctx context.Context) (*models.Client, error) {
+		return c.next.GetByTelephone(ctx, telephone)
+	})
+}
+
+func (c *ClientRepository) GetByTraining(ctx context.Context, id uint64) ([]models.Client, error) {
+	return get(ctx, c.timeouts, List, func(ctx context.Context) ([]models.Client, error) {
+		return c.next.GetByTraining(ctx, id)
+	})
+}
+
+func (c *ClientRepository) CreateAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	return c.timeouts.do(ctx, Write, func(ctx context.Context) error {
+		return c.next.CreateAssignment(ctx, clientID, trainingID)
+	})
+}
+
+func (c *ClientRepository) DeleteAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	return c.timeouts.do(ctx, Write, func(ctx context.Context) error {
+		return c.next.DeleteAssignment(ctx, clientID, trainingID)
+	})
+}
+
+type CoachRepository struct {
+	next     repositories.CoachRepository
+	timeouts Timeouts
+}
+
+// NewCoachRepository bounds the calls of next by timeouts.
+func NewCoachRepository(next repositories.CoachRepository, timeouts Timeouts) repositories.CoachRepository {
+	return &CoachRepository{next: next, timeouts: timeouts}
+}
+
+func (c *CoachRepository) Create(ctx context.Context, coach *models.Coach) error {
+	return c.timeouts.do(ctx, Write, func(ctx context.Context) error {
+		return c.next.Create(ctx, coach)
+	})
+}
+
+func (c *CoachRepository) GetByID(ctx context.Context, id uint64) (*models.Coach, error) {
+	return get(ctx, c.timeouts, PointRead, func(ctx context.Context) (*models.Coach, error) {
+		return c.next.GetByID(ctx, id)
+	})
+}
+
+func (c *CoachRepository) GetByName(ctx context.Context, name string) (*models.Coach, error) {
+	return get(ctx, c.timeouts, PointRead, func(ctx context.Context) (*models.Coach, error) {
+		return c.next.GetByName(ctx, name)
+	})
+}
+
+func (c *CoachRepository) GetAll(ctx context.Context) ([]models.Coach, error) {
+	return get(ctx, c.timeouts, List, func(ctx context.Context) ([]models.Coach, error) {
+		return c.next.GetAll(ctx)
+	})
+}
+
+type HallRepository struct {
+	next     repositories.HallRepository
+	timeouts Timeouts
+}
+
+// NewHallRepository bounds the calls of next by timeouts.
+func NewHallRepository(next repositories.HallRepository, timeouts Timeouts) repositories.HallRepository {
+	return newHallRepository(next, timeouts)
+}
+
+func newHallRepository(next repositories.HallRepository, timeouts Timeouts) *HallRepository {
+	return &HallRepository{next: next, timeouts: timeouts}
+}
+
+func (h *HallRepository) Create(ctx context.Context, hall *models.Hall) error {
+	return h.timeouts.do(ctx, Write, func(ctx context.Context) error {
+		return h.next.Create(ctx, hall)
+	})
+}
+
+func (h *HallRepository) GetByID(ctx context.Context, id uint64) (*models.Hall, error) {
+	return get(ctx, h.timeouts, PointRead, func(ctx context.Context) (*models.Hall, error) {
+		return h.next.GetByID(ctx, id)
+	})
+}
+
+func (h *HallRepository) GetByNumber(ctx context.Context, number uint64) (*models.Hall, error) {
+	return get(ctx, h.timeouts, PointRead, func(ctx context.Context) (*models.Hall, error) {
+		return h.next.GetByNumber(ctx, number)
+	})
+}
+
+func (h *HallRepository) GetAll(ctx context.Context) (map[uint64]models.Hall, error) {
+	return get(ctx, h.timeouts, List, func(ctx context.Context) (map[uint64]models.Hall, error) {
+		return h.next.GetAll(ctx)
+	})
+}
+
+// ExtHallRepository also bounds the methods of the extended hall
+// repository.
+type ExtHallRepository struct {
+	*HallRepository
+	next extRepositories.HallRepository
+}
+
+// NewExtHallRepository is NewHallRepository for the extended hall
+// repository.
+func NewExtHallRepository(next extRepositories.HallRepository, timeouts Timeouts) extRepositories.HallRepository {
+	return &ExtHallRepository{HallRepository: newHallRepository(next, timeouts), next: next}
+}
+
+func (h *ExtHallRepository) GetDetails(ctx context.Context, id uint64) (*extModels.HallDetails, error) {
+	return get(ctx, h.timeouts, PointRead, func(ctx context.Context) (*extModels.HallDetails, error) {
+		return h.next.GetDetails(ctx, id)
+	})
+}
+
+func (h *ExtHallRepository) UpdateDetails(ctx context.Context, details *extModels.HallDetails) error {
+	return h.timeouts.do(ctx, Write, func(ctx context.Context) error {
+		return h.next.UpdateDetails(ctx, details)
+	})
+}
+
+func (h *ExtHallRepository) SetEquipment(ctx context.Context, id uint64, equipment extModels.Equipment, count uint64) error {
+	return h.timeouts.do(ctx, Write, func(ctx context.Context) error {
+		return h.next.SetEquipment(ctx, id, equipment, count)
+	})
+}
+
+func (h *ExtHallRepository) GetAllByEquipment(ctx context.Context, required map[extModels.Equipment]uint64) (map[uint64]models.Hall, error) {
+	return get(ctx, h.timeouts, Report, func(ctx context.Context) (map[uint64]models.Hall, error) {
+		return h.next.GetAllByEquipment(ctx, required)
+	})
+}
+
+type TrainingRepository struct {
+	next     repositories.TrainingRepository
+	timeouts Timeouts
+}
+
+// NewTrainingRepository bounds the calls of next by timeouts.
+func NewTrainingRepository(next repositories.TrainingRepository, timeouts Timeouts) repositories.TrainingRepository {
+	return newTrainingRepository(next, timeouts)
+}
+
+func newTrainingRepository(next repositories.TrainingRepository, timeouts Timeouts) *TrainingRepository {
+	return &TrainingRepository{next: next, timeouts: timeouts}
+}
+
+func (t *TrainingRepository) Create(ctx context.Context, training *models.Training) error {
+	return t.timeouts.do(ctx, Write, func(ctx context.Context) error {
+		return t.next.Create(ctx, training)
+	})
+}
+
+func (t *TrainingRepository) Delete(ctx context.Context, id uint64) error {
+	return t.timeouts.do(ctx, Write, func(ctx context.Context) error {
+		return t.next.Delete(ctx, id)
+	})
+}
+
+func (t *TrainingRepository) GetByID(ctx context.Context, id uint64) (*models.Training, error) {
+	return get(ctx, t.timeouts, PointRead, func(ctx context.Context) (*models.Training, error) {
+		return t.next.GetByID(ctx, id)
+	})
+}
+
+func (t *TrainingRepository) GetAllByClient(ctx context.Context, id uint64) ([]models.Training, error) {
+	return get(ctx, t.timeouts, List, func(ctx context.Context) ([]models.Training, error) {
+		return t.next.GetAllByClient(ctx, id)
+	})
+}
+
+func (t *TrainingRepository) GetAllByCoachOnDate(ctx context.Context, id uint64, date time.Time) ([]models.Training, error) {
+	return get(ctx, t.timeouts, List, func(ctx context.Context) ([]models.Training, error) {
+		return t.next.GetAllByCoachOnDate(ctx, id, date)
+	})
+}
+
+func (t *TrainingRepository) GetAllByDateTime(ctx context.Context, dateTime time.Time) ([]models.Training, error) {
+	return get(ctx, t.timeouts, List, func(ctx context.Context) ([]models.Training, error) {
+		return t.next.GetAllByDateTime(ctx, dateTime)
+	})
+}
+
+func (t *TrainingRepository) GetAllBetweenDateTime(ctx context.Context, start time.Time, end time.Time) ([]models.Training, error) {
+	return get(ctx, t.timeouts, Report, func(ctx context.Context) ([]models.Training, error) {
+		return t.next.GetAllBetweenDateTime(ctx, start, end)
+	})
+}
+
+func (t *TrainingRepository) ReduceAvailablePlacesNum(ctx context.Context, id uint64) error {
+	return t.timeouts.do(ctx, Write, func(ctx context.Context) error {
+		return t.next.ReduceAvailablePlacesNum(ctx, id)
+	})
+}
+
+func (t *TrainingRepository) IncreaseAvailablePlacesNum(ctx context.Context, id uint64) error {
+	return t.timeouts.do(ctx, Write, func(ctx context.Context) error {
+		return t.next.IncreaseAvailablePlacesNum(ctx, id)
+	})
+}
+
+// ExtTrainingRepository also bounds the methods of the extended training
+// repository.
+type ExtTrainingRepository struct {
+	*TrainingRepository
+	next extRepositories.TrainingRepository
+}
+
+// NewExtTrainingRepository is NewTrainingRepository for the extended
+// training repository.
+func NewExtTrainingRepository(next extRepositories.TrainingRepository, timeouts Timeouts) extRepositories.TrainingRepository {
+	return &ExtTrainingRepository{TrainingRepository: newTrainingRepository(next, timeouts), next: next}
+}
+
+func (t *ExtTrainingRepository) Reschedule(ctx context.Context, id uint64, dateTime time.Time) error {
+	return t.timeouts.do(ctx, Write, func(ctx context.Context) error {
+		return t.next.Reschedule(ctx, id, dateTime)
+	})
+}
+
+func (t *ExtTrainingRepository) AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error) {
+	return get(ctx, t.timeouts, PointRead, func(ctx context.Context) (uint64, error) {
+		return t.next.AvailablePlacesNum(ctx, id)
+	})
+}
@@ -0,0 +1,127 @@
+// Package timeout bounds the repository calls whose context has no deadline,
+// so a runaway query does not run forever.
+//
+// The constructors of this package decorate the client, coach, hall and
+// training repositories with Timeouts, a default timeout per class of
+// operation. A call that ran out of time fails with an error matching
+// repositoriesErrors.QueryTimeout, one canceled by its caller with an error
+// matching repositoriesErrors.QueryCanceled; both still match the error of
+// the context:
+//
+//	clients := timeout.NewClientRepository(postgreSQL.NewClientPostgreSQLRepository(dbx), timeout.DefaultTimeouts)
+package timeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgconn"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+)
+
+// Class is the class of a repository operation.
+type Class int
+
+const (
+	// PointRead reads an entity by a key.
+	PointRead Class = iota
+	// List reads the entities of a client, a coach or a date.
+	List
+	// Write changes the database.
+	Write
+	// Report reads the entities of a period or a search.
+	Report
+)
+
+// Timeouts are the default timeouts of the classes of operations. A zero
+// or negative one leaves the calls of its class unbounded.
+type Timeouts struct {
+	PointRead time.Duration
+	List      time.Duration
+	Write     time.Duration
+	Report    time.Duration
+}
+
+var DefaultTimeouts = Timeouts{
+	PointRead: 2 * time.Second,
+	List:      10 * time.Second,
+	Write:     5 * time.Second,
+	Report:    time.Minute,
+}
+
+func (t Timeouts) of(class Class) time.Duration {
+	switch class {
+	case PointRead:
+		return t.PointRead
+	case List:
+		return t.List
+	case Write:
+		return t.Write
+	}
+
+	return t.Report
+}
+
+// statementCanceled are the SQLSTATEs of a statement the server stopped:
+// query_canceled, raised by statement_timeout, and
+// idle_in_transaction_session_timeout.
+var statementCanceled = map[string]bool{
+	"57014": true,
+	"25P03": true,
+}
+
+// do calls fn with ctx bounded by the timeout of class unless ctx has a
+// deadline already.
+func (t Timeouts) do(ctx context.Context, class Class, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Deadline(); !ok {
+		if timeout := t.of(class); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+	}
+
+	return canceled(ctx, fn(ctx))
+}
+
+// canceled tells the errors of a canceled or timed out call from the others.
+func canceled(ctx context.Context, err error) error {
+	if err == nil || errors.Is(err, extRepositoriesErrors.QueryCanceled) || errors.Is(err, extRepositoriesErrors.QueryTimeout) {
+		return err
+	}
+
+	// The driver may report the cancellation of the context as the error
+	// of the statement it canceled.
+	switch {
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("%w: %w", extRepositoriesErrors.QueryCanceled, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", extRepositoriesErrors.QueryTimeout, err)
+	case errors.Is(ctx.Err(), context.Canceled):
+		return fmt.Errorf("%w: %w: %w", extRepositoriesErrors.QueryCanceled, ctx.Err(), err)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w: %w", extRepositoriesErrors.QueryTimeout, ctx.Err(), err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && statementCanceled[pgErr.Code] {
+		return fmt.Errorf("%w: %w", extRepositoriesErrors.QueryTimeout, err)
+	}
+
+	return err
+}
+
+// get is do for an fn returning a result.
+func get[T any](ctx context.Context, t Timeouts, class Class, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := t.do(ctx, class, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,121 @@
+package timeout_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-repo/inmemory"
+	"github.com/nkarakotova/lim-repo/timeout"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+// runawayTrainings runs GetAllBetweenDateTime until its context is done,
+// or fails it with err.
+type runawayTrainings struct {
+	repositories.TrainingRepository
+	err      error
+	deadline bool
+}
+
+func (r *runawayTrainings) GetAllBetweenDateTime(ctx context.Context, start time.Time, end time.Time) ([]models.Training, error) {
+	_, r.deadline = ctx.Deadline()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+type TimeoutSuite struct {
+	suite.Suite
+	runaway *runawayTrainings
+	ctx     context.Context
+}
+
+func (s *TimeoutSuite) BeforeEach(t provider.T) {
+	s.runaway = &runawayTrainings{TrainingRepository: inmemory.NewTrainingInMemoryRepository(inmemory.NewStorage())}
+	s.ctx = context.Background()
+}
+
+func (s *TimeoutSuite) between(ctx context.Context, timeouts timeout.Timeouts) error {
+	trainings := timeout.NewTrainingRepository(s.runaway, timeouts)
+	_, err := trainings.GetAllBetweenDateTime(ctx, time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	return err
+}
+
+func (s *TimeoutSuite) TestDefault(t provider.T) {
+	t.Title("Timeout: A call without a deadline gets the one of its class")
+	t.Tags("Timeout")
+	t.WithNewStep("Report", func(sCtx provider.StepCtx) {
+		start := time.Now()
+		err := s.between(s.ctx, timeout.Timeouts{PointRead: time.Hour, Report: 10 * time.Millisecond})
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.QueryTimeout)
+		sCtx.Assert().ErrorIs(err, context.DeadlineExceeded)
+		sCtx.Assert().False(errors.Is(err, extRepositoriesErrors.QueryCanceled))
+		sCtx.Assert().Less(time.Since(start), time.Second)
+	})
+	t.WithNewStep("Caller deadline", func(sCtx provider.StepCtx) {
+		ctx, cancel := context.WithTimeout(s.ctx, 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := s.between(ctx, timeout.Timeouts{Report: time.Millisecond})
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.QueryTimeout)
+		sCtx.Assert().GreaterOrEqual(time.Since(start), 40*time.Millisecond)
+	})
+	t.WithNewStep("Unbounded", func(sCtx provider.StepCtx) {
+		s.runaway.err = repositoriesErrors.EntityDoesNotExists
+
+		err := s.between(s.ctx, timeout.Timeouts{})
+
+		sCtx.Assert().Equal(repositoriesErrors.EntityDoesNotExists, err)
+		sCtx.Assert().False(s.runaway.deadline)
+	})
+}
+
+func (s *TimeoutSuite) TestCanceled(t provider.T) {
+	t.Title("Timeout: A call canceled by its caller is not a timeout")
+	t.Tags("Timeout")
+	t.WithNewStep("Canceled", func(sCtx provider.StepCtx) {
+		ctx, cancel := context.WithCancel(s.ctx)
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		err := s.between(ctx, timeout.DefaultTimeouts)
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.QueryCanceled)
+		sCtx.Assert().ErrorIs(err, context.Canceled)
+		sCtx.Assert().False(errors.Is(err, extRepositoriesErrors.QueryTimeout))
+	})
+}
+
+func (s *TimeoutSuite) TestStatementTimeout(t provider.T) {
+	t.Title("Timeout: A statement stopped by statement_timeout is a timeout")
+	t.Tags("Timeout")
+	t.WithNewStep("statement_timeout", func(sCtx provider.StepCtx) {
+		s.runaway.err = &pgconn.PgError{Severity: "ERROR", Code: "57014", Message: "canceling statement due to statement timeout"}
+
+		err := s.between(s.ctx, timeout.DefaultTimeouts)
+
+		sCtx.Assert().ErrorIs(err, extRepositoriesErrors.QueryTimeout)
+		sCtx.Assert().True(s.runaway.deadline)
+	})
+}
+
+func TestTimeoutSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(TimeoutSuite))
+}
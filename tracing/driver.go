@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nkarakotova/lim-repo/sqlhook"
+)
+
+// NewHook returns the hook that starts a span for every statement, BEGIN,
+// COMMIT and ROLLBACK of a database of driverName.
+func NewHook(driverName string, opts ...Option) sqlhook.Hook {
+	return newTracer(dbSystem(driverName), opts)
+}
+
+// OpenDB is sqlhook.OpenDB with the hook of NewHook only.
+func OpenDB(driverName, dsn string, opts ...Option) (*sql.DB, error) {
+	return sqlhook.OpenDB(driverName, dsn, NewHook(driverName, opts...))
+}
+
+func dbSystem(driverName string) attribute.KeyValue {
+	switch driverName {
+	case "pgx", "postgres":
+		return semconv.DBSystemPostgreSQL
+	case "sqlite", "sqlite3":
+		return semconv.DBSystemSqlite
+	default:
+		return semconv.DBSystemOtherSQL
+	}
+}
+
+func (t *tracer) After(ctx context.Context, event sqlhook.Event) {
+	var span trace.Span
+	switch event.Kind {
+	case sqlhook.Query:
+		span = t.startStatement(ctx, event.Query, event.Start)
+		if event.Err == nil {
+			span.SetAttributes(RowsReturnedKey.Int64(event.Rows))
+		}
+	case sqlhook.Exec:
+		span = t.startStatement(ctx, event.Query, event.Start)
+		if event.RowsAffected >= 0 {
+			span.SetAttributes(RowsAffectedKey.Int64(event.RowsAffected))
+		}
+	default:
+		span = t.startCommand(ctx, event.Kind.String(), event.Start)
+	}
+
+	end(span, event.Err, trace.WithTimestamp(event.End))
+}
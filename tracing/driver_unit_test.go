@@ -0,0 +1,194 @@
+package tracing_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nkarakotova/lim-core/managers"
+	transactionManager "github.com/nkarakotova/lim-core/managers/implementation"
+	"github.com/nkarakotova/lim-core/repositories"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+	"github.com/nkarakotova/lim-repo/sqlite"
+	"github.com/nkarakotova/lim-repo/tracing"
+
+	trmsqlx "github.com/avito-tech/go-transaction-manager/drivers/sqlx/v2"
+	"github.com/avito-tech/go-transaction-manager/trm/v2/manager"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type DriverSuite struct {
+	suite.Suite
+	exporter   *tracetest.InMemoryExporter
+	provider   *sdktrace.TracerProvider
+	db         *sql.DB
+	repository repositories.CoachRepository
+	manager    managers.TransactionManager
+	ctx        context.Context
+}
+
+func (s *DriverSuite) BeforeEach(t provider.T) {
+	s.exporter = tracetest.NewInMemoryExporter()
+	s.provider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(s.exporter))
+	s.ctx = context.Background()
+
+	var err error
+	s.db, err = tracing.OpenDB("sqlite", "file::memory:?_pragma=foreign_keys(1)", tracing.WithTracerProvider(s.provider))
+	if err != nil {
+		t.Fatalf("error opening traced database: %v", err)
+	}
+	s.db.SetMaxOpenConns(1)
+
+	err = sqlite.Migrate(s.ctx, s.db)
+	if err != nil {
+		t.Fatalf("error applying migrations: %v", err)
+	}
+	s.exporter.Reset()
+
+	dbx := sqlx.NewDb(s.db, "sqlite")
+	s.repository = tracing.NewCoachRepository(sqlite.NewCoachSQLiteRepository(dbx),
+		tracing.WithTracerProvider(s.provider), tracing.WithDBSystem(semconv.DBSystemSqlite))
+	s.manager = transactionManager.NewTransactionManagerImplementation(manager.Must(trmsqlx.NewDefaultFactory(dbx)))
+}
+
+func (s *DriverSuite) AfterEach(t provider.T) {
+	s.db.Close()
+	s.provider.Shutdown(s.ctx)
+}
+
+func span(spans tracetest.SpanStubs, name string) *tracetest.SpanStub {
+	for i := range spans {
+		if spans[i].Name == name {
+			return &spans[i]
+		}
+	}
+
+	return nil
+}
+
+func value(span *tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+
+	return attribute.Value{}
+}
+
+func (s *DriverSuite) TestQuerySpan(t provider.T) {
+	t.Title("Driver: A query carries the statement, table and row count")
+	t.Tags("Tracing")
+	t.WithNewStep("Query", func(sCtx provider.StepCtx) {
+		coach := postgreSQLObjectMother.CreateTestCoach()
+		sCtx.Require().NoError(s.repository.Create(s.ctx, coach))
+		s.exporter.Reset()
+
+		_, err := s.repository.GetByID(s.ctx, coach.ID)
+		sCtx.Require().NoError(err)
+
+		query := span(s.exporter.GetSpans(), "SELECT coaches")
+		sCtx.Require().NotNil(query)
+		sCtx.Assert().Equal(trace.SpanKindClient, query.SpanKind)
+		sCtx.Assert().Equal("sqlite", value(query, semconv.DBSystemKey).AsString())
+		sCtx.Assert().Equal(`select * from coaches where coach_id = $1;`, value(query, semconv.DBStatementKey).AsString())
+		sCtx.Assert().Equal("coaches", value(query, semconv.DBSQLTableKey).AsString())
+		sCtx.Assert().Equal(int64(1), value(query, tracing.RowsReturnedKey).AsInt64())
+		sCtx.Assert().Equal(codes.Unset, query.Status.Code)
+	})
+}
+
+func (s *DriverSuite) TestExecSpanSanitized(t provider.T) {
+	t.Title("Driver: An exec carries the rows affected and no literals")
+	t.Tags("Tracing")
+	t.WithNewStep("Exec", func(sCtx provider.StepCtx) {
+		_, err := s.db.ExecContext(s.ctx, `insert into coaches(name) values('Secret Coach'), ('Other Coach');`)
+		sCtx.Require().NoError(err)
+
+		exec := span(s.exporter.GetSpans(), "INSERT coaches")
+		sCtx.Require().NotNil(exec)
+		sCtx.Assert().Equal(`insert into coaches(name) values(?), (?);`, value(exec, semconv.DBStatementKey).AsString())
+		sCtx.Assert().Equal(int64(2), value(exec, tracing.RowsAffectedKey).AsInt64())
+	})
+}
+
+func (s *DriverSuite) TestErrorSpan(t provider.T) {
+	t.Title("Driver: A failed statement carries the error type")
+	t.Tags("Tracing")
+	t.WithNewStep("Error", func(sCtx provider.StepCtx) {
+		_, err := s.db.QueryContext(s.ctx, `select * from missing where id = $1;`, 1)
+		sCtx.Require().Error(err)
+
+		query := span(s.exporter.GetSpans(), "SELECT missing")
+		sCtx.Require().NotNil(query)
+		sCtx.Assert().Equal(codes.Error, query.Status.Code)
+		sCtx.Assert().NotEmpty(value(query, semconv.ErrorTypeKey).AsString())
+		sCtx.Assert().NotEmpty(query.Events)
+	})
+}
+
+func (s *DriverSuite) TestTransactionPropagation(t provider.T) {
+	t.Title("Driver: Statements in a trmsqlx transaction nest under the repository call")
+	t.Tags("Tracing")
+	t.WithNewStep("Commit", func(sCtx provider.StepCtx) {
+		ctx, booking := s.provider.Tracer("test").Start(s.ctx, "booking")
+		err := s.manager.WithinTransaction(ctx, func(ctx context.Context) error {
+			return s.repository.Create(ctx, postgreSQLObjectMother.CreateTestCoach())
+		})
+		booking.End()
+		sCtx.Require().NoError(err)
+
+		spans := s.exporter.GetSpans()
+		root := span(spans, "booking")
+		method := span(spans, "CoachRepository.Create")
+		insert := span(spans, "INSERT coaches")
+		begin := span(spans, "BEGIN")
+		commit := span(spans, "COMMIT")
+		sCtx.Require().NotNil(root)
+		sCtx.Require().NotNil(method)
+		sCtx.Require().NotNil(insert)
+		sCtx.Require().NotNil(begin)
+		sCtx.Require().NotNil(commit)
+
+		sCtx.Assert().Equal(root.SpanContext.SpanID(), method.Parent.SpanID())
+		sCtx.Assert().Equal(method.SpanContext.SpanID(), insert.Parent.SpanID())
+		sCtx.Assert().Equal(root.SpanContext.SpanID(), begin.Parent.SpanID())
+		sCtx.Assert().Equal(root.SpanContext.SpanID(), commit.Parent.SpanID())
+		sCtx.Assert().Equal(root.SpanContext.TraceID(), insert.SpanContext.TraceID())
+	})
+}
+
+func (s *DriverSuite) TestTransactionRollback(t provider.T) {
+	t.Title("Driver: A failed transaction ends with a ROLLBACK span")
+	t.Tags("Tracing")
+	t.WithNewStep("Rollback", func(sCtx provider.StepCtx) {
+		errFail := errors.New("fail")
+		err := s.manager.WithinTransaction(s.ctx, func(ctx context.Context) error {
+			err := s.repository.Create(ctx, postgreSQLObjectMother.CreateTestCoach())
+			if err != nil {
+				return err
+			}
+
+			return errFail
+		})
+		sCtx.Require().ErrorIs(err, errFail)
+
+		spans := s.exporter.GetSpans()
+		sCtx.Assert().NotNil(span(spans, "ROLLBACK"))
+		sCtx.Assert().Nil(span(spans, "COMMIT"))
+	})
+}
+
+func TestDriverSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(DriverSuite))
+}
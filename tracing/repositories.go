@@ -0,0 +1,334 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/nkarakotova/lim-core/models"
+	"github.com/nkarakotova/lim-core/repositories"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+
+	extModels "github.com/nkarakotova/lim-repo/models"
+	extRepositories "github.com/nkarakotova/lim-repo/repositories"
+)
+
+// noRows leaves the row count out of the span of a method that returns no
+// entities.
+const noRows = -1
+
+func found[T any](entity *T) int {
+	if entity == nil {
+		return 0
+	}
+
+	return 1
+}
+
+type ClientRepository struct {
+	next   repositories.ClientRepository
+	tracer *tracer
+}
+
+// NewClientRepository starts a span named ClientRepository.<method> for
+// every call of next.
+func NewClientRepository(next repositories.ClientRepository, opts ...Option) repositories.ClientRepository {
+	return &ClientRepository{next: next, tracer: newTracer(semconv.DBSystemOtherSQL, opts)}
+}
+
+func (c *ClientRepository) Create(ctx context.Context, client *models.Client) error {
+	ctx, span := c.tracer.startMethod(ctx, "ClientRepository.Create", "clients")
+	err := c.next.Create(ctx, client)
+	c.tracer.endMethod(span, noRows, err)
+
+	return err
+}
+
+func (c *ClientRepository) GetByID(ctx context.Context, id uint64) (*models.Client, error) {
+	ctx, span := c.tracer.startMethod(ctx, "ClientRepository.GetByID", "clients")
+	client, err := c.next.GetByID(ctx, id)
+	c.tracer.endMethod(span, found(client), err)
+
+	return client, err
+}
+
+func (c *ClientRepository) GetByTelephone(ctx context.Context, telephone string) (*models.Client, error) {
+	ctx, span := c.tracer.startMethod(ctx, "ClientRepository.GetByTelephone", "clients")
+	client, err := c.next.GetByTelephone(ctx, telephone)
+	c.tracer.endMethod(span, found(client), err)
+
+	return client, err
+}
+
+func (c *ClientRepository) GetByTraining(ctx context.Context, id uint64) ([]models.Client, error) {
+	ctx, span := c.tracer.startMethod(ctx, "ClientRepository.GetByTraining", "clients")
+	clients, err := c.next.GetByTraining(ctx, id)
+	c.tracer.endMethod(span, len(clients), err)
+
+	return clients, err
+}
+
+func (c *ClientRepository) CreateAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	ctx, span := c.tracer.startMethod(ctx, "ClientRepository.CreateAssignment", "clients_trainings")
+	err := c.next.CreateAssignment(ctx, clientID, trainingID)
+	c.tracer.endMethod(span, noRows, err)
+
+	return err
+}
+
+func (c *ClientRepository) DeleteAssignment(ctx context.Context, clientID, trainingID uint64) error {
+	ctx, span := c.tracer.startMethod(ctx, "ClientRepository.DeleteAssignment", "clients_trainings")
+	err := c.next.DeleteAssignment(ctx, clientID, trainingID)
+	c.tracer.endMethod(span, noRows, err)
+
+	return err
+}
+
+type CoachRepository struct {
+	next   repositories.CoachRepository
+	tracer *tracer
+}
+
+// NewCoachRepository starts a span named CoachRepository.<method> for every
+// call of next.
+func NewCoachRepository(next repositories.CoachRepository, opts ...Option) repositories.CoachRepository {
+	return &CoachRepository{next: next, tracer: newTracer(semconv.DBSystemOtherSQL, opts)}
+}
+
+func (c *CoachRepository) Create(ctx context.Context, coach *models.Coach) error {
+	ctx, span := c.tracer.startMethod(ctx, "CoachRepository.Create", "coaches")
+	err := c.next.Create(ctx, coach)
+	c.tracer.endMethod(span, noRows, err)
+
+	return err
+}
+
+func (c *CoachRepository) GetByID(ctx context.Context, id uint64) (*models.Coach, error) {
+	ctx, span := c.tracer.startMethod(ctx, "CoachRepository.GetByID", "coaches")
+	coach, err := c.next.GetByID(ctx, id)
+	c.tracer.endMethod(span, found(coach), err)
+
+	return coach, err
+}
+
+func (c *CoachRepository) GetByName(ctx context.Context, name string) (*models.Coach, error) {
+	ctx, span := c.tracer.startMethod(ctx, "CoachRepository.GetByName", "coaches")
+	coach, err := c.next.GetByName(ctx, name)
+	c.tracer.endMethod(span, found(coach), err)
+
+	return coach, err
+}
+
+func (c *CoachRepository) GetAll(ctx context.Context) ([]models.Coach, error) {
+	ctx, span := c.tracer.startMethod(ctx, "CoachRepository.GetAll", "coaches")
+	coaches, err := c.next.GetAll(ctx)
+	c.tracer.endMethod(span, len(coaches), err)
+
+	return coaches, err
+}
+
+type HallRepository struct {
+	next   repositories.HallRepository
+	tracer *tracer
+}
+
+// NewHallRepository starts a span named HallRepository.<method> for every
+// call of next.
+func NewHallRepository(next repositories.HallRepository, opts ...Option) repositories.HallRepository {
+	return newHallRepository(next, opts)
+}
+
+func newHallRepository(next repositories.HallRepository, opts []Option) *HallRepository {
+	return &HallRepository{next: next, tracer: newTracer(semconv.DBSystemOtherSQL, opts)}
+}
+
+func (h *HallRepository) Create(ctx context.Context, hall *models.Hall) error {
+	ctx, span := h.tracer.startMethod(ctx, "HallRepository.Create", "halls")
+	err := h.next.Create(ctx, hall)
+	h.tracer.endMethod(span, noRows, err)
+
+	return err
+}
+
+func (h *HallRepository) GetByID(ctx context.Context, id uint64) (*models.Hall, error) {
+	ctx, span := h.tracer.startMethod(ctx, "HallRepository.GetByID", "halls")
+	hall, err := h.next.GetByID(ctx, id)
+	h.tracer.endMethod(span, found(hall), err)
+
+	return hall, err
+}
+
+func (h *HallRepository) GetByNumber(ctx context.Context, number uint64) (*models.Hall, error) {
+	ctx, span := h.tracer.startMethod(ctx, "HallRepository.GetByNumber", "halls")
+	hall, err := h.next.GetByNumber(ctx, number)
+	h.tracer.endMethod(span, found(hall), err)
+
+	return hall, err
+}
+
+func (h *HallRepository) GetAll(ctx context.Context) (map[uint64]models.Hall, error) {
+	ctx, span := h.tracer.startMethod(ctx, "HallRepository.GetAll", "halls")
+	halls, err := h.next.GetAll(ctx)
+	h.tracer.endMethod(span, len(halls), err)
+
+	return halls, err
+}
+
+// ExtHallRepository also traces the methods of the extended hall repository.
+type ExtHallRepository struct {
+	*HallRepository
+	next extRepositories.HallRepository
+}
+
+// NewExtHallRepository is NewHallRepository for the extended hall
+// repository.
+func NewExtHallRepository(next extRepositories.HallRepository, opts ...Option) extRepositories.HallRepository {
+	return &ExtHallRepository{HallRepository: newHallRepository(next, opts), next: next}
+}
+
+func (h *ExtHallRepository) GetDetails(ctx context.Context, id uint64) (*extModels.HallDetails, error) {
+	ctx, span := h.tracer.startMethod(ctx, "HallRepository.GetDetails", "halls")
+	details, err := h.next.GetDetails(ctx, id)
+	h.tracer.endMethod(span, found(details), err)
+
+	return details, err
+}
+
+func (h *ExtHallRepository) UpdateDetails(ctx context.Context, details *extModels.HallDetails) error {
+	ctx, span := h.tracer.startMethod(ctx, "HallRepository.UpdateDetails", "halls")
+	err := h.next.UpdateDetails(ctx, details)
+	h.tracer.endMethod(span, noRows, err)
+
+	return err
+}
+
+func (h *ExtHallRepository) SetEquipment(ctx context.Context, id uint64, equipment extModels.Equipment, count uint64) error {
+	ctx, span := h.tracer.startMethod(ctx, "HallRepository.SetEquipment", "hall_equipment")
+	err := h.next.SetEquipment(ctx, id, equipment, count)
+	h.tracer.endMethod(span, noRows, err)
+
+	return err
+}
+
+func (h *ExtHallRepository) GetAllByEquipment(ctx context.Context, required map[extModels.Equipment]uint64) (map[uint64]models.Hall, error) {
+	ctx, span := h.tracer.startMethod(ctx, "HallRepository.GetAllByEquipment", "halls")
+	halls, err := h.next.GetAllByEquipment(ctx, required)
+	h.tracer.endMethod(span, len(halls), err)
+
+	return halls, err
+}
+
+type TrainingRepository struct {
+	next   repositories.TrainingRepository
+	tracer *tracer
+}
+
+// NewTrainingRepository starts a span named TrainingRepository.<method> for
+// every call of next.
+func NewTrainingRepository(next repositories.TrainingRepository, opts ...Option) repositories.TrainingRepository {
+	return newTrainingRepository(next, opts)
+}
+
+func newTrainingRepository(next repositories.TrainingRepository, opts []Option) *TrainingRepository {
+	return &TrainingRepository{next: next, tracer: newTracer(semconv.DBSystemOtherSQL, opts)}
+}
+
+func (t *TrainingRepository) Create(ctx context.Context, training *models.Training) error {
+	ctx, span := t.tracer.startMethod(ctx, "TrainingRepository.Create", "trainings")
+	err := t.next.Create(ctx, training)
+	t.tracer.endMethod(span, noRows, err)
+
+	return err
+}
+
+func (t *TrainingRepository) Delete(ctx context.Context, id uint64) error {
+	ctx, span := t.tracer.startMethod(ctx, "TrainingRepository.Delete", "trainings")
+	err := t.next.Delete(ctx, id)
+	t.tracer.endMethod(span, noRows, err)
+
+	return err
+}
+
+func (t *TrainingRepository) GetByID(ctx context.Context, id uint64) (*models.Training, error) {
+	ctx, span := t.tracer.startMethod(ctx, "TrainingRepository.GetByID", "trainings")
+	training, err := t.next.GetByID(ctx, id)
+	t.tracer.endMethod(span, found(training), err)
+
+	return training, err
+}
+
+func (t *TrainingRepository) GetAllByClient(ctx context.Context, id uint64) ([]models.Training, error) {
+	ctx, span := t.tracer.startMethod(ctx, "TrainingRepository.GetAllByClient", "trainings")
+	trainings, err := t.next.GetAllByClient(ctx, id)
+	t.tracer.endMethod(span, len(trainings), err)
+
+	return trainings, err
+}
+
+func (t *TrainingRepository) GetAllByCoachOnDate(ctx context.Context, id uint64, date time.Time) ([]models.Training, error) {
+	ctx, span := t.tracer.startMethod(ctx, "TrainingRepository.GetAllByCoachOnDate", "trainings")
+	trainings, err := t.next.GetAllByCoachOnDate(ctx, id, date)
+	t.tracer.endMethod(span, len(trainings), err)
+
+	return trainings, err
+}
+
+func (t *TrainingRepository) GetAllByDateTime(ctx context.Context, dateTime time.Time) ([]models.Training, error) {
+	ctx, span := t.tracer.startMethod(ctx, "TrainingRepository.GetAllByDateTime", "trainings")
+	trainings, err := t.next.GetAllByDateTime(ctx, dateTime)
+	t.tracer.endMethod(span, len(trainings), err)
+
+	return trainings, err
+}
+
+func (t *TrainingRepository) GetAllBetweenDateTime(ctx context.Context, start time.Time, end time.Time) ([]models.Training, error) {
+	ctx, span := t.tracer.startMethod(ctx, "TrainingRepository.GetAllBetweenDateTime", "trainings")
+	trainings, err := t.next.GetAllBetweenDateTime(ctx, start, end)
+	t.tracer.endMethod(span, len(trainings), err)
+
+	return trainings, err
+}
+
+func (t *TrainingRepository) ReduceAvailablePlacesNum(ctx context.Context, id uint64) error {
+	ctx, span := t.tracer.startMethod(ctx, "TrainingRepository.ReduceAvailablePlacesNum", "trainings")
+	err := t.next.ReduceAvailablePlacesNum(ctx, id)
+	t.tracer.endMethod(span, noRows, err)
+
+	return err
+}
+
+func (t *TrainingRepository) IncreaseAvailablePlacesNum(ctx context.Context, id uint64) error {
+	ctx, span := t.tracer.startMethod(ctx, "TrainingRepository.IncreaseAvailablePlacesNum", "trainings")
+	err := t.next.IncreaseAvailablePlacesNum(ctx, id)
+	t.tracer.endMethod(span, noRows, err)
+
+	return err
+}
+
+// ExtTrainingRepository also traces the methods of the extended training
+// repository.
+type ExtTrainingRepository struct {
+	*TrainingRepository
+	next extRepositories.TrainingRepository
+}
+
+// NewExtTrainingRepository is NewTrainingRepository for the extended
+// training repository.
+func NewExtTrainingRepository(next extRepositories.TrainingRepository, opts ...Option) extRepositories.TrainingRepository {
+	return &ExtTrainingRepository{TrainingRepository: newTrainingRepository(next, opts), next: next}
+}
+
+func (t *ExtTrainingRepository) Reschedule(ctx context.Context, id uint64, dateTime time.Time) error {
+	ctx, span := t.tracer.startMethod(ctx, "TrainingRepository.Reschedule", "trainings")
+	err := t.next.Reschedule(ctx, id, dateTime)
+	t.tracer.endMethod(span, noRows, err)
+
+	return err
+}
+
+func (t *ExtTrainingRepository) AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error) {
+	ctx, span := t.tracer.startMethod(ctx, "TrainingRepository.AvailablePlacesNum", "trainings")
+	available, err := t.next.AvailablePlacesNum(ctx, id)
+	t.tracer.endMethod(span, noRows, err)
+
+	return available, err
+}
@@ -0,0 +1,131 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/nkarakotova/lim-core/repositories"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+
+	"github.com/nkarakotova/lim-repo/inmemory"
+	postgreSQLObjectMother "github.com/nkarakotova/lim-repo/postgreSQL/object_mothers"
+	"github.com/nkarakotova/lim-repo/tracing"
+
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+)
+
+type RepositorySuite struct {
+	suite.Suite
+	exporter *tracetest.InMemoryExporter
+	provider *sdktrace.TracerProvider
+	storage  *inmemory.Storage
+	ctx      context.Context
+}
+
+func (s *RepositorySuite) BeforeEach(t provider.T) {
+	s.exporter = tracetest.NewInMemoryExporter()
+	s.provider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(s.exporter))
+	s.storage = inmemory.NewStorage()
+	s.ctx = context.Background()
+}
+
+func (s *RepositorySuite) AfterEach(t provider.T) {
+	s.provider.Shutdown(s.ctx)
+}
+
+func (s *RepositorySuite) options() []tracing.Option {
+	return []tracing.Option{tracing.WithTracerProvider(s.provider), tracing.WithDBSystem(semconv.DBSystemPostgreSQL)}
+}
+
+func (s *RepositorySuite) TestMethodSpan(t provider.T) {
+	t.Title("Repository: A method span carries the table and row count")
+	t.Tags("Tracing")
+	t.WithNewStep("Success", func(sCtx provider.StepCtx) {
+		repository := tracing.NewCoachRepository(inmemory.NewCoachInMemoryRepository(s.storage), s.options()...)
+		sCtx.Require().NoError(repository.Create(s.ctx, postgreSQLObjectMother.NewCoach().Build()))
+		sCtx.Require().NoError(repository.Create(s.ctx, postgreSQLObjectMother.NewCoach().Build()))
+
+		coaches, err := repository.GetAll(s.ctx)
+		sCtx.Require().NoError(err)
+		sCtx.Require().Len(coaches, 2)
+
+		spans := s.exporter.GetSpans()
+		sCtx.Require().Len(spans, 3)
+		getAll := span(spans, "CoachRepository.GetAll")
+		sCtx.Require().NotNil(getAll)
+		sCtx.Assert().Equal("postgresql", value(getAll, semconv.DBSystemKey).AsString())
+		sCtx.Assert().Equal("coaches", value(getAll, semconv.DBSQLTableKey).AsString())
+		sCtx.Assert().Equal(int64(2), value(getAll, tracing.RowsReturnedKey).AsInt64())
+		sCtx.Assert().Equal(codes.Unset, getAll.Status.Code)
+	})
+}
+
+func (s *RepositorySuite) TestMethodSpanError(t provider.T) {
+	t.Title("Repository: A failed method carries the error type")
+	t.Tags("Tracing")
+	t.WithNewStep("Failure", func(sCtx provider.StepCtx) {
+		repository := tracing.NewClientRepository(inmemory.NewClientInMemoryRepository(s.storage), s.options()...)
+
+		_, err := repository.GetByID(s.ctx, 42)
+		sCtx.Require().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		getByID := span(s.exporter.GetSpans(), "ClientRepository.GetByID")
+		sCtx.Require().NotNil(getByID)
+		sCtx.Assert().Equal(codes.Error, getByID.Status.Code)
+		sCtx.Assert().Equal("EntityDoesNotExists", value(getByID, semconv.ErrorTypeKey).AsString())
+		sCtx.Assert().False(value(getByID, tracing.RowsReturnedKey).AsBool())
+	})
+}
+
+// placesRepository is the in-memory training repository, which counts the
+// available places.
+type placesRepository interface {
+	repositories.TrainingRepository
+	AvailablePlacesNum(ctx context.Context, id uint64) (uint64, error)
+}
+
+// rescheduler extends the in-memory training repository with Reschedule.
+type rescheduler struct {
+	placesRepository
+	rescheduled uint64
+}
+
+func (r *rescheduler) Reschedule(_ context.Context, id uint64, _ time.Time) error {
+	r.rescheduled = id
+
+	return nil
+}
+
+func (s *RepositorySuite) TestExtMethodSpan(t provider.T) {
+	t.Title("Repository: The extended methods are traced as well")
+	t.Tags("Tracing")
+	t.WithNewStep("Reschedule", func(sCtx provider.StepCtx) {
+		next := &rescheduler{placesRepository: inmemory.NewTrainingInMemoryRepository(s.storage).(placesRepository)}
+		repository := tracing.NewExtTrainingRepository(next, s.options()...)
+
+		err := repository.Reschedule(s.ctx, 7, time.Now())
+		sCtx.Require().NoError(err)
+
+		sCtx.Assert().Equal(uint64(7), next.rescheduled)
+		sCtx.Assert().NotNil(span(s.exporter.GetSpans(), "TrainingRepository.Reschedule"))
+	})
+	t.WithNewStep("AvailablePlacesNum", func(sCtx provider.StepCtx) {
+		next := &rescheduler{placesRepository: inmemory.NewTrainingInMemoryRepository(s.storage).(placesRepository)}
+		repository := tracing.NewExtTrainingRepository(next, s.options()...)
+
+		_, err := repository.AvailablePlacesNum(s.ctx, 7)
+		sCtx.Require().ErrorIs(err, repositoriesErrors.EntityDoesNotExists)
+
+		sCtx.Assert().NotNil(span(s.exporter.GetSpans(), "TrainingRepository.AvailablePlacesNum"))
+	})
+}
+
+func TestRepositorySuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(RepositorySuite))
+}
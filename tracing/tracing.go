@@ -0,0 +1,296 @@
+// Package tracing emits OpenTelemetry spans for the repositories.
+//
+// NewHook is the sqlhook of the database/sql driver, so every statement the
+// sqlx repositories run becomes a span carrying db.system, the sanitized
+// db.statement, the table, the row count and the error type. The
+// constructors of this package decorate the client, coach, hall and training
+// repositories with a span per method call. The context flows through both,
+// so inside a trmsqlx transaction the statement spans are children of the
+// repository span that ran them:
+//
+//	db, err := tracing.OpenDB("pgx", dsn)
+//	dbx := sqlx.NewDb(db, "pgx")
+//	clients := tracing.NewClientRepository(postgreSQL.NewClientPostgreSQLRepository(dbx),
+//		tracing.WithDBSystem(semconv.DBSystemPostgreSQL))
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+)
+
+const instrumentationName = "github.com/nkarakotova/lim-repo/tracing"
+
+var (
+	// RowsReturnedKey is the number of rows a query or a repository method
+	// returned.
+	RowsReturnedKey = attribute.Key("db.rows_returned")
+	// RowsAffectedKey is the number of rows a statement changed.
+	RowsAffectedKey = attribute.Key("db.rows_affected")
+)
+
+type config struct {
+	provider trace.TracerProvider
+	system   attribute.KeyValue
+}
+
+// Option configures NewHook, OpenDB and the repository constructors.
+type Option func(*config)
+
+// WithTracerProvider sets the provider of the tracer. The global one is used
+// by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithDBSystem sets db.system of the spans, e.g. semconv.DBSystemPostgreSQL.
+// NewHook derives it from the driver name by default, the repositories use
+// other_sql.
+func WithDBSystem(system attribute.KeyValue) Option {
+	return func(c *config) {
+		c.system = system
+	}
+}
+
+type tracer struct {
+	tracer trace.Tracer
+	system attribute.KeyValue
+
+	// statements caches the sanitized statement, operation and table of a
+	// query, the repositories run a handful of distinct ones.
+	statements sync.Map
+}
+
+func newTracer(system attribute.KeyValue, opts []Option) *tracer {
+	c := &config{provider: otel.GetTracerProvider(), system: system}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return &tracer{
+		tracer: c.provider.Tracer(instrumentationName),
+		system: c.system,
+	}
+}
+
+// startMethod starts the span of a repository method.
+func (t *tracer) startMethod(ctx context.Context, name, table string) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(t.system, semconv.DBSQLTableKey.String(table)))
+}
+
+// endMethod ends the span of a repository method. rows is the number of
+// returned entities, it is left out when negative.
+func (t *tracer) endMethod(span trace.Span, rows int, err error) {
+	if rows >= 0 && err == nil {
+		span.SetAttributes(RowsReturnedKey.Int(rows))
+	}
+	end(span, err)
+}
+
+type statement struct {
+	text      string
+	operation string
+	table     string
+}
+
+func (t *tracer) statement(query string) statement {
+	if cached, ok := t.statements.Load(query); ok {
+		return cached.(statement)
+	}
+
+	text := sanitize(query)
+	operation, table := parseStatement(text)
+	s := statement{text: text, operation: operation, table: table}
+	t.statements.Store(query, s)
+
+	return s
+}
+
+// startStatement starts the span of a statement that began at start, the
+// hooks are called once it finished.
+func (t *tracer) startStatement(ctx context.Context, query string, start time.Time) trace.Span {
+	s := t.statement(query)
+
+	attributes := []attribute.KeyValue{t.system, semconv.DBStatementKey.String(s.text)}
+	name := s.operation
+	if s.operation != "" {
+		attributes = append(attributes, semconv.DBOperationKey.String(s.operation))
+	}
+	if s.table != "" {
+		attributes = append(attributes, semconv.DBSQLTableKey.String(s.table))
+		name += " " + s.table
+	}
+	if name == "" {
+		name = "query"
+	}
+
+	_, span := t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(attributes...))
+
+	return span
+}
+
+// startCommand starts the span of BEGIN, COMMIT or ROLLBACK.
+func (t *tracer) startCommand(ctx context.Context, command string, start time.Time) trace.Span {
+	_, span := t.tracer.Start(ctx, command,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(t.system, semconv.DBOperationKey.String(command)))
+
+	return span
+}
+
+func end(span trace.Span, err error, opts ...trace.SpanEndOption) {
+	if err != nil {
+		span.SetAttributes(semconv.ErrorTypeKey.String(errorType(err)))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(opts...)
+}
+
+var sentinelErrors = []struct {
+	err  error
+	name string
+}{
+	{repositoriesErrors.EntityDoesNotExists, "EntityDoesNotExists"},
+	{extRepositoriesErrors.EntityAlreadyExists, "EntityAlreadyExists"},
+	{extRepositoriesErrors.NoAvailablePlacesNum, "NoAvailablePlacesNum"},
+	{extRepositoriesErrors.CoachNotAvailable, "CoachNotAvailable"},
+	{extRepositoriesErrors.PlacesNumMoreThenCapacity, "PlacesNumMoreThenCapacity"},
+	{extRepositoriesErrors.HallClosed, "HallClosed"},
+	{extRepositoriesErrors.DatabaseNotEmpty, "DatabaseNotEmpty"},
+	{extRepositoriesErrors.DatabaseUnavailable, "DatabaseUnavailable"},
+	{extRepositoriesErrors.QueryCanceled, "QueryCanceled"},
+	{extRepositoriesErrors.QueryTimeout, "QueryTimeout"},
+	{sql.ErrNoRows, "sql.ErrNoRows"},
+	{sql.ErrTxDone, "sql.ErrTxDone"},
+	{context.Canceled, "context.Canceled"},
+	{context.DeadlineExceeded, "context.DeadlineExceeded"},
+}
+
+// errorType is a low-cardinality name of err: the name of a repository
+// error, the SQLSTATE of a Postgres error or the Go type otherwise.
+func errorType(err error) string {
+	for _, sentinel := range sentinelErrors {
+		if errors.Is(err, sentinel.err) {
+			return sentinel.name
+		}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+
+	return fmt.Sprintf("%T", err)
+}
+
+// sanitize replaces the string and numeric literals of query with ? and
+// collapses whitespace. The bind parameters are kept, their values are
+// never recorded.
+func sanitize(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	space := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = b.Len() > 0
+			continue
+		case space:
+			b.WriteByte(' ')
+			space = false
+		}
+
+		switch {
+		case c == '\'':
+			for i++; i < len(query); i++ {
+				if query[i] == '\'' {
+					if i+1 < len(query) && query[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			b.WriteByte('?')
+		case isDigit(c) && (i == 0 || !isIdentifier(query[i-1]) && query[i-1] != '$'):
+			for i+1 < len(query) && (isDigit(query[i+1]) || query[i+1] == '.') {
+				i++
+			}
+			b.WriteByte('?')
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// parseStatement returns the operation of a sanitized statement and the
+// first table it reads or writes.
+func parseStatement(text string) (operation, table string) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return r > 0x7f || !isIdentifier(byte(r)) && r != '.'
+	})
+	if len(words) == 0 {
+		return "", ""
+	}
+	operation = strings.ToUpper(words[0])
+
+	keyword := "from"
+	switch operation {
+	case "INSERT":
+		keyword = "into"
+	case "UPDATE":
+		keyword = "update"
+	}
+
+	for i, word := range words[:len(words)-1] {
+		if word != keyword {
+			continue
+		}
+		next := words[i+1]
+		if next == "only" && i+2 < len(words) {
+			next = words[i+2]
+		}
+		if next != "select" && next != "lateral" && next != "unnest" {
+			return operation, next
+		}
+	}
+
+	return operation, ""
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifier(c byte) bool {
+	return c == '_' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
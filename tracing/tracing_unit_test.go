@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/nkarakotova/lim-core/errors/repositoriesErrors"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+
+	extRepositoriesErrors "github.com/nkarakotova/lim-repo/errors/repositoriesErrors"
+)
+
+type StatementSuite struct {
+	suite.Suite
+}
+
+func (s *StatementSuite) TestSanitizeLiterals(t provider.T) {
+	t.Title("Sanitize: String and numeric literals are replaced")
+	t.Tags("Tracing")
+	t.WithNewStep("Literals", func(sCtx provider.StepCtx) {
+		sCtx.Assert().Equal(
+			`select * from clients where telephone=? and mail=? limit ?;`,
+			sanitize("select *\n\tfrom clients\n\twhere telephone='+79990000000' and mail='o''neil@mail.ru' limit 10;"))
+		sCtx.Assert().Equal(
+			`update halls set area=nullif($3::numeric, ?) where hall_id=$1 and area > ?;`,
+			sanitize(`update halls set area=nullif($3::numeric, 0) where hall_id=$1 and area > 12.5;`))
+	})
+}
+
+func (s *StatementSuite) TestSanitizeKeepsIdentifiers(t provider.T) {
+	t.Title("Sanitize: Bind parameters and identifiers with digits are kept")
+	t.Tags("Tracing")
+	t.WithNewStep("Identifiers", func(sCtx provider.StepCtx) {
+		query := `select t1.training_id from trainings t1 where t1.coach_id=$12;`
+
+		sCtx.Assert().Equal(query, sanitize("  "+query+"\n"))
+	})
+}
+
+func (s *StatementSuite) TestParseStatement(t provider.T) {
+	t.Title("ParseStatement: Operation and table")
+	t.Tags("Tracing")
+	t.WithNewStep("Statements", func(sCtx provider.StepCtx) {
+		for _, tc := range []struct {
+			text, operation, table string
+		}{
+			{`select * from trainings where training_id=$1;`, "SELECT", "trainings"},
+			{`insert into clients_trainings(client_id, training_id) values($1, $2);`, "INSERT", "clients_trainings"},
+			{`update trainings set available_places_num = available_places_num - ? where training_id=$1;`, "UPDATE", "trainings"},
+			{`delete from only public.halls where hall_id=$1;`, "DELETE", "public.halls"},
+			{`select count(*) from (select * from coaches) c;`, "SELECT", "coaches"},
+			{`analyze;`, "ANALYZE", ""},
+			{``, "", ""},
+		} {
+			operation, table := parseStatement(tc.text)
+
+			sCtx.Assert().Equal(tc.operation, operation, tc.text)
+			sCtx.Assert().Equal(tc.table, table, tc.text)
+		}
+	})
+}
+
+func (s *StatementSuite) TestErrorType(t provider.T) {
+	t.Title("ErrorType: Low-cardinality names of errors")
+	t.Tags("Tracing")
+	t.WithNewStep("Errors", func(sCtx provider.StepCtx) {
+		sCtx.Assert().Equal("EntityDoesNotExists", errorType(repositoriesErrors.EntityDoesNotExists))
+		sCtx.Assert().Equal("NoAvailablePlacesNum", errorType(fmt.Errorf("booking: %w", extRepositoriesErrors.NoAvailablePlacesNum)))
+		sCtx.Assert().Equal("23505", errorType(&pgconn.PgError{Code: "23505"}))
+		sCtx.Assert().Equal("*errors.errorString", errorType(errors.New("boom")))
+	})
+}
+
+func TestStatementSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(StatementSuite))
+}